@@ -0,0 +1,106 @@
+package sarama
+
+import "time"
+
+// AddRaftVoterRequest is sent to the active controller to add a voter to the
+// KRaft metadata quorum (KIP-853).
+type AddRaftVoterRequest struct {
+	Version int16
+
+	// ClusterID is the cluster id, or null to skip the check
+	ClusterID *string
+
+	// Timeout is how long to wait for the voter to be added
+	Timeout time.Duration
+
+	// VoterID is the replica id of the voter getting added to the topic
+	// partition
+	VoterID int32
+
+	// VoterDirectoryID is the directory id of the voter getting added to the
+	// topic partition
+	VoterDirectoryID Uuid
+
+	// Listeners contains the endpoints that can be used to communicate with
+	// the voter
+	Listeners []RaftVoterListener
+}
+
+func (r *AddRaftVoterRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *AddRaftVoterRequest) encode(pe packetEncoder) error {
+	if err := pe.putNullableString(r.ClusterID); err != nil {
+		return err
+	}
+
+	pe.putDurationMs(r.Timeout)
+	pe.putInt32(r.VoterID)
+
+	if err := pe.putUuid(r.VoterDirectoryID); err != nil {
+		return err
+	}
+
+	if err := encodeRaftVoterListeners(pe, r.Listeners); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *AddRaftVoterRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ClusterID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.Timeout, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.VoterID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.VoterDirectoryID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if r.Listeners, err = decodeRaftVoterListeners(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *AddRaftVoterRequest) key() int16 {
+	return apiKeyAddRaftVoter
+}
+
+func (r *AddRaftVoterRequest) version() int16 {
+	return r.Version
+}
+
+func (r *AddRaftVoterRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *AddRaftVoterRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *AddRaftVoterRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *AddRaftVoterRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *AddRaftVoterRequest) requiredVersion() KafkaVersion {
+	return V3_9_0_0
+}
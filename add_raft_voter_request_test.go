@@ -0,0 +1,33 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var addRaftVoterRequestV0 = []byte{
+	4, 'c', 'i', 'd', // ClusterID
+	0, 0, 0x75, 0x30, // TimeoutMs
+	0, 0, 0, 4, // VoterID
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // VoterDirectoryID
+	2,                                                    // Listeners
+	11, 'C', 'O', 'N', 'T', 'R', 'O', 'L', 'L', 'E', 'R', // Name
+	10, 'l', 'o', 'c', 'a', 'l', 'h', 'o', 's', 't', // Host
+	0x23, 0x83, // Port
+	0, // empty tagged fields
+	0, // empty tagged fields
+}
+
+func TestAddRaftVoterRequest(t *testing.T) {
+	clusterID := "cid"
+	request := &AddRaftVoterRequest{
+		ClusterID:        &clusterID,
+		Timeout:          30 * time.Second,
+		VoterID:          4,
+		VoterDirectoryID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		Listeners:        []RaftVoterListener{{Name: "CONTROLLER", Host: "localhost", Port: 9091}},
+	}
+	testRequest(t, "v0", request, addRaftVoterRequestV0)
+}
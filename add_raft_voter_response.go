@@ -0,0 +1,83 @@
+package sarama
+
+import "time"
+
+type AddRaftVoterResponse struct {
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+
+	// ErrorMessage is the top-level error message, or null if there was no
+	// error
+	ErrorMessage *string
+}
+
+func (r *AddRaftVoterResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *AddRaftVoterResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.ErrorCode)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *AddRaftVoterResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *AddRaftVoterResponse) key() int16 {
+	return apiKeyAddRaftVoter
+}
+
+func (r *AddRaftVoterResponse) version() int16 {
+	return r.Version
+}
+
+func (r *AddRaftVoterResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *AddRaftVoterResponse) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *AddRaftVoterResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *AddRaftVoterResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *AddRaftVoterResponse) requiredVersion() KafkaVersion {
+	return V3_9_0_0
+}
+
+func (r *AddRaftVoterResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
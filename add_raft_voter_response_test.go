@@ -0,0 +1,16 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var addRaftVoterResponseV0 = []byte{
+	0, 0, 0, 0, // ThrottleTimeMs
+	0, 124, // ErrorCode (DUPLICATE_VOTER)
+	0, // ErrorMessage
+	0, // empty tagged fields
+}
+
+func TestAddRaftVoterResponse(t *testing.T) {
+	testResponse(t, "v0", &AddRaftVoterResponse{ErrorCode: ErrDuplicateVoter}, addRaftVoterResponseV0)
+}
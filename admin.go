@@ -167,6 +167,18 @@ type ClusterAdmin interface {
 	// Get information about all log directories on the given set of brokers
 	DescribeLogDirs(brokers []int32) (map[int32][]DescribeLogDirsResponseDirMetadata, error)
 
+	// Move the given replicas to the given log directories of their brokers
+	// (KIP-113). A replica is first copied to a future replica in the
+	// destination directory, which replaces the current replica once it has
+	// caught up; use DescribeReplicaLogDirs to follow the move.
+	// This operation is supported by brokers with version 1.0.0.0 or higher.
+	AlterReplicaLogDirs(replicaAssignment map[TopicPartitionReplica]string) error
+
+	// Get the current and, while a move is in progress, the future log
+	// directory of the given replicas.
+	// This operation is supported by brokers with version 1.0.0.0 or higher.
+	DescribeReplicaLogDirs(replicas []TopicPartitionReplica) (map[TopicPartitionReplica]ReplicaLogDirInfo, error)
+
 	// Get information about SCRAM users
 	DescribeUserScramCredentials(users []string) ([]*DescribeUserScramCredentialsResult, error)
 
@@ -188,6 +200,42 @@ type ClusterAdmin interface {
 	// This operation is supported by brokers with version 2.6.0.0 or higher.
 	AlterClientQuotas(entity []QuotaEntityComponent, op ClientQuotasOp, validateOnly bool) error
 
+	// Create a delegation token (KIP-48) which can be used to authenticate
+	// over SASL/SCRAM, see Net.SASL.SCRAMTokenAuth.
+	// This operation is supported by brokers with version 1.1.0.0 or higher.
+	CreateDelegationToken(options *CreateDelegationTokenOptions) (*DelegationToken, error)
+
+	// Renew the delegation token with the given HMAC, extending its expiry
+	// time by renewPeriod (or the broker's default if renewPeriod is not
+	// positive), and return its new expiry timestamp.
+	// This operation is supported by brokers with version 1.1.0.0 or higher.
+	RenewDelegationToken(hmac []byte, renewPeriod time.Duration) (time.Time, error)
+
+	// Expire the delegation token with the given HMAC after expiryTimePeriod,
+	// or immediately if expiryTimePeriod is not positive, and return its new
+	// expiry timestamp.
+	// This operation is supported by brokers with version 1.1.0.0 or higher.
+	ExpireDelegationToken(hmac []byte, expiryTimePeriod time.Duration) (time.Time, error)
+
+	// Describe the delegation tokens owned by the given principals, or all the
+	// tokens the caller is allowed to describe if owners is empty.
+	// This operation is supported by brokers with version 1.1.0.0 or higher.
+	DescribeDelegationTokens(owners []DelegationTokenPrincipal) ([]DelegationToken, error)
+
+	// Describe the KRaft metadata quorum: its leader and epoch, the high
+	// watermark of the metadata log, and the replication lag of every voter
+	// and observer.
+	// This operation is supported by KRaft clusters with version 3.3.0.0 or
+	// higher.
+	DescribeMetadataQuorum() (*QuorumInfo, error)
+
+	// Unregister the broker with the given ID, removing a decommissioned
+	// broker from the cluster metadata. It does not reassign the broker's
+	// partitions.
+	// This operation is supported by KRaft clusters with version 3.1.0.0 or
+	// higher.
+	UnregisterBroker(brokerID int32) error
+
 	// Controller returns the cluster controller broker. It will return a
 	// locally cached value if it's available.
 	Controller() (*Broker, error)
@@ -368,14 +416,21 @@ func (ca *clusterAdmin) CreateTopic(topic string, detail *TopicDetail, validateO
 }
 
 func (ca *clusterAdmin) DescribeTopics(topics []string) (metadata []*TopicMetadata, err error) {
-	var response *MetadataResponse
 	err = ca.retryOnError(isRetriableControllerError, func() error {
 		controller, err := ca.Controller()
 		if err != nil {
 			return err
 		}
-		request := NewMetadataRequest(ca.conf.Version, topics)
-		response, err = controller.GetMetadata(request)
+		if ca.supportsDescribeTopicPartitions(controller) {
+			metadata, err = ca.describeTopicPartitions(controller, topics)
+		} else {
+			var response *MetadataResponse
+			request := NewMetadataRequest(ca.conf.Version, topics)
+			response, err = controller.GetMetadata(request)
+			if err == nil {
+				metadata = response.Topics
+			}
+		}
 		if isRetriableControllerError(err) {
 			_, _ = ca.refreshController()
 		}
@@ -384,7 +439,64 @@ func (ca *clusterAdmin) DescribeTopics(topics []string) (metadata []*TopicMetada
 	if err != nil {
 		return nil, err
 	}
-	return response.Topics, nil
+	return metadata, nil
+}
+
+// supportsDescribeTopicPartitions reports whether topic metadata can be
+// fetched from b using the paginated DescribeTopicPartitions API (KIP-966).
+func (ca *clusterAdmin) supportsDescribeTopicPartitions(b *Broker) bool {
+	return ca.conf.Version.IsAtLeast(V3_8_0_0) && b.supportsAPIKey(apiKeyDescribeTopicPartitions)
+}
+
+// describeTopicPartitions fetches the metadata of the given topics, or of all
+// topics if none are given, following the response cursor until every page
+// has been read. Partitions of a topic that is split across pages are merged
+// into a single TopicMetadata.
+func (ca *clusterAdmin) describeTopicPartitions(b *Broker, topics []string) ([]*TopicMetadata, error) {
+	request := NewDescribeTopicPartitionsRequest(topics)
+	var metadata []*TopicMetadata
+	byName := make(map[string]*TopicMetadata)
+	for {
+		response, err := b.DescribeTopicPartitions(request)
+		if err != nil {
+			return nil, err
+		}
+		for _, topic := range response.Topics {
+			var name string
+			if topic.Name != nil {
+				name = *topic.Name
+			}
+			tm, ok := byName[name]
+			if !ok {
+				tm = &TopicMetadata{
+					Err:                       topic.ErrorCode,
+					Name:                      name,
+					Uuid:                      topic.TopicID,
+					IsInternal:                topic.IsInternal,
+					TopicAuthorizedOperations: topic.TopicAuthorizedOperations,
+				}
+				byName[name] = tm
+				metadata = append(metadata, tm)
+			}
+			for _, p := range topic.Partitions {
+				tm.Partitions = append(tm.Partitions, &PartitionMetadata{
+					Err:                    p.ErrorCode,
+					ID:                     p.PartitionIndex,
+					Leader:                 p.LeaderID,
+					LeaderEpoch:            p.LeaderEpoch,
+					Replicas:               p.ReplicaNodes,
+					Isr:                    p.IsrNodes,
+					OfflineReplicas:        p.OfflineReplicas,
+					EligibleLeaderReplicas: p.EligibleLeaderReplicas,
+					LastKnownELR:           p.LastKnownELR,
+				})
+			}
+		}
+		if response.NextCursor == nil {
+			return metadata, nil
+		}
+		request.Cursor = response.NextCursor
+	}
 }
 
 func (ca *clusterAdmin) DescribeCluster() (brokers []*Broker, controllerID int32, err error) {
@@ -494,22 +606,31 @@ func (ca *clusterAdmin) findAnyBroker() (*Broker, error) {
 
 func (ca *clusterAdmin) ListTopics() (map[string]TopicDetail, error) {
 	// In order to build TopicDetails we need to first get the list of all
-	// topics using a MetadataRequest and then get their configs using a
+	// topics using a MetadataRequest (or a DescribeTopicPartitionsRequest on
+	// brokers that support it) and then get their configs using a
 	// DescribeConfigsRequest request. To avoid sending many requests to the
 	// broker, we use a single DescribeConfigsRequest.
 
 	var topicsDetailsMap map[string]TopicDetail
 
 	if err := ca.retryOnError(isRetriableListTopicsError, func() error {
-		// Send the all-topic MetadataRequest
+		// Fetch the metadata of all topics
 		b, err := ca.findAnyBroker()
 		if err != nil {
 			return err
 		}
 		_ = b.Open(ca.client.Config())
 
-		metadataReq := NewMetadataRequest(ca.conf.Version, nil)
-		metadataResp, err := b.GetMetadata(metadataReq)
+		var topicsMetadata []*TopicMetadata
+		if ca.supportsDescribeTopicPartitions(b) {
+			topicsMetadata, err = ca.describeTopicPartitions(b, nil)
+		} else {
+			var metadataResp *MetadataResponse
+			metadataResp, err = b.GetMetadata(NewMetadataRequest(ca.conf.Version, nil))
+			if err == nil {
+				topicsMetadata = metadataResp.Topics
+			}
+		}
 		if err != nil {
 			if isTimeoutError(err) {
 				_ = b.Close()
@@ -517,10 +638,10 @@ func (ca *clusterAdmin) ListTopics() (map[string]TopicDetail, error) {
 			return err
 		}
 
-		currentTopicsDetailsMap := make(map[string]TopicDetail, len(metadataResp.Topics))
-		describeConfigsResources := make([]*ConfigResource, 0, len(metadataResp.Topics))
+		currentTopicsDetailsMap := make(map[string]TopicDetail, len(topicsMetadata))
+		describeConfigsResources := make([]*ConfigResource, 0, len(topicsMetadata))
 
-		for _, topic := range metadataResp.Topics {
+		for _, topic := range topicsMetadata {
 			topicDetails := TopicDetail{
 				NumPartitions: int32(len(topic.Partitions)),
 			}
@@ -1517,16 +1638,7 @@ func (ca *clusterAdmin) DescribeLogDirs(brokerIds []int32) (allLogDirs map[int32
 			defer wg.Done()
 			_ = b.Open(conf) // Ensure that broker is opened
 
-			request := &DescribeLogDirsRequest{}
-			if ca.conf.Version.IsAtLeast(V3_3_0_0) {
-				request.Version = 4
-			} else if ca.conf.Version.IsAtLeast(V3_2_0_0) {
-				request.Version = 3
-			} else if ca.conf.Version.IsAtLeast(V2_6_0_0) {
-				request.Version = 2
-			} else if ca.conf.Version.IsAtLeast(V2_0_0_0) {
-				request.Version = 1
-			}
+			request := NewDescribeLogDirsRequest(ca.conf.Version)
 			response, err := b.DescribeLogDirs(request)
 			if err != nil {
 				errChan <- err
@@ -1554,6 +1666,180 @@ func (ca *clusterAdmin) DescribeLogDirs(brokerIds []int32) (allLogDirs map[int32
 	return
 }
 
+// TopicPartitionReplica identifies the replica of a partition hosted by a
+// broker.
+type TopicPartitionReplica struct {
+	Topic     string
+	Partition int32
+	BrokerID  int32
+}
+
+// ReplicaLogDirInfo describes the log directories of a replica.
+type ReplicaLogDirInfo struct {
+	// CurrentReplicaLogDir is the log directory of the current replica, or
+	// empty if the broker does not host the replica.
+	CurrentReplicaLogDir string
+	// CurrentReplicaOffsetLag is the lag of the current replica's log end
+	// offset behind the partition's high watermark.
+	CurrentReplicaOffsetLag int64
+	// FutureReplicaLogDir is the log directory the replica is being moved to,
+	// or empty if no move is in progress.
+	FutureReplicaLogDir string
+	// FutureReplicaOffsetLag is the lag of the future replica's log end offset
+	// behind the current replica's.
+	FutureReplicaOffsetLag int64
+}
+
+func (ca *clusterAdmin) AlterReplicaLogDirs(replicaAssignment map[TopicPartitionReplica]string) error {
+	// Group the replicas by broker, then by destination log dir and topic.
+	dirsPerBroker := make(map[int32]map[string]map[string][]int32)
+	for replica, dir := range replicaAssignment {
+		if dirsPerBroker[replica.BrokerID] == nil {
+			dirsPerBroker[replica.BrokerID] = make(map[string]map[string][]int32)
+		}
+		if dirsPerBroker[replica.BrokerID][dir] == nil {
+			dirsPerBroker[replica.BrokerID][dir] = make(map[string][]int32)
+		}
+		dirsPerBroker[replica.BrokerID][dir][replica.Topic] = append(dirsPerBroker[replica.BrokerID][dir][replica.Topic], replica.Partition)
+	}
+
+	errChan := make(chan error, len(dirsPerBroker))
+	wg := sync.WaitGroup{}
+
+	for id, dirs := range dirsPerBroker {
+		broker, err := ca.findBroker(id)
+		if err != nil {
+			errChan <- err
+			continue
+		}
+
+		request := NewAlterReplicaLogDirsRequest(ca.conf.Version)
+		for path, topics := range dirs {
+			dir := AlterReplicaLogDirsRequestDir{Path: path}
+			for topic, partitions := range topics {
+				dir.Topics = append(dir.Topics, AlterReplicaLogDirsRequestTopic{Name: topic, Partitions: partitions})
+			}
+			request.Dirs = append(request.Dirs, dir)
+		}
+
+		wg.Go(func() {
+			_ = broker.Open(ca.conf) // Ensure that broker is opened
+
+			response, err := broker.AlterReplicaLogDirs(request)
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			var errs []error
+			for _, topic := range response.Results {
+				for _, partition := range topic.Partitions {
+					if !errors.Is(partition.ErrorCode, ErrNoError) {
+						errs = append(errs, fmt.Errorf("alter log dir of partition %d of topic %s on broker %d: %w",
+							partition.PartitionIndex, topic.TopicName, broker.ID(), partition.ErrorCode))
+					}
+				}
+			}
+			errChan <- errors.Join(errs...)
+		})
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (ca *clusterAdmin) DescribeReplicaLogDirs(replicas []TopicPartitionReplica) (map[TopicPartitionReplica]ReplicaLogDirInfo, error) {
+	partitionsPerBroker := make(map[int32]map[string][]int32)
+	for _, replica := range replicas {
+		if partitionsPerBroker[replica.BrokerID] == nil {
+			partitionsPerBroker[replica.BrokerID] = make(map[string][]int32)
+		}
+		partitionsPerBroker[replica.BrokerID][replica.Topic] = append(partitionsPerBroker[replica.BrokerID][replica.Topic], replica.Partition)
+	}
+
+	type result struct {
+		id      int32
+		logDirs []DescribeLogDirsResponseDirMetadata
+	}
+	results := make(chan result, len(partitionsPerBroker))
+	errChan := make(chan error, len(partitionsPerBroker))
+	wg := sync.WaitGroup{}
+
+	for id, topics := range partitionsPerBroker {
+		broker, err := ca.findBroker(id)
+		if err != nil {
+			errChan <- err
+			continue
+		}
+
+		request := NewDescribeLogDirsRequest(ca.conf.Version)
+		for topic, partitions := range topics {
+			request.DescribeTopics = append(request.DescribeTopics, DescribeLogDirsRequestTopic{Topic: topic, PartitionIDs: partitions})
+		}
+
+		wg.Go(func() {
+			_ = broker.Open(ca.conf) // Ensure that broker is opened
+
+			response, err := broker.DescribeLogDirs(request)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			if !errors.Is(response.ErrorCode, ErrNoError) {
+				errChan <- response.ErrorCode
+				return
+			}
+			results <- result{id: id, logDirs: response.LogDirs}
+		})
+	}
+
+	wg.Wait()
+	close(results)
+	close(errChan)
+
+	infos := make(map[TopicPartitionReplica]ReplicaLogDirInfo, len(replicas))
+	for _, replica := range replicas {
+		infos[replica] = ReplicaLogDirInfo{}
+	}
+	var errs []error
+	for r := range results {
+		for _, logDir := range r.logDirs {
+			if !errors.Is(logDir.ErrorCode, ErrNoError) {
+				errs = append(errs, fmt.Errorf("describe log dir %s on broker %d: %w", logDir.Path, r.id, logDir.ErrorCode))
+				continue
+			}
+			for _, topic := range logDir.Topics {
+				for _, partition := range topic.Partitions {
+					replica := TopicPartitionReplica{Topic: topic.Topic, Partition: partition.PartitionID, BrokerID: r.id}
+					info, ok := infos[replica]
+					if !ok {
+						continue
+					}
+					if partition.IsTemporary {
+						info.FutureReplicaLogDir = logDir.Path
+						info.FutureReplicaOffsetLag = partition.OffsetLag
+					} else {
+						info.CurrentReplicaLogDir = logDir.Path
+						info.CurrentReplicaOffsetLag = partition.OffsetLag
+					}
+					infos[replica] = info
+				}
+			}
+		}
+	}
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+
+	return infos, errors.Join(errs...)
+}
+
 func (ca *clusterAdmin) DescribeUserScramCredentials(users []string) ([]*DescribeUserScramCredentialsResult, error) {
 	req := &DescribeUserScramCredentialsRequest{}
 	for _, u := range users {
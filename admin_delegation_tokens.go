@@ -0,0 +1,184 @@
+package sarama
+
+import (
+	"errors"
+	"time"
+)
+
+// CreateDelegationTokenOptions configures the delegation token created by
+// ClusterAdmin.CreateDelegationToken.
+type CreateDelegationTokenOptions struct {
+	// Owner is the principal the token is created for. If nil the token is
+	// owned by the principal sending the request. Requires Kafka 3.3.0.0 or
+	// higher.
+	Owner *DelegationTokenPrincipal
+	// Renewers are the principals, besides the owner, allowed to renew the
+	// token.
+	Renewers []DelegationTokenPrincipal
+	// MaxLifetime is the maximum lifetime of the token. If zero the broker's
+	// delegation.token.max.lifetime.ms is used.
+	MaxLifetime time.Duration
+}
+
+// DelegationToken describes a delegation token. The TokenID and HMAC are the
+// credentials used to authenticate with the token, see Net.SASL.SCRAMTokenAuth.
+type DelegationToken struct {
+	TokenID string
+	HMAC    []byte
+	// Owner is the principal that owns the token.
+	Owner DelegationTokenPrincipal
+	// TokenRequester is the principal that requested the token. It is only
+	// returned by Kafka 3.3.0.0 or higher.
+	TokenRequester DelegationTokenPrincipal
+	// Renewers are the principals allowed to renew the token. They are only
+	// returned by DescribeDelegationTokens.
+	Renewers        []DelegationTokenPrincipal
+	IssueTimestamp  time.Time
+	ExpiryTimestamp time.Time
+	MaxTimestamp    time.Time
+}
+
+func (ca *clusterAdmin) CreateDelegationToken(options *CreateDelegationTokenOptions) (*DelegationToken, error) {
+	if options == nil {
+		options = &CreateDelegationTokenOptions{}
+	}
+
+	request := NewCreateDelegationTokenRequest(ca.conf.Version)
+	request.Renewers = options.Renewers
+	if options.MaxLifetime > 0 {
+		request.MaxLifetimeMs = options.MaxLifetime.Milliseconds()
+	}
+	if options.Owner != nil {
+		if request.Version < 3 {
+			return nil, ConfigurationError("Creating a delegation token for another owner requires Kafka version of at least v3.3.0")
+		}
+		request.OwnerPrincipalType = &options.Owner.PrincipalType
+		request.OwnerPrincipalName = &options.Owner.PrincipalName
+	}
+
+	var response *CreateDelegationTokenResponse
+	err := ca.retryOnError(isRetriableBrokerError, func() (err error) {
+		b, err := ca.findAnyBroker()
+		if err != nil {
+			return err
+		}
+		_ = b.Open(ca.client.Config())
+
+		response, err = b.CreateDelegationToken(request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !errors.Is(response.Err, ErrNoError) {
+		return nil, response.Err
+	}
+
+	return &DelegationToken{
+		TokenID:         response.TokenID,
+		HMAC:            response.HMAC,
+		Owner:           response.Owner,
+		TokenRequester:  response.TokenRequester,
+		IssueTimestamp:  time.UnixMilli(response.IssueTimestampMs),
+		ExpiryTimestamp: time.UnixMilli(response.ExpiryTimestampMs),
+		MaxTimestamp:    time.UnixMilli(response.MaxTimestampMs),
+	}, nil
+}
+
+func (ca *clusterAdmin) RenewDelegationToken(hmac []byte, renewPeriod time.Duration) (time.Time, error) {
+	request := NewRenewDelegationTokenRequest(ca.conf.Version)
+	request.HMAC = hmac
+	request.RenewPeriodMs = -1
+	if renewPeriod > 0 {
+		request.RenewPeriodMs = renewPeriod.Milliseconds()
+	}
+
+	var response *RenewDelegationTokenResponse
+	err := ca.retryOnError(isRetriableBrokerError, func() (err error) {
+		b, err := ca.findAnyBroker()
+		if err != nil {
+			return err
+		}
+		_ = b.Open(ca.client.Config())
+
+		response, err = b.RenewDelegationToken(request)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !errors.Is(response.Err, ErrNoError) {
+		return time.Time{}, response.Err
+	}
+
+	return time.UnixMilli(response.ExpiryTimestampMs), nil
+}
+
+func (ca *clusterAdmin) ExpireDelegationToken(hmac []byte, expiryTimePeriod time.Duration) (time.Time, error) {
+	request := NewExpireDelegationTokenRequest(ca.conf.Version)
+	request.HMAC = hmac
+	request.ExpiryTimePeriodMs = -1
+	if expiryTimePeriod > 0 {
+		request.ExpiryTimePeriodMs = expiryTimePeriod.Milliseconds()
+	}
+
+	var response *ExpireDelegationTokenResponse
+	err := ca.retryOnError(isRetriableBrokerError, func() (err error) {
+		b, err := ca.findAnyBroker()
+		if err != nil {
+			return err
+		}
+		_ = b.Open(ca.client.Config())
+
+		response, err = b.ExpireDelegationToken(request)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !errors.Is(response.Err, ErrNoError) {
+		return time.Time{}, response.Err
+	}
+
+	return time.UnixMilli(response.ExpiryTimestampMs), nil
+}
+
+func (ca *clusterAdmin) DescribeDelegationTokens(owners []DelegationTokenPrincipal) ([]DelegationToken, error) {
+	request := NewDescribeDelegationTokenRequest(ca.conf.Version)
+	if len(owners) > 0 {
+		request.Owners = owners
+	}
+
+	var response *DescribeDelegationTokenResponse
+	err := ca.retryOnError(isRetriableBrokerError, func() (err error) {
+		b, err := ca.findAnyBroker()
+		if err != nil {
+			return err
+		}
+		_ = b.Open(ca.client.Config())
+
+		response, err = b.DescribeDelegationToken(request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !errors.Is(response.Err, ErrNoError) {
+		return nil, response.Err
+	}
+
+	tokens := make([]DelegationToken, 0, len(response.Tokens))
+	for _, t := range response.Tokens {
+		tokens = append(tokens, DelegationToken{
+			TokenID:         t.TokenID,
+			HMAC:            t.HMAC,
+			Owner:           t.Owner,
+			TokenRequester:  t.TokenRequester,
+			Renewers:        t.Renewers,
+			IssueTimestamp:  time.UnixMilli(t.IssueTimestampMs),
+			ExpiryTimestamp: time.UnixMilli(t.ExpiryTimestampMs),
+			MaxTimestamp:    time.UnixMilli(t.MaxTimestampMs),
+		})
+	}
+	return tokens, nil
+}
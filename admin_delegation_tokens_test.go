@@ -0,0 +1,132 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDelegationTokenTestAdmin(t *testing.T, version KafkaVersion, handlers map[string]requestHandlerFunc) ClusterAdmin {
+	t.Helper()
+	seedBroker := NewMockBroker(t, 1)
+	t.Cleanup(seedBroker.Close)
+
+	handlers["MetadataRequest"] = func(req *request) encoderWithHeader {
+		return NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()).
+			For(req.body)
+	}
+	seedBroker.SetHandlerFuncByMap(handlers)
+
+	config := NewTestConfig()
+	config.Version = version
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = admin.Close() })
+	return admin
+}
+
+func TestClusterAdminCreateDelegationToken(t *testing.T) {
+	var received *CreateDelegationTokenRequest
+	admin := newDelegationTokenTestAdmin(t, V3_3_0_0, map[string]requestHandlerFunc{
+		"CreateDelegationTokenRequest": func(req *request) encoderWithHeader {
+			received = req.body.(*CreateDelegationTokenRequest)
+			return &CreateDelegationTokenResponse{
+				Version:           received.Version,
+				Owner:             DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+				TokenRequester:    DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "admin"},
+				IssueTimestampMs:  1000,
+				ExpiryTimestampMs: 2000,
+				MaxTimestampMs:    3000,
+				TokenID:           "token-id",
+				HMAC:              []byte{1, 2, 3},
+			}
+		},
+	})
+
+	token, err := admin.CreateDelegationToken(&CreateDelegationTokenOptions{
+		Owner:       &DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+		Renewers:    []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "bob"}},
+		MaxLifetime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int16(3), received.Version)
+	require.Equal(t, "alice", *received.OwnerPrincipalName)
+	require.Equal(t, int64(3600000), received.MaxLifetimeMs)
+	require.Len(t, received.Renewers, 1)
+
+	require.Equal(t, "token-id", token.TokenID)
+	require.Equal(t, []byte{1, 2, 3}, token.HMAC)
+	require.Equal(t, "alice", token.Owner.PrincipalName)
+	require.Equal(t, "admin", token.TokenRequester.PrincipalName)
+	require.Equal(t, time.UnixMilli(2000), token.ExpiryTimestamp)
+}
+
+func TestClusterAdminCreateDelegationTokenOwnerUnsupported(t *testing.T) {
+	admin := newDelegationTokenTestAdmin(t, V2_4_0_0, map[string]requestHandlerFunc{})
+
+	_, err := admin.CreateDelegationToken(&CreateDelegationTokenOptions{
+		Owner: &DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+	})
+	var configErr ConfigurationError
+	require.ErrorAs(t, err, &configErr)
+}
+
+func TestClusterAdminRenewAndExpireDelegationToken(t *testing.T) {
+	var renewed *RenewDelegationTokenRequest
+	var expired *ExpireDelegationTokenRequest
+	admin := newDelegationTokenTestAdmin(t, V2_4_0_0, map[string]requestHandlerFunc{
+		"RenewDelegationTokenRequest": func(req *request) encoderWithHeader {
+			renewed = req.body.(*RenewDelegationTokenRequest)
+			return &RenewDelegationTokenResponse{Version: renewed.Version, ExpiryTimestampMs: 5000}
+		},
+		"ExpireDelegationTokenRequest": func(req *request) encoderWithHeader {
+			expired = req.body.(*ExpireDelegationTokenRequest)
+			return &ExpireDelegationTokenResponse{Version: expired.Version, Err: ErrDelegationTokenExpired}
+		},
+	})
+
+	expiry, err := admin.RenewDelegationToken([]byte{1, 2, 3}, 0)
+	require.NoError(t, err)
+	require.Equal(t, time.UnixMilli(5000), expiry)
+	require.Equal(t, []byte{1, 2, 3}, renewed.HMAC)
+	require.Equal(t, int64(-1), renewed.RenewPeriodMs)
+
+	_, err = admin.ExpireDelegationToken([]byte{1, 2, 3}, time.Minute)
+	require.ErrorIs(t, err, ErrDelegationTokenExpired)
+	require.Equal(t, int64(60000), expired.ExpiryTimePeriodMs)
+}
+
+func TestClusterAdminDescribeDelegationTokens(t *testing.T) {
+	var received *DescribeDelegationTokenRequest
+	admin := newDelegationTokenTestAdmin(t, V2_4_0_0, map[string]requestHandlerFunc{
+		"DescribeDelegationTokenRequest": func(req *request) encoderWithHeader {
+			received = req.body.(*DescribeDelegationTokenRequest)
+			return &DescribeDelegationTokenResponse{
+				Version: received.Version,
+				Tokens: []DescribedDelegationToken{{
+					Owner:             DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+					IssueTimestampMs:  1000,
+					ExpiryTimestampMs: 2000,
+					MaxTimestampMs:    3000,
+					TokenID:           "token-id",
+					HMAC:              []byte{1, 2, 3},
+					Renewers:          []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "bob"}},
+				}},
+			}
+		},
+	})
+
+	tokens, err := admin.DescribeDelegationTokens(nil)
+	require.NoError(t, err)
+	require.Empty(t, received.Owners)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "token-id", tokens[0].TokenID)
+	require.Equal(t, "bob", tokens[0].Renewers[0].PrincipalName)
+	require.Equal(t, time.UnixMilli(1000), tokens[0].IssueTimestamp)
+}
@@ -0,0 +1,141 @@
+package sarama
+
+import "time"
+
+// QuorumInfo describes the KRaft metadata quorum, as returned by
+// ClusterAdmin.DescribeMetadataQuorum.
+type QuorumInfo struct {
+	// LeaderID is the ID of the active controller, or -1 if it is unknown.
+	LeaderID int32
+	// LeaderEpoch is the latest known leader epoch.
+	LeaderEpoch int32
+	// HighWatermark is the high watermark of the metadata log.
+	HighWatermark int64
+	// Voters are the controllers voting in the quorum.
+	Voters []QuorumReplicaInfo
+	// Observers are the brokers, and any controllers not voting, replicating
+	// the metadata log.
+	Observers []QuorumReplicaInfo
+	// Nodes maps the ID of each voter to its listener endpoints. It is only
+	// returned by Kafka 3.9.0.0 or higher.
+	Nodes map[int32][]RaftVoterListener
+}
+
+// QuorumReplicaInfo describes the replication state of a voter or observer of
+// the metadata quorum.
+type QuorumReplicaInfo struct {
+	ReplicaID int32
+	// ReplicaDirectoryID is the metadata log directory ID of the replica. It
+	// is only returned by Kafka 3.9.0.0 or higher.
+	ReplicaDirectoryID Uuid
+	// LogEndOffset is the last known log end offset of the replica, or -1 if
+	// it is unknown.
+	LogEndOffset int64
+	// Lag is the number of records the replica trails the leader's log end
+	// offset by, or -1 if it is unknown.
+	Lag int64
+	// LastFetchTimestamp is the last time the replica fetched from the
+	// leader, or the zero time if it is unknown.
+	LastFetchTimestamp time.Time
+	// LastCaughtUpTimestamp is the last time the replica was caught up with
+	// the leader, or the zero time if it is unknown.
+	LastCaughtUpTimestamp time.Time
+}
+
+func (ca *clusterAdmin) DescribeMetadataQuorum() (*QuorumInfo, error) {
+	var response *DescribeQuorumResponse
+	err := ca.retryOnError(isRetriableControllerError, func() error {
+		b, err := ca.Controller()
+		if err != nil {
+			return err
+		}
+
+		response, err = b.DescribeQuorum(NewDescribeQuorumRequest(ca.conf.Version))
+		if err != nil {
+			return err
+		}
+		return ca.controllerError(response.ErrorCode, response.ErrorMessage)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, topic := range response.Topics {
+		if topic.TopicName != clusterMetadataTopic {
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			if partition.PartitionIndex != 0 {
+				continue
+			}
+			if err := ca.controllerError(partition.ErrorCode, partition.ErrorMessage); err != nil {
+				return nil, err
+			}
+			return newQuorumInfo(partition, response.Nodes), nil
+		}
+	}
+	return nil, ErrIncompleteResponse
+}
+
+func newQuorumInfo(partition DescribeQuorumResponsePartition, nodes []DescribeQuorumResponseNode) *QuorumInfo {
+	info := &QuorumInfo{
+		LeaderID:      partition.LeaderID,
+		LeaderEpoch:   partition.LeaderEpoch,
+		HighWatermark: partition.HighWatermark,
+	}
+
+	leaderLogEndOffset := int64(-1)
+	for _, voter := range partition.CurrentVoters {
+		if voter.ReplicaID == partition.LeaderID {
+			leaderLogEndOffset = voter.LogEndOffset
+		}
+	}
+
+	replicaInfo := func(state QuorumReplicaState) QuorumReplicaInfo {
+		replica := QuorumReplicaInfo{
+			ReplicaID:          state.ReplicaID,
+			ReplicaDirectoryID: state.ReplicaDirectoryID,
+			LogEndOffset:       state.LogEndOffset,
+			Lag:                -1,
+		}
+		if leaderLogEndOffset >= 0 && state.LogEndOffset >= 0 {
+			replica.Lag = max(leaderLogEndOffset-state.LogEndOffset, 0)
+		}
+		if state.LastFetchTimestamp >= 0 {
+			replica.LastFetchTimestamp = time.UnixMilli(state.LastFetchTimestamp)
+		}
+		if state.LastCaughtUpTimestamp >= 0 {
+			replica.LastCaughtUpTimestamp = time.UnixMilli(state.LastCaughtUpTimestamp)
+		}
+		return replica
+	}
+	for _, voter := range partition.CurrentVoters {
+		info.Voters = append(info.Voters, replicaInfo(voter))
+	}
+	for _, observer := range partition.Observers {
+		info.Observers = append(info.Observers, replicaInfo(observer))
+	}
+
+	if len(nodes) > 0 {
+		info.Nodes = make(map[int32][]RaftVoterListener, len(nodes))
+		for _, node := range nodes {
+			info.Nodes[node.NodeID] = node.Listeners
+		}
+	}
+	return info
+}
+
+func (ca *clusterAdmin) UnregisterBroker(brokerID int32) error {
+	return ca.retryOnError(isRetriableControllerError, func() error {
+		b, err := ca.Controller()
+		if err != nil {
+			return err
+		}
+
+		response, err := b.UnregisterBroker(&UnregisterBrokerRequest{BrokerID: brokerID})
+		if err != nil {
+			return err
+		}
+		return ca.controllerError(response.ErrorCode, response.ErrorMessage)
+	})
+}
@@ -0,0 +1,95 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterAdminDescribeMetadataQuorum(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"DescribeQuorumRequest": NewMockDescribeQuorumResponse(t).
+			SetLeader(3000, 7, 100).
+			AddVoter(3000, 110).
+			AddVoter(3001, 95).
+			AddObserver(1, 100).
+			AddObserver(2, -1),
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_3_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	quorum, err := admin.DescribeMetadataQuorum()
+	require.NoError(t, err)
+	require.Equal(t, int32(3000), quorum.LeaderID)
+	require.Equal(t, int32(7), quorum.LeaderEpoch)
+	require.Equal(t, int64(100), quorum.HighWatermark)
+
+	require.Len(t, quorum.Voters, 2)
+	require.Equal(t, int64(0), quorum.Voters[0].Lag)
+	require.Equal(t, int32(3001), quorum.Voters[1].ReplicaID)
+	require.Equal(t, int64(15), quorum.Voters[1].Lag)
+	require.False(t, quorum.Voters[1].LastFetchTimestamp.IsZero())
+
+	require.Len(t, quorum.Observers, 2)
+	require.Equal(t, int64(10), quorum.Observers[0].Lag)
+	require.Equal(t, int64(-1), quorum.Observers[1].Lag, "lag of an unknown log end offset is unknown")
+	require.Nil(t, quorum.Nodes)
+}
+
+func TestClusterAdminDescribeMetadataQuorumError(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"DescribeQuorumRequest": NewMockDescribeQuorumResponse(t).
+			SetError(ErrClusterAuthorizationFailed),
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_3_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	_, err = admin.DescribeMetadataQuorum()
+	require.ErrorIs(t, err, ErrClusterAuthorizationFailed)
+}
+
+func TestClusterAdminUnregisterBroker(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	unregister := NewMockUnregisterBrokerResponse(t)
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"UnregisterBrokerRequest": unregister,
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_3_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	require.NoError(t, admin.UnregisterBroker(2))
+
+	unregister.SetError(ErrBrokerIDNotRegistered)
+	require.ErrorIs(t, admin.UnregisterBroker(2), ErrBrokerIDNotRegistered)
+}
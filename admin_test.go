@@ -206,6 +206,38 @@ func TestClusterAdminListTopics(t *testing.T) {
 	}
 }
 
+func TestClusterAdminListTopicsUsingDescribeTopicPartitions(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"DescribeTopicPartitionsRequest": NewMockDescribeTopicPartitionsResponse(t).
+			SetPartition("my_topic", 0, 1, []int32{1, 2}, []int32{1, 2}).
+			SetPartition("my_topic", 1, 2, []int32{2, 1}, []int32{2, 1}).
+			SetResponsePartitionLimit(1),
+		"DescribeConfigsRequest": NewMockDescribeConfigsResponse(t),
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_8_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	entries, err := admin.ListTopics()
+	require.NoError(t, err)
+
+	topic, found := entries["my_topic"]
+	require.True(t, found, "topic not found in response")
+	require.Equal(t, int32(2), topic.NumPartitions)
+	require.Equal(t, int16(2), topic.ReplicationFactor)
+	require.Equal(t, []int32{2, 1}, topic.ReplicaAssignment[1])
+	require.Equal(t, "5000", *topic.ConfigEntries["retention.ms"])
+}
+
 func TestClusterAdminListTopicsRetriesOnTransientConnectionError(t *testing.T) {
 	seedBroker := NewMockBroker(t, 1)
 	defer seedBroker.Close()
@@ -1756,6 +1788,82 @@ func TestDescribeTopicWithVersion0_11(t *testing.T) {
 	}
 }
 
+func TestDescribeTopicUsingDescribeTopicPartitions(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"DescribeTopicPartitionsRequest": NewMockDescribeTopicPartitionsResponse(t).
+			SetPartition("a", 0, 1, []int32{1, 2, 3}, []int32{1, 2, 3}).
+			SetPartition("a", 1, 1, []int32{1, 2, 3}, []int32{1}).
+			SetPartition("a", 2, 2, []int32{1, 2, 3}, []int32{2, 3}).
+			SetPartition("b", 0, 3, []int32{3}, []int32{3}).
+			SetPartition("b", 1, 3, []int32{3}, []int32{3}).
+			SetEligibleLeaderReplicas("a", 1, []int32{2}, []int32{3}).
+			SetResponsePartitionLimit(2),
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_8_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	topics, err := admin.DescribeTopics([]string{"b", "a", "missing"})
+	require.NoError(t, err)
+	require.Len(t, topics, 3)
+
+	require.Equal(t, "a", topics[0].Name)
+	require.Len(t, topics[0].Partitions, 3, "partitions split across pages should be merged")
+	require.Equal(t, []int32{1}, topics[0].Partitions[1].Isr)
+	require.Equal(t, []int32{2}, topics[0].Partitions[1].EligibleLeaderReplicas)
+	require.Equal(t, []int32{3}, topics[0].Partitions[1].LastKnownELR)
+	require.Nil(t, topics[0].Partitions[0].EligibleLeaderReplicas)
+	require.Equal(t, "b", topics[1].Name)
+	require.Len(t, topics[1].Partitions, 2)
+	require.Equal(t, "missing", topics[2].Name)
+	require.ErrorIs(t, topics[2].Err, ErrUnknownTopicOrPartition)
+
+	var pages int
+	for _, rr := range seedBroker.History() {
+		if _, ok := rr.Request.(*DescribeTopicPartitionsRequest); ok {
+			pages++
+		}
+	}
+	require.Equal(t, 3, pages)
+}
+
+func TestDescribeTopicFallsBackToMetadata(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"ApiVersionsRequest": NewMockApiVersionsResponse(t).SetApiKeys([]ApiVersionsResponseKey{
+			{ApiKey: apiKeyMetadata, MinVersion: 0, MaxVersion: 12},
+		}),
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetLeader("my_topic", 0, seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+	})
+
+	config := NewTestConfig()
+	config.ApiVersionsRequest = true
+	config.Version = V3_8_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	topics, err := admin.DescribeTopics([]string{"my_topic"})
+	require.NoError(t, err)
+	require.Len(t, topics, 1)
+	require.Equal(t, "my_topic", topics[0].Name)
+	require.Len(t, topics[0].Partitions, 1)
+}
+
 func TestDescribeConsumerGroup(t *testing.T) {
 	seedBroker := NewMockBroker(t, 1)
 	defer seedBroker.Close()
@@ -2579,6 +2687,79 @@ func TestDescribeLogDirsUnknownBroker(t *testing.T) {
 	}
 }
 
+func TestAlterReplicaLogDirs(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"AlterReplicaLogDirsRequest": NewMockAlterReplicaLogDirsResponse(t).
+			SetError("topic2", 0, ErrLogDirNotFound),
+	})
+
+	config := NewTestConfig()
+	config.Version = V2_6_0_0
+
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	err = admin.AlterReplicaLogDirs(map[TopicPartitionReplica]string{
+		{Topic: "topic1", Partition: 0, BrokerID: seedBroker.BrokerID()}: "/tmp/logs2",
+		{Topic: "topic1", Partition: 1, BrokerID: seedBroker.BrokerID()}: "/tmp/logs2",
+	})
+	require.NoError(t, err)
+
+	err = admin.AlterReplicaLogDirs(map[TopicPartitionReplica]string{
+		{Topic: "topic2", Partition: 0, BrokerID: seedBroker.BrokerID()}: "/tmp/missing",
+	})
+	require.ErrorIs(t, err, ErrLogDirNotFound)
+	require.ErrorContains(t, err, "partition 0 of topic topic2")
+
+	err = admin.AlterReplicaLogDirs(map[TopicPartitionReplica]string{
+		{Topic: "topic1", Partition: 0, BrokerID: seedBroker.BrokerID() + 1}: "/tmp/logs2",
+	})
+	require.ErrorContains(t, err, "could not find broker id")
+}
+
+func TestDescribeReplicaLogDirs(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"DescribeLogDirsRequest": NewMockDescribeLogDirsResponse(t).
+			SetLogDirs("/tmp/logs", map[string]int{"topic1": 2}).
+			AddFutureReplica("/tmp/logs2", "topic1", 1, 42),
+	})
+
+	config := NewTestConfig()
+	config.Version = V2_6_0_0
+
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, admin)
+
+	moving := TopicPartitionReplica{Topic: "topic1", Partition: 1, BrokerID: seedBroker.BrokerID()}
+	settled := TopicPartitionReplica{Topic: "topic1", Partition: 0, BrokerID: seedBroker.BrokerID()}
+	missing := TopicPartitionReplica{Topic: "topic2", Partition: 0, BrokerID: seedBroker.BrokerID()}
+	infos, err := admin.DescribeReplicaLogDirs([]TopicPartitionReplica{moving, settled, missing})
+	require.NoError(t, err)
+	require.Equal(t, map[TopicPartitionReplica]ReplicaLogDirInfo{
+		moving: {
+			CurrentReplicaLogDir:   "/tmp/logs",
+			FutureReplicaLogDir:    "/tmp/logs2",
+			FutureReplicaOffsetLag: 42,
+		},
+		settled: {CurrentReplicaLogDir: "/tmp/logs"},
+		missing: {},
+	}, infos)
+}
+
 func Test_retryOnError(t *testing.T) {
 	testBackoffTime := 100 * time.Millisecond
 	config := NewTestConfig()
@@ -4,13 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
-// TransactionClusterAdmin extends ClusterAdmin with the read-only KIP-664
-// transaction-detection APIs: DescribeProducers, DescribeTransactions, and
-// ListTransactions. Values returned by NewClusterAdmin and
+// TransactionClusterAdmin extends ClusterAdmin with the KIP-664 APIs for
+// detecting and aborting hanging transactions: DescribeProducers,
+// DescribeTransactions, ListTransactions, FindHangingTransactions, and
+// AbortTransaction. Values returned by NewClusterAdmin and
 // NewClusterAdminFromClient implement TransactionClusterAdmin.
 type TransactionClusterAdmin interface {
 	ClusterAdmin
@@ -30,6 +33,38 @@ type TransactionClusterAdmin interface {
 	// milliseconds. Requires Kafka 3.0.0.0 or higher. A durationFilterMs less
 	// than 0 disables the duration filter.
 	ListTransactions(stateFilters []string, producerIDFilters []int64, durationFilterMs int64) ([]ListTransactionsResponseTransactionState, error)
+
+	// FindHangingTransactions returns the open transactions on the given topic
+	// partitions that have not been written to for longer than
+	// maxTransactionTimeout (the broker's transaction.max.timeout.ms) and that
+	// their coordinator no longer considers part of an ongoing transaction.
+	// Such transactions block the last stable offset of the partition, stalling
+	// read_committed consumers, until aborted with AbortTransaction. Requires
+	// Kafka 3.0.0.0 or higher.
+	FindHangingTransactions(topicPartitions map[string][]int32, maxTransactionTimeout time.Duration) ([]HangingTransaction, error)
+
+	// AbortTransaction aborts the open transaction of the given producer on a
+	// topic partition by writing an ABORT marker directly to the partition
+	// leader. The producer id, epoch, and coordinator epoch should be taken
+	// from DescribeProducers (or FindHangingTransactions).
+	AbortTransaction(topic string, partition int32, producerID int64, producerEpoch int16, coordinatorEpoch int32) error
+}
+
+// HangingTransaction is an open transaction on a topic partition found by
+// FindHangingTransactions.
+type HangingTransaction struct {
+	// Topic is the topic name
+	Topic string
+
+	// Partition is the partition id
+	Partition int32
+
+	// ProducerState is the state of the producer that opened the transaction
+	ProducerState
+
+	// TransactionalID is the transactional id that the coordinator associates
+	// with the producer id, or empty if there is none
+	TransactionalID string
 }
 
 var _ TransactionClusterAdmin = (*clusterAdmin)(nil)
@@ -293,3 +328,151 @@ func (ca *clusterAdmin) ListTransactions(stateFilters []string, producerIDFilter
 
 	return allTransactions, errors.Join(errs...)
 }
+
+func (ca *clusterAdmin) FindHangingTransactions(topicPartitions map[string][]int32, maxTransactionTimeout time.Duration) ([]HangingTransaction, error) {
+	if len(topicPartitions) == 0 {
+		return nil, nil
+	}
+
+	producers, err := ca.DescribeProducers(topicPartitions)
+	if err != nil {
+		return nil, err
+	}
+
+	// A transaction can only be hanging once its producer has been silent for
+	// longer than the maximum transaction timeout: any live transaction would
+	// have been aborted by its coordinator by then.
+	var candidates []HangingTransaction
+	var producerIDs []int64
+	now := time.Now()
+	for topic, partitions := range producers {
+		for partition, result := range partitions {
+			if !errors.Is(result.ErrorCode, ErrNoError) {
+				err = errors.Join(err, fmt.Errorf("describe producers for partition %d of topic %s: %w", partition, topic, result.ErrorCode))
+				continue
+			}
+			for _, producer := range result.ActiveProducers {
+				if producer.CurrentTxnStartOffset < 0 {
+					continue
+				}
+				if now.Sub(time.UnixMilli(producer.LastTimestamp)) <= maxTransactionTimeout {
+					continue
+				}
+				candidates = append(candidates, HangingTransaction{
+					Topic:         topic,
+					Partition:     partition,
+					ProducerState: producer,
+				})
+				producerIDs = append(producerIDs, producer.ProducerID)
+			}
+		}
+	}
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	// Look up the transactional ids of the candidate producers. A producer
+	// unknown to every coordinator cannot have its transaction completed.
+	transactions, err := ca.ListTransactions(nil, producerIDs, -1)
+	if err != nil {
+		return nil, err
+	}
+	transactionalIDs := make(map[int64]string, len(transactions))
+	for _, transaction := range transactions {
+		transactionalIDs[transaction.ProducerID] = transaction.TransactionalID
+	}
+
+	var ids []string
+	for i := range candidates {
+		if id, ok := transactionalIDs[candidates[i].ProducerID]; ok {
+			candidates[i].TransactionalID = id
+			ids = append(ids, id)
+		}
+	}
+	states, err := ca.DescribeTransactions(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var hanging []HangingTransaction
+	for _, candidate := range candidates {
+		if candidate.TransactionalID != "" && isTransactionRunning(candidate, states[candidate.TransactionalID]) {
+			continue
+		}
+		hanging = append(hanging, candidate)
+	}
+	return hanging, nil
+}
+
+// isTransactionRunning reports whether the coordinator's view of a transaction
+// still includes the open transaction found on a partition, in which case the
+// transaction is merely long-running rather than hanging.
+func isTransactionRunning(candidate HangingTransaction, state TransactionState) bool {
+	if state.ProducerID != candidate.ProducerID || int32(state.ProducerEpoch) != candidate.ProducerEpoch {
+		return false
+	}
+	for _, topic := range state.Topics {
+		if topic.Topic == candidate.Topic && slices.Contains(topic.Partitions, candidate.Partition) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ca *clusterAdmin) AbortTransaction(topic string, partition int32, producerID int64, producerEpoch int16, coordinatorEpoch int32) error {
+	request := NewWriteTxnMarkersRequest(ca.conf.Version)
+	request.Markers = []WritableTxnMarker{{
+		ProducerID:        producerID,
+		ProducerEpoch:     producerEpoch,
+		TransactionResult: false,
+		Topics:            []WritableTxnMarkerTopic{{Name: topic, PartitionIndexes: []int32{partition}}},
+		CoordinatorEpoch:  coordinatorEpoch,
+	}}
+
+	return ca.retryOnError(isRetriableLeaderError, func() (err error) {
+		defer func() {
+			if err != nil && isRetriableLeaderError(err) {
+				_ = ca.client.RefreshMetadata(topic)
+			}
+		}()
+
+		leader, err := ca.client.Leader(topic, partition)
+		if err != nil {
+			return err
+		}
+
+		response, err := leader.WriteTxnMarkers(request)
+		if err != nil {
+			return err
+		}
+
+		for _, marker := range response.Markers {
+			for _, t := range marker.Topics {
+				for _, p := range t.Partitions {
+					if t.Name == topic && p.PartitionIndex == partition {
+						if !errors.Is(p.ErrorCode, ErrNoError) {
+							return p.ErrorCode
+						}
+						return nil
+					}
+				}
+			}
+		}
+		return fmt.Errorf("abort transaction: no result for partition %d of topic %s: %w", partition, topic, ErrIncompleteResponse)
+	})
+}
+
+// isRetriableLeaderError reports whether the given error indicates stale
+// partition leadership that refreshing the metadata and retrying can resolve.
+func isRetriableLeaderError(err error) bool {
+	switch {
+	case errors.Is(err, ErrNotLeaderForPartition):
+		return true
+	case errors.Is(err, ErrLeaderNotAvailable):
+		return true
+	case errors.Is(err, io.EOF):
+		return true
+	default:
+		return false
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -829,3 +832,118 @@ func TestClusterAdminImplementsTransactionClusterAdmin(t *testing.T) {
 	_, ok := admin.(TransactionClusterAdmin)
 	require.True(t, ok, "NewClusterAdmin result should implement TransactionClusterAdmin")
 }
+
+func TestClusterAdminFindHangingTransactions(t *testing.T) {
+	topicName := "my_topic"
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	stale := time.Now().Add(-time.Hour).UnixMilli()
+	recent := time.Now().UnixMilli()
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetController(seedBroker.BrokerID()).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()).
+			SetLeader(topicName, 0, seedBroker.BrokerID()).
+			SetLeader(topicName, 1, seedBroker.BrokerID()).
+			SetLeader(topicName, 2, seedBroker.BrokerID()).
+			SetLeader(topicName, 3, seedBroker.BrokerID()),
+		"DescribeProducersRequest": NewMockDescribeProducersResponse(t).
+			// no transactional id: hanging
+			AddProducer(topicName, 0, ProducerState{ProducerID: 1, LastTimestamp: stale, CurrentTxnStartOffset: 10}).
+			// no open transaction
+			AddProducer(topicName, 0, ProducerState{ProducerID: 5, LastTimestamp: stale, CurrentTxnStartOffset: -1}).
+			// still part of the coordinator's ongoing transaction
+			AddProducer(topicName, 1, ProducerState{ProducerID: 2, LastTimestamp: stale, CurrentTxnStartOffset: 20}).
+			// the coordinator has since bumped the epoch: hanging
+			AddProducer(topicName, 2, ProducerState{ProducerID: 3, LastTimestamp: stale, CurrentTxnStartOffset: 30}).
+			// written to within the transaction timeout
+			AddProducer(topicName, 3, ProducerState{ProducerID: 4, LastTimestamp: recent, CurrentTxnStartOffset: 40}),
+		"ListTransactionsRequest": NewMockListTransactionsResponse(t).
+			AddTransaction("running", 2, TransactionStateOngoing).
+			AddTransaction("bumped", 3, TransactionStateOngoing),
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorTransaction, "running", seedBroker).
+			SetCoordinator(CoordinatorTransaction, "bumped", seedBroker),
+		"DescribeTransactionsRequest": NewMockDescribeTransactionsResponse(t).
+			AddTransaction("running", TransactionState{
+				TransactionState: TransactionStateOngoing,
+				ProducerID:       2,
+				Topics:           []DescribeTransactionsResponseTopic{{Topic: topicName, Partitions: []int32{1}}},
+			}).
+			AddTransaction("bumped", TransactionState{
+				TransactionState: TransactionStateOngoing,
+				ProducerID:       3,
+				ProducerEpoch:    1,
+				Topics:           []DescribeTransactionsResponseTopic{{Topic: topicName, Partitions: []int32{2}}},
+			}),
+	})
+
+	config := NewTestConfig()
+	config.Version = V3_0_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer func() { _ = admin.Close() }()
+
+	txAdmin := admin.(TransactionClusterAdmin)
+	hanging, err := txAdmin.FindHangingTransactions(map[string][]int32{topicName: {0, 1, 2, 3}}, 15*time.Minute)
+	require.NoError(t, err)
+	slices.SortFunc(hanging, func(a, b HangingTransaction) int { return int(a.Partition - b.Partition) })
+	require.Len(t, hanging, 2)
+	require.Equal(t, int32(0), hanging[0].Partition)
+	require.Equal(t, int64(1), hanging[0].ProducerID)
+	require.Empty(t, hanging[0].TransactionalID)
+	require.Equal(t, int32(2), hanging[1].Partition)
+	require.Equal(t, int64(3), hanging[1].ProducerID)
+	require.Equal(t, int64(30), hanging[1].CurrentTxnStartOffset)
+	require.Equal(t, "bumped", hanging[1].TransactionalID)
+}
+
+func TestClusterAdminAbortTransaction(t *testing.T) {
+	topicName := "my_topic"
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	var (
+		mu      sync.Mutex
+		written *WriteTxnMarkersRequest
+	)
+	markers := NewMockWriteTxnMarkersResponse(t)
+	seedBroker.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": func(req *request) encoderWithHeader {
+			return NewMockMetadataResponse(t).
+				SetController(seedBroker.BrokerID()).
+				SetBroker(seedBroker.Addr(), seedBroker.BrokerID()).
+				SetLeader(topicName, 0, seedBroker.BrokerID()).
+				For(req.body)
+		},
+		"WriteTxnMarkersRequest": func(req *request) encoderWithHeader {
+			mu.Lock()
+			written = req.body.(*WriteTxnMarkersRequest)
+			mu.Unlock()
+			return markers.For(req.body)
+		},
+	})
+
+	config := NewTestConfig()
+	config.Version = V2_8_0_0
+	admin, err := NewClusterAdmin([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer func() { _ = admin.Close() }()
+
+	txAdmin := admin.(TransactionClusterAdmin)
+	require.NoError(t, txAdmin.AbortTransaction(topicName, 0, 42, 3, 7))
+	mu.Lock()
+	require.Equal(t, int16(1), written.Version)
+	require.Equal(t, []WritableTxnMarker{{
+		ProducerID:       42,
+		ProducerEpoch:    3,
+		Topics:           []WritableTxnMarkerTopic{{Name: topicName, PartitionIndexes: []int32{0}}},
+		CoordinatorEpoch: 7,
+	}}, written.Markers)
+	mu.Unlock()
+
+	markers.SetError(topicName, 0, ErrInvalidProducerEpoch)
+	err = txAdmin.AbortTransaction(topicName, 0, 42, 3, 7)
+	require.ErrorIs(t, err, ErrInvalidProducerEpoch)
+}
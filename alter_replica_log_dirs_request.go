@@ -0,0 +1,166 @@
+package sarama
+
+// AlterReplicaLogDirsRequest is sent to a broker to move its replicas of the
+// given partitions to other log directories (KIP-113).
+type AlterReplicaLogDirsRequest struct {
+	// Version 0 and 1 are equal
+	// The version number is bumped to indicate that on quota violation brokers send out responses before throttling.
+	Version int16
+
+	// Dirs contains the alterations to make for each directory
+	Dirs []AlterReplicaLogDirsRequestDir
+}
+
+// AlterReplicaLogDirsRequestDir contains the partitions to move to a log
+// directory.
+type AlterReplicaLogDirsRequestDir struct {
+	// Path is the absolute directory path
+	Path string
+
+	// Topics contains the topics to add to the directory
+	Topics []AlterReplicaLogDirsRequestTopic
+}
+
+type AlterReplicaLogDirsRequestTopic struct {
+	// Name is the topic name
+	Name string
+
+	// Partitions contains the partition indexes
+	Partitions []int32
+}
+
+// NewAlterReplicaLogDirsRequest returns an AlterReplicaLogDirsRequest using
+// the highest protocol version supported by the given Kafka version.
+func NewAlterReplicaLogDirsRequest(version KafkaVersion) *AlterReplicaLogDirsRequest {
+	r := &AlterReplicaLogDirsRequest{}
+	if version.IsAtLeast(V2_6_0_0) {
+		r.Version = 2
+	} else if version.IsAtLeast(V2_0_0_0) {
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *AlterReplicaLogDirsRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *AlterReplicaLogDirsRequest) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(r.Dirs)); err != nil {
+		return err
+	}
+	for _, dir := range r.Dirs {
+		if err := pe.putString(dir.Path); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(dir.Topics)); err != nil {
+			return err
+		}
+		for _, topic := range dir.Topics {
+			if err := pe.putString(topic.Name); err != nil {
+				return err
+			}
+
+			if err := pe.putInt32Array(topic.Partitions); err != nil {
+				return err
+			}
+
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *AlterReplicaLogDirsRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Dirs = make([]AlterReplicaLogDirsRequestDir, n)
+	for i := range r.Dirs {
+		dir := &r.Dirs[i]
+		if dir.Path, err = pd.getString(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		dir.Topics = make([]AlterReplicaLogDirsRequestTopic, m)
+		for j := range dir.Topics {
+			topic := &dir.Topics[j]
+			if topic.Name, err = pd.getString(); err != nil {
+				return err
+			}
+
+			if topic.Partitions, err = pd.getInt32Array(); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *AlterReplicaLogDirsRequest) key() int16 {
+	return apiKeyAlterReplicaLogDirs
+}
+
+func (r *AlterReplicaLogDirsRequest) version() int16 {
+	return r.Version
+}
+
+func (r *AlterReplicaLogDirsRequest) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (r *AlterReplicaLogDirsRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *AlterReplicaLogDirsRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *AlterReplicaLogDirsRequest) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *AlterReplicaLogDirsRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V2_6_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_0_0_0
+	}
+}
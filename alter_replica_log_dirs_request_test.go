@@ -0,0 +1,44 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	alterReplicaLogDirsRequestV0 = []byte{
+		0, 0, 0, 1, // Dirs
+		0, 9, '/', 'd', 'a', 't', 'a', '/', 'k', 'f', '2', // Path
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Name
+		0, 0, 0, 2, // Partitions
+		0, 0, 0, 0,
+		0, 0, 0, 1,
+	}
+
+	alterReplicaLogDirsRequestV2 = []byte{
+		2,                                               // Dirs
+		10, '/', 'd', 'a', 't', 'a', '/', 'k', 'f', '2', // Path
+		2,                          // Topics
+		6, 't', 'o', 'p', 'i', 'c', // Name
+		3, // Partitions
+		0, 0, 0, 0,
+		0, 0, 0, 1,
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestAlterReplicaLogDirsRequest(t *testing.T) {
+	request := &AlterReplicaLogDirsRequest{
+		Version: 0,
+		Dirs: []AlterReplicaLogDirsRequestDir{{
+			Path:   "/data/kf2",
+			Topics: []AlterReplicaLogDirsRequestTopic{{Name: "topic", Partitions: []int32{0, 1}}},
+		}},
+	}
+	testRequest(t, "v0", request, alterReplicaLogDirsRequestV0)
+
+	request.Version = 2
+	testRequest(t, "v2", request, alterReplicaLogDirsRequestV2)
+}
@@ -0,0 +1,158 @@
+package sarama
+
+import "time"
+
+type AlterReplicaLogDirsResponse struct {
+	// Version 0 and 1 are equal
+	// The version number is bumped to indicate that on quota violation brokers send out responses before throttling.
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// Results contains the results for each topic
+	Results []AlterReplicaLogDirsResponseTopic
+}
+
+type AlterReplicaLogDirsResponseTopic struct {
+	// TopicName is the name of the topic
+	TopicName string
+
+	// Partitions contains the results for each partition
+	Partitions []AlterReplicaLogDirsResponsePartition
+}
+
+type AlterReplicaLogDirsResponsePartition struct {
+	// PartitionIndex is the partition index
+	PartitionIndex int32
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+}
+
+func (r *AlterReplicaLogDirsResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *AlterReplicaLogDirsResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+
+	if err := pe.putArrayLength(len(r.Results)); err != nil {
+		return err
+	}
+	for _, topic := range r.Results {
+		if err := pe.putString(topic.TopicName); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition.PartitionIndex)
+			pe.putKError(partition.ErrorCode)
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *AlterReplicaLogDirsResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Results = make([]AlterReplicaLogDirsResponseTopic, n)
+	for i := range r.Results {
+		topic := &r.Results[i]
+		if topic.TopicName, err = pd.getString(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]AlterReplicaLogDirsResponsePartition, m)
+		for j := range topic.Partitions {
+			partition := &topic.Partitions[j]
+			if partition.PartitionIndex, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if partition.ErrorCode, err = pd.getKError(); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *AlterReplicaLogDirsResponse) key() int16 {
+	return apiKeyAlterReplicaLogDirs
+}
+
+func (r *AlterReplicaLogDirsResponse) version() int16 {
+	return r.Version
+}
+
+func (r *AlterReplicaLogDirsResponse) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 1
+	}
+	return 0
+}
+
+func (r *AlterReplicaLogDirsResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *AlterReplicaLogDirsResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *AlterReplicaLogDirsResponse) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *AlterReplicaLogDirsResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V2_6_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_0_0_0
+	}
+}
+
+func (r *AlterReplicaLogDirsResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
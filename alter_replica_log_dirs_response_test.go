@@ -0,0 +1,46 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	alterReplicaLogDirsResponseV0 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		0, 0, 0, 1, // Results
+		0, 5, 't', 'o', 'p', 'i', 'c', // TopicName
+		0, 0, 0, 1, // Partitions
+		0, 0, 0, 0, // PartitionIndex
+		0, 57, // ErrorCode (LOG_DIR_NOT_FOUND)
+	}
+
+	alterReplicaLogDirsResponseV2 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		2,                          // Results
+		6, 't', 'o', 'p', 'i', 'c', // TopicName
+		2,          // Partitions
+		0, 0, 0, 0, // PartitionIndex
+		0, 57, // ErrorCode (LOG_DIR_NOT_FOUND)
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestAlterReplicaLogDirsResponse(t *testing.T) {
+	response := &AlterReplicaLogDirsResponse{
+		Version:      0,
+		ThrottleTime: 100 * time.Millisecond,
+		Results: []AlterReplicaLogDirsResponseTopic{{
+			TopicName:  "topic",
+			Partitions: []AlterReplicaLogDirsResponsePartition{{PartitionIndex: 0, ErrorCode: ErrLogDirNotFound}},
+		}},
+	}
+	testResponse(t, "v0", response, alterReplicaLogDirsResponseV0)
+
+	response.Version = 2
+	testResponse(t, "v2", response, alterReplicaLogDirsResponseV2)
+}
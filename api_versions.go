@@ -81,9 +81,21 @@ const (
 	apiKeyAlterClientQuotas            = 49
 	apiKeyDescribeUserScramCredentials = 50
 	apiKeyAlterUserScramCredentials    = 51
+	apiKeyDescribeQuorum               = 55
 	apiKeyUpdateFeatures               = 57
 	apiKeyDescribeCluster              = 60
 	apiKeyDescribeProducers            = 61
+	apiKeyUnregisterBroker             = 64
 	apiKeyDescribeTransactions         = 65
 	apiKeyListTransactions             = 66
+	apiKeyConsumerGroupHeartbeat       = 68
+	apiKeyConsumerGroupDescribe        = 69
+	apiKeyGetTelemetrySubscriptions    = 71
+	apiKeyPushTelemetry                = 72
+	apiKeyDescribeTopicPartitions      = 75
+	apiKeyShareGroupHeartbeat          = 76
+	apiKeyShareFetch                   = 78
+	apiKeyShareAcknowledge             = 79
+	apiKeyAddRaftVoter                 = 80
+	apiKeyRemoveRaftVoter              = 81
 )
@@ -1,6 +1,7 @@
 package sarama
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -18,6 +19,9 @@ import (
 // ErrProducerRetryBufferOverflow is returned when the bridging retry buffer is full and OOM prevention needs to be applied.
 var ErrProducerRetryBufferOverflow = errors.New("retry buffer full: message discarded to prevent buffer overflow")
 
+// ErrProducerBufferFull is returned when a message waited longer than Producer.MaxBlock for room in Producer.MaxBufferedBytes.
+var ErrProducerBufferFull = errors.New("producer buffer full: message discarded after waiting for Producer.MaxBlock")
+
 const (
 	// minFunctionalRetryBufferLength defines the minimum number of messages the retry buffer must support.
 	// If Producer.Retry.MaxBufferLength is set to a non-zero value below this limit, it will be adjusted to this value.
@@ -50,9 +54,23 @@ type AsyncProducer interface {
 	Close() error
 
 	// Input is the input channel for the user to write messages to that they
-	// wish to send.
+	// wish to send. Writes block while Producer.MaxBufferedBytes is reached.
 	Input() chan<- *ProducerMessage
 
+	// SendContext writes a message to the Input channel, blocking until there
+	// is room for it or the context is done, in which case the context error
+	// is returned and the message is not sent. It returns ErrShuttingDown
+	// once the producer is being closed.
+	SendContext(ctx context.Context, msg *ProducerMessage) error
+
+	// Flush sends the messages buffered by the producer without waiting for
+	// Producer.Flush thresholds, and waits until every message written to the
+	// Input channel before the call has either been acknowledged or failed.
+	// The Successes and Errors channels must still be read while it waits. It
+	// returns the context error if the context is done first, and
+	// ErrShuttingDown once the producer is being closed.
+	Flush(ctx context.Context) error
+
 	// Successes is the success output channel back to the user when Return.Successes is
 	// enabled. If Return.Successes is true, you MUST read from this channel or the
 	// Producer will deadlock. It is suggested that you send and read messages
@@ -103,6 +121,7 @@ type asyncProducer struct {
 
 	errors                    chan *ProducerError
 	input, successes, retries chan *ProducerMessage
+	retried                   chan *ProducerMessage // from the retryHandler back to the dispatcher
 	inFlight                  sync.WaitGroup
 
 	brokers    map[*Broker]*brokerProducer
@@ -116,10 +135,20 @@ type asyncProducer struct {
 	// mirroring Kafka's RecordAccumulator.
 	muter *partitionMuter
 
+	// flusher tracks the messages Flush is waiting for.
+	flusher *flushTracker
+
+	// buffer bounds the bytes of the messages neither acknowledged nor failed
+	// yet to Producer.MaxBufferedBytes.
+	buffer *bufferBudget
+
 	// done is closed on shutdown so a retryBatch goroutine blocked handing a muted
 	// batch to a broker can release the mute and fail instead of waiting forever.
 	done   chan struct{}
 	closed atomic.Bool
+	// inputLock is read-held by SendContext while it writes to the Input
+	// channel, so that shutdown does not close it under a blocked sender.
+	inputLock sync.RWMutex
 
 	metricsRegistry metrics.Registry
 }
@@ -130,24 +159,42 @@ type partitionMuter struct {
 	closed         bool
 	inFlightCounts map[string]map[int32]int // topic -> partition -> in-flight count
 	unmuteSignal   chan struct{}
+
+	// maxInFlight is the number of batches a partition may have in flight
+	// (1 unless the producer is idempotent), and held, when set, reports
+	// whether a partition is held back regardless of its in-flight count.
+	maxInFlight int
+	held        func(topic string, partition int32) bool
 }
 
 func newPartitionMuter() *partitionMuter {
 	m := &partitionMuter{
 		inFlightCounts: make(map[string]map[int32]int),
 		unmuteSignal:   make(chan struct{}),
+		maxInFlight:    1,
 	}
 	m.cond = sync.NewCond(&m.mu)
 	return m
 }
 
-// isMuted reports whether the partition has an in-flight batch.
+// isMuted reports whether the partition can't have another batch in flight.
 // Requires: m.mu held.
 func (m *partitionMuter) isMuted(topic string, partition int32) bool {
-	return m.inFlightCounts[topic][partition] > 0
+	if m.inFlightCounts[topic][partition] >= m.maxInFlight {
+		return true
+	}
+	return m.held != nil && m.held(topic, partition)
 }
 
-// isAnyMuted reports whether any partition in the set has an in-flight batch.
+// isBackedUp reports whether the partition can't have another batch in flight.
+func (m *partitionMuter) isBackedUp(topic string, partition int32) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.isMuted(topic, partition)
+}
+
+// isAnyMuted reports whether any partition in the set can't have another batch in flight.
 // Requires: m.mu held.
 func (m *partitionMuter) isAnyMuted(set *produceSet) bool {
 	return set.anyPartition(func(topic string, partition int32, _ *partitionSet) bool {
@@ -266,6 +313,23 @@ func (m *partitionMuter) unmute(set *produceSet) {
 			delete(m.inFlightCounts, topic)
 		}
 	})
+	m.signal()
+}
+
+// release wakes up the goroutines waiting for a partition that is no longer held.
+func (m *partitionMuter) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.signal()
+}
+
+// signal wakes up the goroutines waiting for a partition to be unmuted.
+// Requires: m.mu held.
+func (m *partitionMuter) signal() {
 	close(m.unmuteSignal)
 	m.unmuteSignal = make(chan struct{})
 	m.cond.Broadcast()
@@ -284,6 +348,179 @@ func (m *partitionMuter) close() {
 	m.cond.Broadcast()
 }
 
+// flushTracker counts the messages of each flush generation that have been
+// neither acknowledged nor failed yet, so that a Flush waits for the messages
+// enqueued before it without waiting for the ones enqueued after it.
+type flushTracker struct {
+	mu      sync.Mutex
+	gen     uint64
+	pending map[uint64]int // generation -> messages not completed yet
+	waiters []flushWaiter
+	signal  chan struct{} // closed and replaced whenever a flush starts
+
+	waiting atomic.Int32 // len(waiters), readable without the lock
+}
+
+type flushWaiter struct {
+	gen  uint64
+	done chan struct{}
+}
+
+func newFlushTracker() *flushTracker {
+	return &flushTracker{
+		pending: make(map[uint64]int),
+		signal:  make(chan struct{}),
+	}
+}
+
+// enqueued adds the message to the current generation.
+func (f *flushTracker) enqueued(msg *ProducerMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	msg.flushGen = f.gen
+	f.pending[f.gen]++
+}
+
+// completed removes a message of the given generation once it has been
+// acknowledged or failed.
+func (f *flushTracker) completed(gen uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pending[gen]--
+	if f.pending[gen] <= 0 {
+		delete(f.pending, gen)
+		f.release()
+	}
+}
+
+// start begins a new generation; done is closed once every message of the
+// previous generations has completed.
+func (f *flushTracker) start(done chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.gen++
+	f.waiters = append(f.waiters, flushWaiter{gen: f.gen, done: done})
+	f.release()
+
+	close(f.signal)
+	f.signal = make(chan struct{})
+}
+
+// release closes the waiters no pending message is older than.
+// Requires: f.mu held.
+func (f *flushTracker) release() {
+	oldest := f.gen
+	for gen := range f.pending {
+		oldest = min(oldest, gen)
+	}
+	waiters := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.gen <= oldest {
+			close(w.done)
+		} else {
+			waiters = append(waiters, w)
+		}
+	}
+	f.waiters = waiters
+	f.waiting.Store(int32(len(waiters)))
+}
+
+// flushing reports whether a Flush is waiting for messages to complete.
+func (f *flushTracker) flushing() bool {
+	return f.waiting.Load() > 0
+}
+
+// flushSignal returns a channel closed when the next flush starts.
+func (f *flushTracker) flushSignal() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.signal
+}
+
+// bufferBudget accounts for the bytes of the messages neither acknowledged nor
+// failed yet, against a limit of 0 for none, mirroring the BufferPool of the
+// JVM producer.
+type bufferBudget struct {
+	mu    sync.Mutex
+	max   int
+	used  int
+	freed chan struct{} // closed and replaced whenever bytes are released
+	gauge metrics.Gauge
+
+	// full is closed and replaced whenever a message starts waiting for
+	// room, so that the brokerProducers send what they hold right away
+	full      chan struct{}
+	exhausted atomic.Bool
+}
+
+func newBufferBudget(max int, gauge metrics.Gauge) *bufferBudget {
+	return &bufferBudget{
+		max:   max,
+		freed: make(chan struct{}),
+		gauge: gauge,
+		full:  make(chan struct{}),
+	}
+}
+
+// reserve accounts for the message when it fits in the budget. Otherwise it
+// marks the budget exhausted until a message fits or unblock is called, and
+// returns false along with a channel closed once bytes are released.
+func (b *bufferBudget) reserve(msg *ProducerMessage, size int) (<-chan struct{}, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.max > 0 && b.used > 0 && b.used+size > b.max {
+		if b.exhausted.CompareAndSwap(false, true) {
+			close(b.full)
+			b.full = make(chan struct{})
+		}
+		return b.freed, false
+	}
+	b.exhausted.Store(false)
+	msg.bufferedBytes = size
+	b.used += size
+	b.gauge.Update(int64(b.used))
+	return nil, true
+}
+
+// unblock clears the exhausted state once the waiting message gave up.
+func (b *bufferBudget) unblock() {
+	b.exhausted.Store(false)
+}
+
+// release gives back the bytes reserved by a message.
+func (b *bufferBudget) release(size int) {
+	if size == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used -= size
+	b.gauge.Update(int64(b.used))
+	close(b.freed)
+	b.freed = make(chan struct{})
+}
+
+// waiting reports whether a message is waiting for room in the budget.
+func (b *bufferBudget) waiting() bool {
+	return b.exhausted.Load()
+}
+
+// fullSignal returns a channel closed when the next message starts waiting
+// for room in the budget.
+func (b *bufferBudget) fullSignal() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.full
+}
+
 // NewAsyncProducer creates a new AsyncProducer using the given broker addresses and configuration.
 func NewAsyncProducer(addrs []string, conf *Config) (AsyncProducer, error) {
 	client, err := NewClient(addrs, conf)
@@ -320,13 +557,26 @@ func newAsyncProducer(client Client) (AsyncProducer, error) {
 		input:           make(chan *ProducerMessage),
 		successes:       make(chan *ProducerMessage),
 		retries:         make(chan *ProducerMessage),
+		retried:         make(chan *ProducerMessage),
 		done:            make(chan struct{}),
 		brokers:         make(map[*Broker]*brokerProducer),
 		brokerRefs:      make(map[*brokerProducer]int),
 		txnmgr:          txnmgr,
 		muter:           newPartitionMuter(),
+		flusher:         newFlushTracker(),
 		metricsRegistry: newCleanupRegistry(client.Config().MetricRegistry),
 	}
+	p.buffer = newBufferBudget(p.conf.Producer.MaxBufferedBytes,
+		metrics.GetOrRegisterGauge("producer-buffered-bytes", p.metricsRegistry))
+
+	if p.conf.Producer.Idempotent {
+		// the broker orders the batches of a partition by sequence number, so
+		// several of them may be in flight, but none may overtake a retry
+		p.muter.maxInFlight = p.conf.Net.MaxOpenRequests
+		p.muter.held = func(topic string, partition int32) bool {
+			return p.txnmgr.hasRetryingBatch(topic, partition)
+		}
+	}
 
 	// launch our singleton dispatchers
 	go withRecover(p.dispatcher)
@@ -344,6 +594,7 @@ const (
 	endtxn                        // endtxn
 	committxn                     // endtxn
 	aborttxn                      // endtxn
+	flush                         // flush the messages enqueued before it
 )
 
 // ProducerMessage is the collection of elements passed to the Producer in order to send a message.
@@ -366,6 +617,13 @@ type ProducerMessage struct {
 	// pass-through data.
 	Metadata any
 
+	// OnDelivery, when set, is called exactly once with the message and the
+	// error when it fails, or nil when it is successfully delivered,
+	// regardless of Producer.Return.Successes and Producer.Return.Errors. It
+	// is called before the message is returned on the Successes or Errors
+	// channel, from a goroutine of the producer: it must not block.
+	OnDelivery func(msg *ProducerMessage, err error)
+
 	// Below this point are filled in by the producer as the message is processed
 
 	// Offset is the offset of the message stored on the broker. This is only
@@ -394,6 +652,9 @@ type ProducerMessage struct {
 	sequenceNumber int32
 	producerEpoch  int16
 	hasSequence    bool
+	flushGen       uint64
+	flushed        chan struct{}
+	bufferedBytes  int
 }
 
 const producerMessageOverhead = 26 // the metadata overhead of CRC, flags, etc.
@@ -423,6 +684,7 @@ func (m *ProducerMessage) clear() {
 	m.sequenceNumber = 0
 	m.producerEpoch = 0
 	m.hasSequence = false
+	m.bufferedBytes = 0
 }
 
 // ProducerError is the type of error generated when the producer fails to deliver a message.
@@ -560,6 +822,42 @@ func (p *asyncProducer) Input() chan<- *ProducerMessage {
 	return p.input
 }
 
+func (p *asyncProducer) SendContext(ctx context.Context, msg *ProducerMessage) error {
+	p.inputLock.RLock()
+	defer p.inputLock.RUnlock()
+	if p.closed.Load() {
+		return ErrShuttingDown
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case p.input <- msg:
+		return nil
+	case <-p.done:
+		return ErrShuttingDown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *asyncProducer) Flush(ctx context.Context) error {
+	// the marker goes through the dispatcher so that it is ordered after
+	// every message already written to the Input channel
+	flushed := make(chan struct{})
+	if err := p.SendContext(ctx, &ProducerMessage{flags: flush, flushed: flushed}); err != nil {
+		return err
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-p.done:
+		return ErrShuttingDown
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (p *asyncProducer) Close() error {
 	p.AsyncClose()
 
@@ -595,7 +893,60 @@ func (p *asyncProducer) dispatcher() {
 	handlers := make(map[string]chan<- *ProducerMessage)
 	shuttingDown := false
 
-	for msg := range p.input {
+	route := func(msg *ProducerMessage) {
+		handler := handlers[msg.Topic]
+		if handler == nil {
+			handler = p.newTopicProducer(msg.Topic)
+			handlers[msg.Topic] = handler
+		}
+
+		handler <- msg
+	}
+
+	// blocked is the message waiting for room in Producer.MaxBufferedBytes,
+	// the Input channel is not read until it fits or Producer.MaxBlock expires
+	var (
+		blocked     *ProducerMessage
+		blockedSize int
+		room        <-chan struct{}
+		blockTimer  *time.Timer
+		blockExpiry <-chan time.Time
+	)
+
+	for {
+		input := p.input
+		if blocked != nil {
+			input = nil
+		}
+
+		var msg *ProducerMessage
+		select {
+		case m, ok := <-input:
+			if !ok {
+				for _, handler := range handlers {
+					close(handler)
+				}
+				return
+			}
+			msg = m
+		case msg = <-p.retried:
+		case <-room:
+			var fits bool
+			if room, fits = p.buffer.reserve(blocked, blockedSize); fits {
+				if blockTimer != nil {
+					blockTimer.Stop()
+				}
+				route(blocked)
+				blocked, blockTimer, blockExpiry = nil, nil, nil
+			}
+			continue
+		case <-blockExpiry:
+			p.buffer.unblock()
+			p.returnError(blocked, ErrProducerBufferFull)
+			blocked, room, blockTimer, blockExpiry = nil, nil, nil, nil
+			continue
+		}
+
 		if msg == nil {
 			Logger.Println("Something tried to send a nil message, it was ignored.")
 			continue
@@ -615,6 +966,11 @@ func (p *asyncProducer) dispatcher() {
 			continue
 		}
 
+		if msg.flags&flush != 0 {
+			p.flusher.start(msg.flushed)
+			continue
+		}
+
 		if msg.flags&shutdown != 0 {
 			shuttingDown = true
 			p.inFlight.Done()
@@ -625,6 +981,9 @@ func (p *asyncProducer) dispatcher() {
 			if shuttingDown {
 				// we can't just call returnError here because that decrements the wait group,
 				// which hasn't been incremented yet for this message, and shouldn't be
+				if msg.OnDelivery != nil {
+					msg.OnDelivery(msg, ErrShuttingDown)
+				}
 				pErr := &ProducerError{Msg: msg, Err: ErrShuttingDown}
 				if p.conf.Producer.Return.Errors {
 					p.errors <- pErr
@@ -634,6 +993,7 @@ func (p *asyncProducer) dispatcher() {
 				continue
 			}
 			p.inFlight.Add(1)
+			p.flusher.enqueued(msg)
 			// Ignore retried msg, there are already in txn.
 			// Can't produce new record when transaction is not started.
 			if p.IsTransactional() && p.txnmgr.currentTxnStatus()&ProducerTxnFlagInTransaction == 0 {
@@ -661,17 +1021,19 @@ func (p *asyncProducer) dispatcher() {
 			continue
 		}
 
-		handler := handlers[msg.Topic]
-		if handler == nil {
-			handler = p.newTopicProducer(msg.Topic)
-			handlers[msg.Topic] = handler
+		if msg.retries == 0 {
+			var fits bool
+			if room, fits = p.buffer.reserve(msg, size); !fits {
+				blocked, blockedSize = msg, size
+				if p.conf.Producer.MaxBlock > 0 {
+					blockTimer = time.NewTimer(p.conf.Producer.MaxBlock)
+					blockExpiry = blockTimer.C
+				}
+				continue
+			}
 		}
 
-		handler <- msg
-	}
-
-	for _, handler := range handlers {
-		close(handler)
+		route(msg)
 	}
 }
 
@@ -685,6 +1047,7 @@ type topicProducer struct {
 	breaker     *breaker.Breaker
 	handlers    map[int32]chan<- *ProducerMessage
 	partitioner Partitioner
+	load        producerLoad // given to an AdaptivePartitioner
 }
 
 func (p *asyncProducer) newTopicProducer(topic string) chan<- *ProducerMessage {
@@ -697,6 +1060,7 @@ func (p *asyncProducer) newTopicProducer(topic string) chan<- *ProducerMessage {
 		handlers:    make(map[int32]chan<- *ProducerMessage),
 		partitioner: p.conf.Producer.Partitioner(topic),
 	}
+	tp.load.tp = tp
 	go withRecover(tp.dispatch)
 	return input
 }
@@ -752,7 +1116,13 @@ func (tp *topicProducer) partitionMessage(msg *ProducerMessage) error {
 		return ErrLeaderNotAvailable
 	}
 
-	choice, err := tp.partitioner.Partition(msg, numPartitions)
+	var choice int32
+	if ap, ok := tp.partitioner.(AdaptivePartitioner); ok {
+		tp.load.reset(partitions)
+		choice, err = ap.PartitionAdaptive(msg, numPartitions, &tp.load)
+	} else {
+		choice, err = tp.partitioner.Partition(msg, numPartitions)
+	}
 
 	if err != nil {
 		return err
@@ -765,6 +1135,89 @@ func (tp *topicProducer) partitionMessage(msg *ProducerMessage) error {
 	return nil
 }
 
+// slowLeaderFactor is how many times the mean request latency of all the
+// leaders the one of a leader must exceed for its partitions to be busy.
+const slowLeaderFactor = 2
+
+// producerLoad implements ProducerLoad for the partitions of a topicProducer.
+type producerLoad struct {
+	tp         *topicProducer
+	partitions []int32
+
+	// mean request latency of the leader of each partition, and of all of
+	// them, computed when first needed
+	latencies   map[int32]float64
+	meanLatency float64
+}
+
+func (l *producerLoad) reset(partitions []int32) {
+	l.partitions = partitions
+	l.latencies = nil
+}
+
+func (l *producerLoad) BatchBytes() int {
+	return l.tp.parent.conf.Producer.Flush.Bytes
+}
+
+func (l *producerLoad) MessageBytes(message *ProducerMessage) int {
+	// the same record format as the dispatcher and the produceSet use
+	if l.tp.parent.conf.Version.IsAtLeast(V0_11_0_0) {
+		return message.ByteSize(2)
+	}
+	return message.ByteSize(1)
+}
+
+func (l *producerLoad) IsBusy(index int32) bool {
+	if index < 0 || int(index) >= len(l.partitions) {
+		return false
+	}
+	partition := l.partitions[index]
+	if l.tp.parent.muter.isBackedUp(l.tp.topic, partition) {
+		return true
+	}
+
+	if l.latencies == nil {
+		l.computeLatencies()
+	}
+	latency, ok := l.latencies[partition]
+	return ok && l.meanLatency > 0 && latency > slowLeaderFactor*l.meanLatency
+}
+
+// computeLatencies looks up the request latency of the leaders of the
+// partitions, which is only known when metrics are enabled.
+func (l *producerLoad) computeLatencies() {
+	l.latencies = make(map[int32]float64, len(l.partitions))
+	l.meanLatency = 0
+
+	registry := l.tp.parent.conf.MetricRegistry
+	byBroker := make(map[int32]float64)
+	for _, partition := range l.partitions {
+		leader, err := l.tp.parent.client.Leader(l.tp.topic, partition)
+		if err != nil {
+			continue
+		}
+		latency, ok := byBroker[leader.ID()]
+		if !ok {
+			histogram, isHistogram := registry.Get(getMetricNameForBroker("request-latency-in-ms", leader)).(metrics.Histogram)
+			if !isHistogram || histogram.Count() == 0 {
+				continue
+			}
+			latency = histogram.Mean()
+			byBroker[leader.ID()] = latency
+		}
+		l.latencies[partition] = latency
+	}
+
+	if len(byBroker) < 2 {
+		// no leader can be slower than the others
+		return
+	}
+	for _, latency := range byBroker {
+		l.meanLatency += latency
+	}
+	l.meanLatency /= float64(len(byBroker))
+}
+
 // one per partition per topic
 // dispatches messages to the appropriate broker
 // also responsible for maintaining message order during retries
@@ -1025,6 +1478,10 @@ func (p *asyncProducer) newBrokerProducer(broker *Broker) *brokerProducer {
 				wg.Done()
 			}
 
+			if p.conf.Producer.Idempotent {
+				p.batchesSent(set)
+			}
+
 			if p.IsTransactional() {
 				// Add partition to tx before sending current batch
 				err := p.txnmgr.publishTxnPartitions()
@@ -1141,6 +1598,9 @@ func (bp *brokerProducer) run() {
 			output = nil
 		}
 
+		flushSignal := bp.parent.flusher.flushSignal()
+		fullSignal := bp.parent.buffer.fullSignal()
+
 		select {
 		case msg, ok := <-bp.input:
 			if !ok {
@@ -1206,11 +1666,24 @@ func (bp *brokerProducer) run() {
 				continue
 			}
 
-			if bp.parent.conf.Producer.Flush.Frequency > 0 && bp.timer == nil {
+			if bp.parent.flusher.flushing() || bp.parent.buffer.waiting() {
+				// a Flush or a message waiting for room in Producer.MaxBufferedBytes
+				// is waiting, don't hold messages back for the thresholds
+				bp.timerFired = true
+			} else if bp.parent.conf.Producer.Flush.Frequency > 0 && bp.timer == nil {
 				bp.timer = time.NewTimer(bp.parent.conf.Producer.Flush.Frequency)
 			}
 		case <-timerChan:
 			bp.timerFired = true
+		case <-flushSignal:
+			if !bp.accumulatingBatch.empty() {
+				bp.timerFired = true
+			}
+		case <-fullSignal:
+			// send the buffered messages right away to make room
+			if !bp.accumulatingBatch.empty() {
+				bp.timerFired = true
+			}
 		case output <- bp.flushingBatch:
 			bp.flushingBatch = nil
 		case response, ok := <-bp.responses:
@@ -1411,6 +1884,21 @@ func (bp *brokerProducer) handleSuccess(sent *produceSet, response *ProduceRespo
 					keepMuted[topic][partition] = struct{}{}
 				}
 			}
+		// Out of order because of a batch that is retried, or that failed
+		// and bumped the epoch: retry once the earlier batches are sent again
+		case ErrOutOfOrderSequenceNumber:
+			if bp.canRetryOutOfOrder(topic, partition, pSet) {
+				retryTopics = append(retryTopics, topic)
+				if keepMuted[topic] == nil {
+					keepMuted[topic] = make(map[int32]struct{})
+				}
+				keepMuted[topic][partition] = struct{}{}
+				return
+			}
+			if bp.parent.conf.Producer.Retry.Max <= 0 {
+				bp.parent.abandonBrokerConnection(bp.broker)
+			}
+			bp.parent.returnErrors(pSet.msgs, block.Err)
 		// Other non-retriable errors
 		default:
 			if bp.parent.conf.Producer.Retry.Max <= 0 {
@@ -1445,12 +1933,24 @@ func (bp *brokerProducer) handleSuccess(sent *produceSet, response *ProduceRespo
 				}
 				bp.currentRetries[topic][partition] = block.Err
 				if bp.parent.conf.Producer.Idempotent {
+					bp.parent.txnmgr.batchRetrying(topic, partition, pSet)
 					go bp.parent.retryBatch(topic, partition, pSet, block.Err, true)
 				} else {
 					bp.parent.retryMessages(pSet.msgs, block.Err)
 				}
 				// dropping the following messages has the side effect of incrementing their retry count
 				bp.parent.retryMessages(bp.accumulatingBatch.dropPartition(topic, partition), block.Err)
+			case ErrOutOfOrderSequenceNumber:
+				if _, kept := keepMuted[topic][partition]; !kept {
+					// returned as an error in the previous "eachPartition" loop
+					return
+				}
+				if bp.parent.txnmgr.resequenceBatch(topic, partition, pSet) {
+					Logger.Printf("producer/broker/%d re-sequenced batch on %s/%d after an epoch bump\n",
+						bp.broker.ID(), topic, partition)
+				}
+				bp.parent.txnmgr.batchRetrying(topic, partition, pSet)
+				go bp.parent.retryBatch(topic, partition, pSet, block.Err, true)
 			}
 		})
 	}
@@ -1462,7 +1962,48 @@ func (bp *brokerProducer) handleSuccess(sent *produceSet, response *ProduceRespo
 		}
 		return true
 	})
-	bp.parent.muter.unmute(unmuteSet)
+	bp.parent.completeBatches(unmuteSet)
+}
+
+// canRetryOutOfOrder reports whether a batch of an idempotent producer
+// rejected as out of order can be retried: either a batch sent before it is
+// retried, or the batch predates an epoch bump and can be re-sequenced.
+// Otherwise the broker disagrees with the sequence numbers sent so far.
+func (bp *brokerProducer) canRetryOutOfOrder(topic string, partition int32, pSet *partitionSet) bool {
+	if !bp.parent.conf.Producer.Idempotent || bp.parent.conf.Producer.Retry.Max <= 0 {
+		return false
+	}
+	if !bp.parent.txnmgr.isOldestInFlight(topic, partition, pSet) {
+		return true
+	}
+	producerID, epoch := bp.parent.txnmgr.getProducerID()
+	rb := pSet.recordsToSend.RecordBatch
+	return rb != nil && (rb.ProducerID != producerID || rb.ProducerEpoch != epoch)
+}
+
+// batchesSent records the batches of an idempotent producer handed over to a
+// broker, releasing their partitions if they were retried.
+func (p *asyncProducer) batchesSent(set *produceSet) {
+	var retried bool
+	set.eachPartition(func(topic string, partition int32, pSet *partitionSet) {
+		if p.txnmgr.batchSent(topic, partition, pSet) {
+			retried = true
+		}
+	})
+	if retried {
+		p.muter.release()
+	}
+}
+
+// completeBatches unmutes the partitions of batches that succeeded or failed
+// for good, and stops tracking them when the producer is idempotent.
+func (p *asyncProducer) completeBatches(set *produceSet) {
+	if p.conf.Producer.Idempotent {
+		set.eachPartition(func(topic string, partition int32, pSet *partitionSet) {
+			p.txnmgr.batchCompleted(topic, partition, pSet)
+		})
+	}
+	p.muter.unmute(set)
 }
 
 func (p *asyncProducer) retryBatch(topic string, partition int32, pSet *partitionSet, retryErr error, alreadyMuted bool) {
@@ -1476,7 +2017,7 @@ func (p *asyncProducer) retryBatch(topic string, partition int32, pSet *partitio
 		if msg.retries >= p.conf.Producer.Retry.Max {
 			p.returnErrors(pSet.msgs, retryErr)
 			if alreadyMuted {
-				p.muter.unmute(produceSet)
+				p.completeBatches(produceSet)
 			}
 			return
 		}
@@ -1489,6 +2030,9 @@ func (p *asyncProducer) retryBatch(topic string, partition int32, pSet *partitio
 	if len(pSet.msgs) > 0 {
 		p.backoff(pSet.msgs[0].retries)
 	}
+	if p.conf.Producer.Idempotent && alreadyMuted {
+		p.txnmgr.awaitRetryTurn(topic, partition, pSet)
+	}
 
 	// it's expected that a metadata refresh has been requested prior to calling retryBatch
 	leader, leaderErr := p.client.Leader(topic, partition)
@@ -1498,7 +2042,7 @@ func (p *asyncProducer) retryBatch(topic string, partition int32, pSet *partitio
 			p.returnError(msg, retryErr)
 		}
 		if alreadyMuted {
-			p.muter.unmute(produceSet)
+			p.completeBatches(produceSet)
 		}
 		return
 	}
@@ -1518,7 +2062,7 @@ func (p *asyncProducer) retryBatch(topic string, partition int32, pSet *partitio
 		for _, msg := range pSet.msgs {
 			p.returnError(msg, ErrShuttingDown)
 		}
-		p.muter.unmute(produceSet)
+		p.completeBatches(produceSet)
 	}
 }
 
@@ -1528,7 +2072,7 @@ func (bp *brokerProducer) handleError(sent *produceSet, err error) {
 		sent.eachPartition(func(topic string, partition int32, pSet *partitionSet) {
 			bp.parent.returnErrors(pSet.msgs, err)
 		})
-		bp.parent.muter.unmute(sent)
+		bp.parent.completeBatches(sent)
 	} else {
 		Logger.Printf("producer/broker/%d state change to [closing] because %s\n", bp.broker.ID(), err)
 		bp.parent.abandonBrokerConnection(bp.broker)
@@ -1562,6 +2106,9 @@ func (bp *brokerProducer) handleError(sent *produceSet, err error) {
 				keepMuted[topic] = make(map[int32]struct{})
 			}
 			keepMuted[topic][partition] = struct{}{}
+			if bp.parent.conf.Producer.Idempotent {
+				bp.parent.txnmgr.batchRetrying(topic, partition, pSet)
+			}
 			go bp.parent.retryBatch(topic, partition, pSet, err, true)
 		})
 		bp.accumulatingBatch.eachPartition(func(topic string, partition int32, pSet *partitionSet) {
@@ -1576,7 +2123,7 @@ func (bp *brokerProducer) handleError(sent *produceSet, err error) {
 			}
 			return true
 		})
-		bp.parent.muter.unmute(unmuteSet)
+		bp.parent.completeBatches(unmuteSet)
 	}
 }
 
@@ -1609,7 +2156,7 @@ func (p *asyncProducer) retryHandler() {
 		} else {
 			select {
 			case msg = <-p.retries:
-			case p.input <- buf.Peek():
+			case p.retried <- buf.Peek():
 				msgToRemove := buf.Remove()
 				currentByteSize -= int64(msgToRemove.ByteSize(version))
 				continue
@@ -1630,7 +2177,7 @@ func (p *asyncProducer) retryHandler() {
 		msgToHandle := buf.Peek()
 		if msgToHandle.flags == 0 {
 			select {
-			case p.input <- msgToHandle:
+			case p.retried <- msgToHandle:
 				buf.Remove()
 				currentByteSize -= int64(msgToHandle.ByteSize(version))
 			default:
@@ -1661,7 +2208,9 @@ func (p *asyncProducer) shutdown() {
 
 	p.muter.close()
 
+	p.inputLock.Lock()
 	close(p.input)
+	p.inputLock.Unlock()
 	close(p.retries)
 	close(p.errors)
 	close(p.successes)
@@ -1709,13 +2258,20 @@ func (p *asyncProducer) returnError(msg *ProducerMessage, err error) {
 		p.bumpIdempotentProducerEpoch()
 	}
 
+	// read before the message is handed back and possibly sent again
+	gen, size := msg.flushGen, msg.bufferedBytes
 	msg.clear()
+	if msg.OnDelivery != nil {
+		msg.OnDelivery(msg, err)
+	}
 	pErr := &ProducerError{Msg: msg, Err: err}
 	if p.conf.Producer.Return.Errors {
 		p.errors <- pErr
 	} else {
 		Logger.Println(pErr)
 	}
+	p.buffer.release(size)
+	p.flusher.completed(gen)
 	p.inFlight.Done()
 }
 
@@ -1727,10 +2283,16 @@ func (p *asyncProducer) returnErrors(batch []*ProducerMessage, err error) {
 
 func (p *asyncProducer) returnSuccesses(batch []*ProducerMessage) {
 	for _, msg := range batch {
+		gen, size := msg.flushGen, msg.bufferedBytes
+		msg.clear()
+		if msg.OnDelivery != nil {
+			msg.OnDelivery(msg, nil)
+		}
 		if p.conf.Producer.Return.Successes {
-			msg.clear()
 			p.successes <- msg
 		}
+		p.buffer.release(size)
+		p.flusher.completed(gen)
 		p.inFlight.Done()
 	}
 }
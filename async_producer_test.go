@@ -3,6 +3,7 @@
 package sarama
 
 import (
+	"context"
 	"errors"
 	"log"
 	"math"
@@ -15,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -219,6 +221,384 @@ func TestAsyncProducerMultipleFlushes(t *testing.T) {
 	seedBroker.Close()
 }
 
+func TestAsyncProducerOnDelivery(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	leader := NewMockBroker(t, 2)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+
+	prodSuccess := new(ProduceResponse)
+	prodSuccess.AddTopicPartition("my_topic", 0, ErrNoError)
+	leader.Returns(prodSuccess)
+	prodTooLarge := new(ProduceResponse)
+	prodTooLarge.AddTopicPartition("my_topic", 0, ErrMessageSizeTooLarge)
+	leader.Returns(prodTooLarge)
+
+	config := NewTestConfig()
+	config.Producer.Flush.Messages = 1
+	config.Producer.Return.Successes = true
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type delivery struct {
+		msg *ProducerMessage
+		err error
+	}
+	delivered := make(chan delivery, 2)
+	onDelivery := func(msg *ProducerMessage, err error) {
+		delivered <- delivery{msg, err}
+	}
+
+	ok := &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage), OnDelivery: onDelivery}
+	require.NoError(t, producer.SendContext(context.Background(), ok))
+	d := <-delivered
+	require.Same(t, ok, d.msg)
+	require.NoError(t, d.err)
+	require.Same(t, ok, <-producer.Successes(), "the message is also returned on the Successes channel")
+
+	tooLarge := &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage), OnDelivery: onDelivery}
+	require.NoError(t, producer.SendContext(context.Background(), tooLarge))
+	d = <-delivered
+	require.Same(t, tooLarge, d.msg)
+	require.ErrorIs(t, d.err, ErrMessageSizeTooLarge)
+	require.Same(t, tooLarge, (<-producer.Errors()).Msg, "the message is also returned on the Errors channel")
+
+	closeProducer(t, producer)
+	require.Empty(t, delivered, "OnDelivery must be called once per message")
+	leader.Close()
+	seedBroker.Close()
+}
+
+func TestAsyncProducerSendContext(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+
+	prodSuccess := new(ProduceResponse)
+	prodSuccess.AddTopicPartition("my_topic", 0, ErrNoError)
+	leader.Returns(prodSuccess)
+
+	config := NewTestConfig()
+	config.Producer.Return.Successes = true
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = producer.SendContext(ctx, &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, producer.SendContext(context.Background(), &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}))
+	expectResults(t, producer, 1, 0)
+
+	closeProducer(t, producer)
+	err = producer.SendContext(context.Background(), &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)})
+	require.ErrorIs(t, err, ErrShuttingDown)
+}
+
+func TestAsyncProducerSendContextClose(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+	leader.SetLatency(500 * time.Millisecond)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+	leader.SetHandlerByMap(map[string]MockResponse{
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.MaxBufferedBytes = 1
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+
+	// the second message waits for the first one to be acknowledged, so
+	// the Input channel is not read and SendContext blocks
+	for range 2 {
+		producer.Input() <- &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}
+	}
+	sent := make(chan error, 1)
+	go func() {
+		sent <- producer.SendContext(context.Background(), &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		closeProducer(t, producer)
+	}()
+
+	select {
+	case err := <-sent:
+		require.ErrorIs(t, err, ErrShuttingDown)
+	case <-time.After(250 * time.Millisecond):
+		t.Fatal("SendContext should return once the producer is closing")
+	}
+	<-closed
+}
+
+func TestAsyncProducerFlush(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+
+	// a flush may send the messages in any number of requests
+	leader.SetHandlerByMap(map[string]MockResponse{
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	config := NewTestConfig()
+	// without Flush, nothing would be sent before an hour or 10 messages
+	config.Producer.Flush.Messages = 10
+	config.Producer.Flush.Frequency = time.Hour
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, producer.Flush(ctx), "flushing an idle producer")
+
+	var delivered atomic.Int32
+	onDelivery := func(msg *ProducerMessage, err error) {
+		assert.NoError(t, err)
+		delivered.Add(1)
+	}
+	for round := 1; round <= 2; round++ {
+		for range 3 {
+			producer.Input() <- &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage), OnDelivery: onDelivery}
+		}
+		require.NoError(t, producer.Flush(ctx))
+		require.Equal(t, int32(3*round), delivered.Load(), "messages delivered after flush #%d", round)
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	require.ErrorIs(t, producer.Flush(cancelled), context.Canceled)
+
+	closeProducer(t, producer)
+	require.ErrorIs(t, producer.Flush(ctx), ErrShuttingDown)
+}
+
+func TestAsyncProducerFlushClose(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+	leader.SetLatency(500 * time.Millisecond)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+	leader.SetHandlerByMap(map[string]MockResponse{
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, NewTestConfig())
+	require.NoError(t, err)
+
+	// the message is acknowledged only after the latency, so Flush is
+	// still waiting for it when the producer is closed
+	producer.Input() <- &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}
+	flushed := make(chan error, 1)
+	go func() {
+		flushed <- producer.Flush(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		closeProducer(t, producer)
+	}()
+
+	select {
+	case err := <-flushed:
+		require.ErrorIs(t, err, ErrShuttingDown)
+	case <-time.After(250 * time.Millisecond):
+		t.Fatal("Flush should return once the producer is closing")
+	}
+	<-closed
+}
+
+func TestAsyncProducerMaxBufferedBytes(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+	leader.SetLatency(200 * time.Millisecond)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+	leader.SetHandlerByMap(map[string]MockResponse{
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	msgSize := (&ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}).ByteSize(2)
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.MaxBufferedBytes = msgSize
+	config.Producer.Return.Successes = true
+	// the first message is only sent because the second one waits for room
+	config.Producer.Flush.Messages = 10
+	config.Producer.Flush.Frequency = time.Hour
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	buffered := config.MetricRegistry.Get("producer-buffered-bytes").(metrics.Gauge)
+
+	// the first message fills the budget and the second one waits for room,
+	// so the third one can't be written before the first one is acknowledged
+	for range 2 {
+		producer.Input() <- &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = producer.SendContext(ctx, &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int64(msgSize), buffered.Value())
+	require.False(t, producer.(*asyncProducer).flusher.flushing(), "waiting for room is not a Flush")
+
+	// the second message is held back by the thresholds again once it fits
+	expectResults(t, producer, 1, 0)
+	flushed := make(chan error, 1)
+	go func() {
+		flushed <- producer.Flush(context.Background())
+	}()
+	expectResults(t, producer, 1, 0)
+	require.NoError(t, <-flushed)
+	require.Zero(t, buffered.Value())
+	closeProducer(t, producer)
+}
+
+func TestAsyncProducerMaxBlock(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	leader := NewMockBroker(t, 2)
+	defer leader.Close()
+	leader.SetLatency(200 * time.Millisecond)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+	leader.SetHandlerByMap(map[string]MockResponse{
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	msgSize := (&ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}).ByteSize(2)
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.MaxBufferedBytes = 2 * msgSize
+	config.Producer.MaxBlock = 20 * time.Millisecond
+	config.Producer.Return.Successes = true
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+
+	for i := range 3 {
+		producer.Input() <- &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage), Metadata: i}
+	}
+
+	pErr := <-producer.Errors()
+	require.ErrorIs(t, pErr, ErrProducerBufferFull)
+	require.Equal(t, 2, pErr.Msg.Metadata, "the message not fitting in the budget should fail")
+	expectResults(t, producer, 2, 0)
+	closeProducer(t, producer)
+}
+
+func TestAsyncProducerStickyPartitioner(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	leader0 := NewMockBroker(t, 2)
+	leader1 := NewMockBroker(t, 3)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader0.Addr(), leader0.BrokerID())
+	metadataResponse.AddBroker(leader1.Addr(), leader1.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader0.BrokerID(), nil, nil, nil, ErrNoError)
+	metadataResponse.AddTopicPartition("my_topic", 1, leader1.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+
+	prodResponse0 := new(ProduceResponse)
+	prodResponse0.AddTopicPartition("my_topic", 0, ErrNoError)
+	leader0.Returns(prodResponse0)
+
+	prodResponse1 := new(ProduceResponse)
+	prodResponse1.AddTopicPartition("my_topic", 1, ErrNoError)
+	leader1.Returns(prodResponse1)
+
+	msgSize := (&ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}).ByteSize(2)
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.Flush.Messages = 5
+	config.Producer.Flush.Bytes = 5 * msgSize
+	config.Producer.Return.Successes = true
+	config.Producer.Partitioner = NewStickyPartitioner
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		producer.Input() <- &ProducerMessage{Topic: "my_topic", Key: nil, Value: StringEncoder(TestMessage)}
+	}
+	partitions := make(map[int32]int)
+	for range 10 {
+		select {
+		case msg := <-producer.Successes():
+			partitions[msg.Partition]++
+		case err := <-producer.Errors():
+			t.Fatal(err)
+		}
+	}
+	// each partition is stuck to until a batch of 5 messages is filled
+	require.Equal(t, map[int32]int{0: 5, 1: 5}, partitions)
+
+	closeProducer(t, producer)
+	leader1.Close()
+	leader0.Close()
+	seedBroker.Close()
+}
+
+func TestProducerLoadMessageBytes(t *testing.T) {
+	msg := &ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)}
+	for _, tc := range []struct {
+		version       KafkaVersion
+		recordVersion int
+	}{
+		{V0_10_2_0, 1},
+		{V0_11_0_0, 2},
+	} {
+		config := NewTestConfig()
+		config.Version = tc.version
+		load := &producerLoad{tp: &topicProducer{parent: &asyncProducer{conf: config}}}
+		require.Equal(t, msg.ByteSize(tc.recordVersion), load.MessageBytes(msg), "version %s", tc.version)
+	}
+}
+
 func TestAsyncProducerMultipleBrokers(t *testing.T) {
 	seedBroker := NewMockBroker(t, 1)
 	leader0 := NewMockBroker(t, 2)
@@ -1446,6 +1826,106 @@ func TestAsyncProducerIdempotentRetryCheckBatch_2378(t *testing.T) {
 	closeProducer(t, producer)
 }
 
+// TestAsyncProducerIdempotentPipelinedRetry checks that the batches pipelined
+// behind a failed one, which the broker rejects as out of order, are retried
+// after it so that every sequence number is written once and in order.
+func TestAsyncProducerIdempotentPipelinedRetry(t *testing.T) {
+	broker := NewMockBroker(t, 1)
+
+	metadataResponse := &MetadataResponse{
+		Version:      4,
+		ControllerID: 1,
+	}
+	metadataResponse.AddBroker(broker.Addr(), broker.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, broker.BrokerID(), nil, nil, nil, ErrNoError)
+
+	initProducerIDResponse := &InitProducerIDResponse{
+		ThrottleTime:  0,
+		ProducerID:    1000,
+		ProducerEpoch: 1,
+	}
+
+	newProduceResponse := func(kerr KError) *ProduceResponse {
+		res := &ProduceResponse{Version: 3}
+		res.AddTopicPartition("my_topic", 0, kerr)
+		return res
+	}
+
+	var (
+		lock       sync.Mutex
+		written    []int32
+		outOfOrder int
+		failed     bool
+	)
+	handler := func(req *request) (res encoderWithHeader) {
+		switch req.body.key() {
+		case 3:
+			return metadataResponse
+		case 22:
+			return initProducerIDResponse
+		case 0:
+			lock.Lock()
+			defer lock.Unlock()
+
+			batch := req.body.(*ProduceRequest).records["my_topic"][0].RecordBatch
+			if !failed {
+				// let the following batches be sent before failing the first one
+				failed = true
+				time.Sleep(50 * time.Millisecond)
+				return newProduceResponse(ErrNotEnoughReplicas)
+			}
+			next := int32(len(written))
+			switch {
+			case batch.FirstSequence == next:
+				for i := range batch.Records {
+					written = append(written, batch.FirstSequence+int32(i))
+				}
+				return newProduceResponse(ErrNoError)
+			case batch.FirstSequence < next:
+				return newProduceResponse(ErrDuplicateSequenceNumber)
+			default:
+				outOfOrder++
+				return newProduceResponse(ErrOutOfOrderSequenceNumber)
+			}
+		}
+		return nil
+	}
+
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.Idempotent = true
+	config.Net.MaxOpenRequests = 5
+	config.Producer.RequiredAcks = WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Flush.Messages = 1
+	config.Producer.Retry.Max = 1 // each batch may only be retried once, in order
+	var backoffs atomic.Int32
+	config.Producer.Retry.BackoffFunc = func(retries, maxRetries int) time.Duration {
+		// give the following batches a chance to overtake the failed one
+		if backoffs.Add(1) == 1 {
+			return 50 * time.Millisecond
+		}
+		return 0
+	}
+
+	broker.setHandler(handler)
+	producer, err := NewAsyncProducer([]string{broker.Addr()}, config)
+	require.NoError(t, err)
+
+	for range 5 {
+		producer.Input() <- &ProducerMessage{Topic: "my_topic", Key: nil, Value: StringEncoder(TestMessage)}
+	}
+	expectResults(t, producer, 5, 0)
+
+	broker.Close()
+	closeProducer(t, producer)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []int32{0, 1, 2, 3, 4}, written)
+	require.Positive(t, outOfOrder, "the batches pipelined behind the failed one must be rejected as out of order")
+}
+
 // test case for https://github.com/IBM/sarama/issues/2469: idempotent producer
 // retries via retryBatch must honor Retry.Backoff / Retry.BackoffFunc when the
 // broker repeatedly returns a retriable error.
@@ -1747,9 +2227,11 @@ func TestBrokerProducerShutdown(t *testing.T) {
 func TestBrokerProducerWaitForSpaceEmptyBufferRollover(t *testing.T) {
 	config := NewTestConfig()
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: &transactionManager{},
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  &transactionManager{},
 	}
 
 	bp := &brokerProducer{
@@ -1820,9 +2302,11 @@ func TestBrokerProducerWaitForSpaceRespectsExternalUnmute(t *testing.T) {
 		sequenceNumbers: make(map[string]int32),
 	}
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: txnMgr,
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  txnMgr,
 	}
 
 	externallyMutedSet := newProduceSet(parent)
@@ -1862,9 +2346,11 @@ func TestBrokerProducerWaitForSpaceRespectsExternalUnmute(t *testing.T) {
 func TestBrokerProducerFlushSkipsMutedPartitions(t *testing.T) {
 	config := NewTestConfig()
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: &transactionManager{},
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  &transactionManager{},
 	}
 	bp := &brokerProducer{
 		parent:            parent,
@@ -1904,9 +2390,11 @@ func TestBrokerProducerFlushSkipsMutedPartitions(t *testing.T) {
 func TestBrokerProducerWaitForSpaceAllPartitionsMuted(t *testing.T) {
 	config := NewTestConfig()
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: &transactionManager{},
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  &transactionManager{},
 	}
 
 	blockedSet := newProduceSet(parent)
@@ -1939,9 +2427,11 @@ func TestBrokerProducerWaitForSpaceAllPartitionsMuted(t *testing.T) {
 func TestPartitionMuterCloseWakesWaitUntilMuted(t *testing.T) {
 	config := NewTestConfig()
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: &transactionManager{},
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  &transactionManager{},
 	}
 
 	blockedSet := newProduceSet(parent)
@@ -1979,9 +2469,11 @@ func TestBrokerProducerRollOverClearsTimer(t *testing.T) {
 	config := NewTestConfig()
 	config.Producer.Flush.Frequency = 10 * time.Millisecond
 	parent := &asyncProducer{
-		conf:   config,
-		muter:  newPartitionMuter(),
-		txnmgr: &transactionManager{},
+		conf:    config,
+		muter:   newPartitionMuter(),
+		flusher: newFlushTracker(),
+		buffer:  newBufferBudget(0, metrics.NilGauge{}),
+		txnmgr:  &transactionManager{},
 	}
 	output := make(chan *produceSet, 2)
 	responses := make(chan *brokerProducerResponse)
@@ -2042,6 +2534,8 @@ func TestRetryBatchRespectsPartitionMuter(t *testing.T) {
 	parent := &asyncProducer{
 		conf:       config,
 		muter:      newPartitionMuter(),
+		flusher:    newFlushTracker(),
+		buffer:     newBufferBudget(0, metrics.NilGauge{}),
 		brokers:    make(map[*Broker]*brokerProducer),
 		brokerRefs: make(map[*brokerProducer]int),
 		txnmgr:     txnMgr,
@@ -2093,6 +2587,8 @@ func TestRetryBatchReleasesMuteOnShutdown(t *testing.T) {
 	parent := &asyncProducer{
 		conf:       config,
 		muter:      newPartitionMuter(),
+		flusher:    newFlushTracker(),
+		buffer:     newBufferBudget(0, metrics.NilGauge{}),
 		brokers:    make(map[*Broker]*brokerProducer),
 		brokerRefs: make(map[*brokerProducer]int),
 		errors:     make(chan *ProducerError, 1),
@@ -2145,6 +2641,8 @@ func TestBrokerProducerHandleError(t *testing.T) {
 		parent := &asyncProducer{
 			conf:       config,
 			muter:      newPartitionMuter(),
+			flusher:    newFlushTracker(),
+			buffer:     newBufferBudget(0, metrics.NilGauge{}),
 			brokers:    make(map[*Broker]*brokerProducer),
 			brokerRefs: make(map[*brokerProducer]int),
 			retries:    make(chan *ProducerMessage, 4),
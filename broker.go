@@ -128,6 +128,20 @@ type SCRAMClient interface {
 	Done() bool
 }
 
+// SCRAMClientWithExtensions is a SCRAMClient that can send SCRAM extensions
+// (RFC 5802 section 5.1) in its client-first message. It is required to
+// authenticate with delegation tokens, see Net.SASL.SCRAMTokenAuth.
+type SCRAMClientWithExtensions interface {
+	SCRAMClient
+	// BeginWithExtensions prepares the client for the SCRAM exchange like
+	// Begin, and appends the given extensions to the client-first message
+	// as comma separated key=value attributes.
+	BeginWithExtensions(userName, password, authzID string, extensions map[string]string) error
+}
+
+// scramExtensionTokenAuth marks the SCRAM credentials as a delegation token.
+const scramExtensionTokenAuth = "tokenauth"
+
 type responsePromise struct {
 	requestTime   time.Time
 	correlationID int32
@@ -343,6 +357,20 @@ func (b *Broker) ResponseSize() int {
 	return len(b.responses)
 }
 
+// supportsAPIKey reports whether the broker advertised the given api key in its
+// ApiVersionsResponse. If no ApiVersionsResponse was received (see
+// Config.ApiVersionsRequest) every api key is assumed to be supported.
+func (b *Broker) supportsAPIKey(key int16) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.brokerAPIVersions == nil {
+		return true
+	}
+	_, ok := b.brokerAPIVersions[key]
+	return ok
+}
+
 // Connected returns true if the broker is connected and false otherwise. If the broker is not
 // connected but it had tried to connect, the error from that connection attempt is also returned.
 func (b *Broker) Connected() (bool, error) {
@@ -864,6 +892,19 @@ func (b *Broker) InitProducerID(request *InitProducerIDRequest) (*InitProducerID
 	return response, nil
 }
 
+// OffsetForLeaderEpoch sends a request to look up the end offset of a leader
+// epoch for partitions led by this broker
+func (b *Broker) OffsetForLeaderEpoch(request *OffsetForLeaderEpochRequest) (*OffsetForLeaderEpochResponse, error) {
+	response := new(OffsetForLeaderEpochResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // AddPartitionsToTxn send a request to add partition to txn and returns
 // a response or error
 func (b *Broker) AddPartitionsToTxn(request *AddPartitionsToTxnRequest) (*AddPartitionsToTxnResponse, error) {
@@ -902,6 +943,19 @@ func (b *Broker) EndTxn(request *EndTxnRequest) (*EndTxnResponse, error) {
 	return response, nil
 }
 
+// WriteTxnMarkers sends a request to write transaction markers and returns a
+// response or error
+func (b *Broker) WriteTxnMarkers(request *WriteTxnMarkersRequest) (*WriteTxnMarkersResponse, error) {
+	response := new(WriteTxnMarkersResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // TxnOffsetCommit sends a request to commit transaction offsets and returns
 // a response or error
 func (b *Broker) TxnOffsetCommit(request *TxnOffsetCommitRequest) (*TxnOffsetCommitResponse, error) {
@@ -974,6 +1028,19 @@ func (b *Broker) DeleteOffsets(request *DeleteOffsetsRequest) (*DeleteOffsetsRes
 	return response, nil
 }
 
+// AlterReplicaLogDirs sends a request to move replicas between the broker's
+// log dirs and returns a response or error
+func (b *Broker) AlterReplicaLogDirs(request *AlterReplicaLogDirsRequest) (*AlterReplicaLogDirsResponse, error) {
+	response := new(AlterReplicaLogDirsResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // DescribeLogDirs sends a request to get the broker's log dir paths and sizes
 func (b *Broker) DescribeLogDirs(request *DescribeLogDirsRequest) (*DescribeLogDirsResponse, error) {
 	response := new(DescribeLogDirsResponse)
@@ -986,6 +1053,58 @@ func (b *Broker) DescribeLogDirs(request *DescribeLogDirsRequest) (*DescribeLogD
 	return response, nil
 }
 
+// CreateDelegationToken sends a create delegation token request and returns
+// create delegation token response or error
+func (b *Broker) CreateDelegationToken(request *CreateDelegationTokenRequest) (*CreateDelegationTokenResponse, error) {
+	response := new(CreateDelegationTokenResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RenewDelegationToken sends a renew delegation token request and returns
+// renew delegation token response or error
+func (b *Broker) RenewDelegationToken(request *RenewDelegationTokenRequest) (*RenewDelegationTokenResponse, error) {
+	response := new(RenewDelegationTokenResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ExpireDelegationToken sends an expire delegation token request and returns
+// expire delegation token response or error
+func (b *Broker) ExpireDelegationToken(request *ExpireDelegationTokenRequest) (*ExpireDelegationTokenResponse, error) {
+	response := new(ExpireDelegationTokenResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DescribeDelegationToken sends a describe delegation token request and returns
+// describe delegation token response or error
+func (b *Broker) DescribeDelegationToken(request *DescribeDelegationTokenRequest) (*DescribeDelegationTokenResponse, error) {
+	response := new(DescribeDelegationTokenResponse)
+
+	err := b.sendAndReceive(request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // DescribeUserScramCredentials sends a request to get SCRAM users
 func (b *Broker) DescribeUserScramCredentials(req *DescribeUserScramCredentialsRequest) (*DescribeUserScramCredentialsResponse, error) {
 	res := new(DescribeUserScramCredentialsResponse)
@@ -1060,6 +1179,162 @@ func (b *Broker) ListTransactions(req *ListTransactionsRequest) (*ListTransactio
 	return res, nil
 }
 
+// ConsumerGroupHeartbeat sends a consumer group heartbeat request and
+// returns consumer group heartbeat response or error
+func (b *Broker) ConsumerGroupHeartbeat(req *ConsumerGroupHeartbeatRequest) (*ConsumerGroupHeartbeatResponse, error) {
+	res := new(ConsumerGroupHeartbeatResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ConsumerGroupDescribe sends a consumer group describe request and returns
+// consumer group describe response or error
+func (b *Broker) ConsumerGroupDescribe(req *ConsumerGroupDescribeRequest) (*ConsumerGroupDescribeResponse, error) {
+	res := new(ConsumerGroupDescribeResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetTelemetrySubscriptions sends a get telemetry subscriptions request and
+// returns get telemetry subscriptions response or error
+func (b *Broker) GetTelemetrySubscriptions(req *GetTelemetrySubscriptionsRequest) (*GetTelemetrySubscriptionsResponse, error) {
+	res := new(GetTelemetrySubscriptionsResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PushTelemetry sends a push telemetry request and returns push telemetry
+// response or error
+func (b *Broker) PushTelemetry(req *PushTelemetryRequest) (*PushTelemetryResponse, error) {
+	res := new(PushTelemetryResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DescribeTopicPartitions sends a describe topic partitions request and
+// returns describe topic partitions response or error
+func (b *Broker) DescribeTopicPartitions(req *DescribeTopicPartitionsRequest) (*DescribeTopicPartitionsResponse, error) {
+	res := new(DescribeTopicPartitionsResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ShareGroupHeartbeat sends a share group heartbeat request and returns
+// share group heartbeat response or error
+func (b *Broker) ShareGroupHeartbeat(req *ShareGroupHeartbeatRequest) (*ShareGroupHeartbeatResponse, error) {
+	res := new(ShareGroupHeartbeatResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ShareFetch sends a share fetch request and returns
+// share fetch response or error
+func (b *Broker) ShareFetch(req *ShareFetchRequest) (*ShareFetchResponse, error) {
+	res := new(ShareFetchResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ShareAcknowledge sends a share acknowledge request and returns
+// share acknowledge response or error
+func (b *Broker) ShareAcknowledge(req *ShareAcknowledgeRequest) (*ShareAcknowledgeResponse, error) {
+	res := new(ShareAcknowledgeResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DescribeQuorum sends a describe quorum request and returns
+// describe quorum response or error
+func (b *Broker) DescribeQuorum(req *DescribeQuorumRequest) (*DescribeQuorumResponse, error) {
+	res := new(DescribeQuorumResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UnregisterBroker sends a unregister broker request and returns
+// unregister broker response or error
+func (b *Broker) UnregisterBroker(req *UnregisterBrokerRequest) (*UnregisterBrokerResponse, error) {
+	res := new(UnregisterBrokerResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddRaftVoter sends a add raft voter request and returns
+// add raft voter response or error
+func (b *Broker) AddRaftVoter(req *AddRaftVoterRequest) (*AddRaftVoterResponse, error) {
+	res := new(AddRaftVoterResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RemoveRaftVoter sends a remove raft voter request and returns
+// remove raft voter response or error
+func (b *Broker) RemoveRaftVoter(req *RemoveRaftVoterRequest) (*RemoveRaftVoterResponse, error) {
+	res := new(RemoveRaftVoterResponse)
+
+	err := b.sendAndReceive(req, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // DescribeClientQuotas sends a request to get the broker's quotas
 func (b *Broker) DescribeClientQuotas(request *DescribeClientQuotasRequest) (*DescribeClientQuotasResponse, error) {
 	response := new(DescribeClientQuotasResponse)
@@ -1734,8 +2009,8 @@ func (b *Broker) sendAndReceiveSASLSCRAMv0() error {
 	}
 
 	scramClient := b.conf.Net.SASL.SCRAMClientGeneratorFunc()
-	if err := scramClient.Begin(b.conf.Net.SASL.User, b.conf.Net.SASL.Password, b.conf.Net.SASL.SCRAMAuthzID); err != nil {
-		return fmt.Errorf("failed to start SCRAM exchange with the server: %w", err)
+	if err := b.beginSCRAM(scramClient); err != nil {
+		return err
 	}
 
 	msg, err := scramClient.Step("")
@@ -1790,8 +2065,8 @@ func (b *Broker) sendAndReceiveSASLSCRAMv0() error {
 }
 
 func (b *Broker) sendAndReceiveSASLSCRAMv1(authSendReceiver func(authBytes []byte) (*SaslAuthenticateResponse, error), scramClient SCRAMClient) error {
-	if err := scramClient.Begin(b.conf.Net.SASL.User, b.conf.Net.SASL.Password, b.conf.Net.SASL.SCRAMAuthzID); err != nil {
-		return fmt.Errorf("failed to start SCRAM exchange with the server: %w", err)
+	if err := b.beginSCRAM(scramClient); err != nil {
+		return err
 	}
 
 	msg, err := scramClient.Step("")
@@ -1817,6 +2092,26 @@ func (b *Broker) sendAndReceiveSASLSCRAMv1(authSendReceiver func(authBytes []byt
 	return nil
 }
 
+// beginSCRAM starts the SCRAM exchange, requesting delegation token
+// authentication if Net.SASL.SCRAMTokenAuth is set.
+func (b *Broker) beginSCRAM(scramClient SCRAMClient) error {
+	var err error
+	if b.conf.Net.SASL.SCRAMTokenAuth {
+		extClient, ok := scramClient.(SCRAMClientWithExtensions)
+		if !ok {
+			return ConfigurationError("Net.SASL.SCRAMTokenAuth requires the SCRAM client to implement SCRAMClientWithExtensions")
+		}
+		err = extClient.BeginWithExtensions(b.conf.Net.SASL.User, b.conf.Net.SASL.Password, b.conf.Net.SASL.SCRAMAuthzID,
+			map[string]string{scramExtensionTokenAuth: "true"})
+	} else {
+		err = scramClient.Begin(b.conf.Net.SASL.User, b.conf.Net.SASL.Password, b.conf.Net.SASL.SCRAMAuthzID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start SCRAM exchange with the server: %w", err)
+	}
+	return nil
+}
+
 func (b *Broker) createSaslAuthenticateRequest(msg []byte) *SaslAuthenticateRequest {
 	authenticateRequest := SaslAuthenticateRequest{SaslAuthBytes: msg}
 	if b.conf.Version.IsAtLeast(V2_5_0_0) {
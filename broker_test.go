@@ -635,6 +635,73 @@ func TestSASLSCRAMSHAXXX(t *testing.T) {
 	}
 }
 
+// A mock scram client supporting SCRAM extensions.
+type MockSCRAMClientWithExtensions struct {
+	MockSCRAMClient
+	extensions map[string]string
+}
+
+func (m *MockSCRAMClientWithExtensions) BeginWithExtensions(_, _, _ string, extensions map[string]string) error {
+	m.extensions = extensions
+	return nil
+}
+
+var _ SCRAMClientWithExtensions = &MockSCRAMClientWithExtensions{}
+
+func TestSASLSCRAMTokenAuth(t *testing.T) {
+	testTable := []struct {
+		name            string
+		scramClient     SCRAMClient
+		expectClientErr bool
+	}{
+		{
+			name:        "SCRAM client with extensions",
+			scramClient: &MockSCRAMClientWithExtensions{},
+		},
+		{
+			name:            "SCRAM client without extensions",
+			scramClient:     &MockSCRAMClient{},
+			expectClientErr: true,
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			mockBroker := NewMockBroker(t, 0)
+			defer mockBroker.Close()
+			mockBroker.SetHandlerByMap(map[string]MockResponse{
+				"SaslAuthenticateRequest": NewMockSaslAuthenticateResponse(t).SetAuthBytes([]byte("pong")),
+				"SaslHandshakeRequest":    NewMockSaslHandshakeResponse(t).SetEnabledMechanisms([]string{SASLTypeSCRAMSHA512}),
+			})
+
+			conf := NewTestConfig()
+			conf.Net.SASL.Enable = true
+			conf.Net.SASL.Mechanism = SASLTypeSCRAMSHA512
+			conf.Net.SASL.Version = SASLHandshakeV1
+			conf.Net.SASL.User = "token-id"
+			conf.Net.SASL.Password = "aG1hYw=="
+			conf.Net.SASL.SCRAMTokenAuth = true
+			conf.Net.SASL.SCRAMClientGeneratorFunc = func() SCRAMClient { return test.scramClient }
+			conf.Version = V1_0_0_0
+
+			broker := NewBroker(mockBroker.Addr())
+			if err := broker.Open(conf); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = broker.Close() })
+
+			_, err := broker.Connected()
+			if test.expectClientErr {
+				var configErr ConfigurationError
+				require.ErrorAs(t, err, &configErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"tokenauth": "true"}, test.scramClient.(*MockSCRAMClientWithExtensions).extensions)
+		})
+	}
+}
+
 func TestSASLPlainAuth(t *testing.T) {
 	testTable := []struct {
 		name             string
@@ -148,6 +148,7 @@ type client struct {
 
 	conf           *Config
 	closer, closed chan none // for shutting down background metadata updater
+	telemetryDone  chan none // closed when the telemetry reporter has stopped, nil if it never ran
 
 	// the broker addresses given to us through the constructor are not guaranteed to be returned in
 	// the cluster metadata (I *think* it only returns brokers who are currently leading partitions?)
@@ -244,6 +245,11 @@ func NewClient(addrs []string, conf *Config) (Client, error) {
 	}
 	go withRecover(client.backgroundMetadataUpdater)
 
+	if conf.Telemetry.Enable && conf.ApiVersionsRequest && conf.Version.IsAtLeast(V3_7_0_0) {
+		client.telemetryDone = make(chan none)
+		go withRecover(client.backgroundTelemetryReporter)
+	}
+
 	DebugLogger.Println("Successfully initialized new client")
 
 	return client, nil
@@ -317,9 +323,12 @@ func (client *client) Close() error {
 		return ErrClosedClient
 	}
 
-	// shutdown and wait for the background thread before we take the lock, to avoid races
+	// shutdown and wait for the background threads before we take the lock, to avoid races
 	close(client.closer)
 	<-client.closed
+	if client.telemetryDone != nil {
+		<-client.telemetryDone
+	}
 
 	client.lock.Lock()
 	defer client.lock.Unlock()
@@ -951,6 +960,12 @@ func (client *client) backgroundMetadataUpdater() {
 	}
 }
 
+func (client *client) backgroundTelemetryReporter() {
+	defer close(client.telemetryDone)
+
+	newTelemetryReporter(client).run(client.closer)
+}
+
 func (client *client) refreshMetadata() error {
 	var topics []string
 
@@ -16,6 +16,7 @@ import (
 const (
 	defaultClientID                 = "sarama"
 	defaultMetadataRefreshFrequency = 10 * time.Minute
+	defaultAckMaxPending            = 10000
 )
 
 // validClientID specifies the permitted characters for a client.id when
@@ -104,6 +105,13 @@ type Config struct {
 			// SCRAMClientGeneratorFunc is a generator of a user provided implementation of a SCRAM
 			// client used to perform the SCRAM exchange with the server.
 			SCRAMClientGeneratorFunc func() SCRAMClient
+			// SCRAMTokenAuth authenticates over SASL/SCRAM with a delegation
+			// token (KIP-48) instead of a user's credentials, by sending the
+			// tokenauth=true SCRAM extension. User must be set to the token ID
+			// and Password to the base64 encoded token HMAC. The client
+			// returned by SCRAMClientGeneratorFunc must implement
+			// SCRAMClientWithExtensions.
+			SCRAMTokenAuth bool
 			// TokenProvider is a user-defined callback for generating
 			// access tokens for SASL/OAUTHBEARER auth. See the
 			// AccessTokenProvider interface docs for proper implementation
@@ -180,6 +188,18 @@ type Config struct {
 		SingleFlight bool
 	}
 
+	// Telemetry is the namespace for pushing client metrics to the cluster
+	// (KIP-714), used by the Client.
+	Telemetry struct {
+		// Whether to push the meters and histograms of MetricRegistry to the
+		// cluster when a broker subscribes to them (default true). Only the
+		// metrics requested by the broker's telemetry subscription are pushed,
+		// at the interval it requests. Pushing requires Version to be at least
+		// V3_7_0_0 and ApiVersionsRequest to be enabled. Similar to
+		// `enable.metrics.push` in the JVM version.
+		Enable bool
+	}
+
 	// Producer is the namespace for configuration related to producing messages,
 	// used by the Producer.
 	Producer struct {
@@ -208,9 +228,24 @@ type Config struct {
 		// setting for the JVM producer.
 		Partitioner PartitionerConstructor
 		// If enabled, the producer will ensure that exactly one copy of each message is
-		// written, and it will enforce stricter ordering by requiring MaxOpenRequests=1
-		// and WaitForAll acks, which can reduce throughput.
+		// written, and it will enforce stricter ordering by requiring WaitForAll acks and
+		// at most 5 MaxOpenRequests, which can reduce throughput. Up to MaxOpenRequests
+		// batches may be in flight for each partition, the broker using their sequence
+		// numbers to keep them in order.
 		Idempotent bool
+		// The maximum number of bytes of messages the producer buffers until
+		// they are acknowledged or failed, similar to the `buffer.memory`
+		// setting of the JVM producer. Once it is reached, the Input channel is
+		// not read until enough buffered messages complete, and buffered messages
+		// are sent without waiting for the Flush thresholds. A message larger
+		// than the budget is accepted once nothing else is buffered. Defaults to
+		// 0, meaning no limit.
+		MaxBufferedBytes int
+		// How long a message may wait for room in MaxBufferedBytes before it is
+		// returned with ErrProducerBufferFull, similar to the `max.block.ms`
+		// setting of the JVM producer. Defaults to 0, meaning it waits as long
+		// as needed.
+		MaxBlock time.Duration
 		// Transaction specify
 		Transaction struct {
 			// Used in transactions to identify an instance of a producer through restarts
@@ -311,6 +346,19 @@ type Config struct {
 	Consumer struct {
 		// Group is the namespace for configuring consumer group.
 		Group struct {
+			// Protocol selects the rebalance protocol used by the consumer group.
+			// GroupProtocolClassic (the default) joins the group with JoinGroup and
+			// SyncGroup and computes the assignment on the client using
+			// Rebalance.GroupStrategies. GroupProtocolConsumer uses the consumer
+			// rebalance protocol from KIP-848 (Kafka 4.0+), where the group
+			// coordinator computes the assignment and hands it out incrementally
+			// through ConsumerGroupHeartbeat requests.
+			Protocol ConsumerGroupProtocol
+			// ServerAssignor is the name of the server side assignor ("uniform" or
+			// "range" in Apache Kafka) to request when Protocol is
+			// GroupProtocolConsumer. Leave empty to use the broker's default.
+			ServerAssignor string
+
 			Session struct {
 				// The timeout used to detect consumer failures when using Kafka's group management facility.
 				// The consumer sends periodic heartbeats to indicate its liveness to the broker.
@@ -372,8 +420,15 @@ type Config struct {
 			// if the fetched consumer offset is out of range of available offsets. Out of range
 			// can happen if the data has been deleted from the server, or during situations of
 			// under-replication where a replica does not have all the data yet. It can be
-			// dangerous to reset the offset automatically, particularly in the latter case. Defaults
-			// to true to maintain existing behavior.
+			// dangerous to reset the offset automatically, particularly in the latter case.
+			//
+			// It also applies to any partition consumer, in a group or not, that
+			// detects that the log of a new leader was truncated below its fetch
+			// position (KIP-320, Kafka 2.1.0.0+): if true it reports a
+			// *LogTruncationError and resumes from the truncation point, or from
+			// Consumer.Offsets.Initial when that is unknown; otherwise it reports
+			// the error and shuts down. Defaults to true to maintain existing
+			// behavior.
 			ResetInvalidOffsets bool
 		}
 
@@ -450,6 +505,14 @@ type Config struct {
 		// between two messages being sent may not be recognized as a timeout.
 		MaxProcessingTime time.Duration
 
+		// The maximum number of bytes of fetched messages buffered by the
+		// consumer and not yet received by the user, including the messages
+		// waiting in the Messages channel, shared equally by all of its
+		// partition consumers. A partition is not fetched from while it
+		// buffers more than its share, so the budget may be exceeded by up to
+		// one fetch response per partition. Defaults to 0, meaning no limit.
+		MaxBufferedBytes int
+
 		// Return specifies what channels will be populated. If they are set to true,
 		// you must read from them to prevent deadlock.
 		Return struct {
@@ -494,6 +557,51 @@ type Config struct {
 				// requests during OffsetManager shutdown (default 3).
 				Max int
 			}
+
+			// Ack specifies configuration for acknowledging the messages of a
+			// consumer group claim in any order with ConsumerGroupSession.AckMessage,
+			// as an alternative to marking offsets in order.
+			Ack struct {
+				// Whether the messages delivered to the claims are tracked so
+				// that they can be acknowledged (default disabled).
+				Enable bool
+
+				// The maximum number of delivered messages per partition that
+				// are waiting for themselves or an earlier message to be
+				// acknowledged (default 10000). Once reached, the partition is
+				// no longer fetched from until the gap is filled, although the
+				// messages already fetched are still delivered.
+				MaxPending int
+			}
+
+			// Store, when set, stores and fetches the offsets of consumer
+			// groups and offset managers in place of the group coordinator
+			// (default nil, offsets are committed to Kafka). See OffsetStore.
+			Store OffsetStore
+		}
+
+		// Share specifies configuration for the members of a share group,
+		// see ShareConsumer.
+		Share struct {
+			// The maximum number of records acquired by a single share fetch
+			// request (default 500). The broker may exceed it to align with
+			// record batch boundaries. Requires Kafka 4.1 or later.
+			MaxRecords int32
+		}
+
+		// Batch specifies how messages are grouped into batches for a consumer
+		// group handler implementing BatchConsumerGroupHandler.
+		Batch struct {
+			// The maximum number of messages in a batch (default 500).
+			MaxMessages int
+			// The maximum size of a batch, counting the keys, values and headers
+			// of its messages (default 1MB). A message larger than this is
+			// delivered in a batch of its own.
+			MaxBytes int
+			// How long to wait for a batch to fill up before delivering it
+			// anyway (default 100ms). With 0, the messages of each fetch
+			// response are delivered as soon as they are received.
+			Linger time.Duration
 		}
 
 		// IsolationLevel support 2 mode:
@@ -566,6 +674,8 @@ func NewConfig() *Config {
 	c.Metadata.AllowAutoTopicCreation = true
 	c.Metadata.SingleFlight = true
 
+	c.Telemetry.Enable = true
+
 	c.Producer.MaxMessageBytes = 1024 * 1024
 	c.Producer.RequiredAcks = WaitForLocal
 	c.Producer.Timeout = 10 * time.Second
@@ -590,7 +700,13 @@ func NewConfig() *Config {
 	c.Consumer.Offsets.AutoCommit.Interval = 1 * time.Second
 	c.Consumer.Offsets.Initial = OffsetNewest
 	c.Consumer.Offsets.Retry.Max = 3
+	c.Consumer.Offsets.Ack.MaxPending = defaultAckMaxPending
+	c.Consumer.Share.MaxRecords = 500
+	c.Consumer.Batch.MaxMessages = 500
+	c.Consumer.Batch.MaxBytes = 1024 * 1024
+	c.Consumer.Batch.Linger = 100 * time.Millisecond
 
+	c.Consumer.Group.Protocol = GroupProtocolClassic
 	c.Consumer.Group.Session.Timeout = 10 * time.Second
 	c.Consumer.Group.Heartbeat.Interval = 3 * time.Second
 	c.Consumer.Group.Rebalance.GroupStrategies = []BalanceStrategy{NewBalanceStrategyRange()}
@@ -687,6 +803,9 @@ func (c *Config) Validate() error {
 		if c.Net.SASL.Version == SASLHandshakeV0 && c.ApiVersionsRequest {
 			return ConfigurationError("ApiVersionsRequest must be disabled when SASL v0 is enabled")
 		}
+		if c.Net.SASL.SCRAMTokenAuth && c.Net.SASL.Mechanism != SASLTypeSCRAMSHA256 && c.Net.SASL.Mechanism != SASLTypeSCRAMSHA512 {
+			return ConfigurationError("Net.SASL.SCRAMTokenAuth requires a SCRAM Net.SASL.Mechanism")
+		}
 		switch c.Net.SASL.Mechanism {
 		case SASLTypePlaintext:
 			if c.Net.SASL.User == "" {
@@ -790,6 +909,10 @@ func (c *Config) Validate() error {
 		return ConfigurationError("Producer.Retry.Max must be >= 0")
 	case c.Producer.Retry.Backoff < 0:
 		return ConfigurationError("Producer.Retry.Backoff must be >= 0")
+	case c.Producer.MaxBufferedBytes < 0:
+		return ConfigurationError("Producer.MaxBufferedBytes must be >= 0")
+	case c.Producer.MaxBlock < 0:
+		return ConfigurationError("Producer.MaxBlock must be >= 0")
 	}
 
 	if c.Producer.Compression == CompressionLZ4 && !c.Version.IsAtLeast(V0_10_0_0) {
@@ -818,8 +941,8 @@ func (c *Config) Validate() error {
 		if c.Producer.RequiredAcks != WaitForAll {
 			return ConfigurationError("Idempotent producer requires Producer.RequiredAcks to be WaitForAll")
 		}
-		if c.Net.MaxOpenRequests > 1 {
-			return ConfigurationError("Idempotent producer requires Net.MaxOpenRequests to be 1")
+		if c.Net.MaxOpenRequests > 5 {
+			return ConfigurationError("Idempotent producer requires Net.MaxOpenRequests to be <= 5")
 		}
 	}
 
@@ -841,6 +964,8 @@ func (c *Config) Validate() error {
 		return ConfigurationError("Consumer.MaxWaitTime must be >= 1ms")
 	case c.Consumer.MaxProcessingTime <= 0:
 		return ConfigurationError("Consumer.MaxProcessingTime must be > 0")
+	case c.Consumer.MaxBufferedBytes < 0:
+		return ConfigurationError("Consumer.MaxBufferedBytes must be >= 0")
 	case c.Consumer.Retry.Backoff < 0:
 		return ConfigurationError("Consumer.Retry.Backoff must be >= 0")
 	case c.Consumer.Retry.Max < 0:
@@ -851,6 +976,16 @@ func (c *Config) Validate() error {
 		return ConfigurationError("Consumer.Offsets.Initial must be OffsetOldest or OffsetNewest")
 	case c.Consumer.Offsets.Retry.Max < 0:
 		return ConfigurationError("Consumer.Offsets.Retry.Max must be >= 0")
+	case c.Consumer.Offsets.Ack.MaxPending <= 0:
+		return ConfigurationError("Consumer.Offsets.Ack.MaxPending must be > 0")
+	case c.Consumer.Share.MaxRecords <= 0:
+		return ConfigurationError("Consumer.Share.MaxRecords must be > 0")
+	case c.Consumer.Batch.MaxMessages <= 0:
+		return ConfigurationError("Consumer.Batch.MaxMessages must be > 0")
+	case c.Consumer.Batch.MaxBytes <= 0:
+		return ConfigurationError("Consumer.Batch.MaxBytes must be > 0")
+	case c.Consumer.Batch.Linger < 0:
+		return ConfigurationError("Consumer.Batch.Linger must be >= 0")
 	case c.Consumer.IsolationLevel != ReadUncommitted && c.Consumer.IsolationLevel != ReadCommitted:
 		return ConfigurationError("Consumer.IsolationLevel must be ReadUncommitted or ReadCommitted")
 	}
@@ -903,6 +1038,16 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	switch c.Consumer.Group.Protocol {
+	case GroupProtocolClassic:
+	case GroupProtocolConsumer:
+		if !c.Version.IsAtLeast(V4_0_0_0) {
+			return ConfigurationError("Consumer.Group.Protocol consumer requires Version >= 4.0")
+		}
+	default:
+		return ConfigurationError(fmt.Sprintf("Consumer.Group.Protocol %q is not supported", c.Consumer.Group.Protocol))
+	}
+
 	if c.Consumer.Group.InstanceId != "" {
 		if !c.Version.IsAtLeast(V2_3_0_0) {
 			return ConfigurationError("Consumer.Group.InstanceId need Version >= 2.3")
@@ -152,6 +152,17 @@ func TestNetConfigValidates(t *testing.T) {
 			},
 			"A SCRAMClientGeneratorFunc function must be provided to Net.SASL.SCRAMClientGeneratorFunc",
 		},
+		{
+			"SASL.SCRAMTokenAuth - Non SCRAM mechanism",
+			func(cfg *Config) {
+				cfg.Net.SASL.Enable = true
+				cfg.Net.SASL.Mechanism = SASLTypePlaintext
+				cfg.Net.SASL.SCRAMTokenAuth = true
+				cfg.Net.SASL.User = "user"
+				cfg.Net.SASL.Password = "strong_password"
+			},
+			"Net.SASL.SCRAMTokenAuth requires a SCRAM Net.SASL.Mechanism",
+		},
 		{
 			"SASL.Mechanism GSSAPI (Kerberos) - Using User/Password, Missing password field",
 			func(cfg *Config) {
@@ -420,6 +431,20 @@ func TestProducerConfigValidates(t *testing.T) {
 			},
 			"Producer.Retry.Backoff must be >= 0",
 		},
+		{
+			"MaxBufferedBytes",
+			func(cfg *Config) {
+				cfg.Producer.MaxBufferedBytes = -1
+			},
+			"Producer.MaxBufferedBytes must be >= 0",
+		},
+		{
+			"MaxBlock",
+			func(cfg *Config) {
+				cfg.Producer.MaxBlock = -1
+			},
+			"Producer.MaxBlock must be >= 0",
+		},
 		{
 			"Idempotent Version",
 			func(cfg *Config) {
@@ -451,8 +476,9 @@ func TestProducerConfigValidates(t *testing.T) {
 				cfg.Version = V0_11_0_0
 				cfg.Producer.Idempotent = true
 				cfg.Producer.RequiredAcks = WaitForAll
+				cfg.Net.MaxOpenRequests = 6
 			},
-			"Idempotent producer requires Net.MaxOpenRequests to be 1",
+			"Idempotent producer requires Net.MaxOpenRequests to be <= 5",
 		},
 	}
 
@@ -489,6 +515,63 @@ func TestConsumerConfigValidates(t *testing.T) {
 			},
 			"Consumer.IsolationLevel must be ReadUncommitted or ReadCommitted",
 		},
+		{
+			"Consumer group protocol Version",
+			func(cfg *Config) {
+				cfg.Version = V3_9_0_0
+				cfg.Consumer.Group.Protocol = GroupProtocolConsumer
+			},
+			"Consumer.Group.Protocol consumer requires Version >= 4.0",
+		},
+		{
+			"Unknown consumer group protocol",
+			func(cfg *Config) {
+				cfg.Consumer.Group.Protocol = "eager"
+			},
+			`Consumer.Group.Protocol "eager" is not supported`,
+		},
+		{
+			"Share MaxRecords",
+			func(cfg *Config) {
+				cfg.Consumer.Share.MaxRecords = 0
+			},
+			"Consumer.Share.MaxRecords must be > 0",
+		},
+		{
+			"Ack MaxPending",
+			func(cfg *Config) {
+				cfg.Consumer.Offsets.Ack.MaxPending = 0
+			},
+			"Consumer.Offsets.Ack.MaxPending must be > 0",
+		},
+		{
+			"Batch MaxMessages",
+			func(cfg *Config) {
+				cfg.Consumer.Batch.MaxMessages = 0
+			},
+			"Consumer.Batch.MaxMessages must be > 0",
+		},
+		{
+			"Batch MaxBytes",
+			func(cfg *Config) {
+				cfg.Consumer.Batch.MaxBytes = 0
+			},
+			"Consumer.Batch.MaxBytes must be > 0",
+		},
+		{
+			"Batch Linger",
+			func(cfg *Config) {
+				cfg.Consumer.Batch.Linger = -1
+			},
+			"Consumer.Batch.Linger must be >= 0",
+		},
+		{
+			"MaxBufferedBytes",
+			func(cfg *Config) {
+				cfg.Consumer.MaxBufferedBytes = -1
+			},
+			"Consumer.MaxBufferedBytes must be >= 0",
+		},
 	}
 
 	for i, test := range tests {
@@ -48,6 +48,33 @@ func (ce ConsumerErrors) Error() string {
 	return fmt.Sprintf("kafka: %d errors while consuming", len(ce))
 }
 
+// LogTruncationError is sent on a PartitionConsumer's Errors channel when,
+// after a leader change, the new leader's log no longer contains records the
+// consumer has already consumed, typically following an unclean leader
+// election (KIP-320). If Consumer.Group.ResetInvalidOffsets is false the
+// partition consumer shuts down after reporting it; otherwise it resumes
+// from TruncationOffset, or from Consumer.Offsets.Initial when that is unknown.
+type LogTruncationError struct {
+	Topic     string
+	Partition int32
+	// FetchOffset is the offset the consumer would have fetched next.
+	FetchOffset int64
+	// TruncationOffset is the end offset of the last consumed epoch on the new
+	// leader, or -1 if the leader no longer knows that epoch.
+	TruncationOffset int64
+	// LeaderEpoch is the epoch that TruncationOffset belongs to, or -1.
+	LeaderEpoch int32
+}
+
+func (e *LogTruncationError) Error() string {
+	if e.TruncationOffset < 0 {
+		return fmt.Sprintf("kafka: log truncation detected for %s/%d at fetch offset %d, truncation offset unknown",
+			e.Topic, e.Partition, e.FetchOffset)
+	}
+	return fmt.Sprintf("kafka: log truncation detected for %s/%d, fetch offset %d is beyond end offset %d of leader epoch %d",
+		e.Topic, e.Partition, e.FetchOffset, e.TruncationOffset, e.LeaderEpoch)
+}
+
 // Consumer manages PartitionConsumers which process Kafka messages from brokers. You MUST call Close()
 // on a consumer to avoid leaks, it will not be garbage-collected automatically when it passes out of
 // scope.
@@ -106,6 +133,12 @@ type consumer struct {
 	client          Client
 	metricRegistry  metrics.Registry
 	lock            sync.Mutex
+
+	// childCount is the number of partition consumers sharing
+	// Consumer.MaxBufferedBytes, and buffered the bytes they buffer
+	childCount    atomic.Int64
+	buffered      atomic.Int64
+	bufferedGauge metrics.Gauge
 }
 
 // NewConsumer creates a new consumer using the given broker addresses and configuration.
@@ -139,6 +172,7 @@ func newConsumer(client Client) (Consumer, error) {
 		brokerConsumers: make(map[*Broker]*brokerConsumer),
 		metricRegistry:  newCleanupRegistry(client.Config().MetricRegistry),
 	}
+	c.bufferedGauge = metrics.GetOrRegisterGauge("consumer-buffered-bytes", c.metricRegistry)
 
 	return c, nil
 }
@@ -157,6 +191,21 @@ func (c *consumer) Partitions(topic string) ([]int32, error) {
 }
 
 func (c *consumer) ConsumePartition(topic string, partition int32, offset int64) (PartitionConsumer, error) {
+	return c.consumePartition(topic, partition, offset, partitionConsumerOptions{})
+}
+
+// partitionConsumerOptions are the settings of a partition consumer that a
+// consumer group uses for its claims.
+type partitionConsumerOptions struct {
+	// batched hands over the messages of each fetch response at once on the
+	// batches channel instead of feeding them one by one to Messages()
+	batched bool
+	// acks, when set, records the delivered messages so that they can be
+	// acknowledged, and stops fetching while too many are pending
+	acks *ackTracker
+}
+
+func (c *consumer) consumePartition(topic string, partition int32, offset int64, opts partitionConsumerOptions) (PartitionConsumer, error) {
 	child := &partitionConsumer{
 		consumer:             c,
 		conf:                 c.conf,
@@ -170,12 +219,19 @@ func (c *consumer) ConsumePartition(topic string, partition int32, offset int64)
 		trigger:              make(chan none, 1),
 		dying:                make(chan none),
 		dispatcherStop:       make(chan none),
+		seeks:                make(chan chan none),
 		fetchSize:            c.conf.Consumer.Fetch.Default,
+		acks:                 opts.acks,
+	}
+	child.lastFetchedEpoch.Store(invalidLeaderEpoch)
+	if opts.batched {
+		child.batches = make(chan []*ConsumerMessage, 1)
 	}
 
 	if err := child.chooseStartingOffset(offset); err != nil {
 		return nil, err
 	}
+	child.delivered.Store(child.offset.Load())
 
 	leader, epoch, err := c.client.LeaderAndEpoch(child.topic, child.partition)
 	if err != nil {
@@ -185,6 +241,12 @@ func (c *consumer) ConsumePartition(topic string, partition int32, offset int64)
 	if err := c.addChild(child); err != nil {
 		return nil, err
 	}
+	// registered only once the partition is ours, and unregistered by the
+	// dispatcher before it is released, so that the gauges are never shared
+	// with another partitionConsumer for the same partition
+	child.lagGauge = metrics.GetOrRegisterGauge(getMetricNameForPartition("consumer-lag", topic, partition), c.metricRegistry)
+	child.timeLagGauge = metrics.GetOrRegisterGauge(getMetricNameForPartition("consumer-time-lag", topic, partition), c.metricRegistry)
+	child.updateLagMetrics()
 
 	go withRecover(child.dispatcher)
 	go withRecover(child.responseFeeder)
@@ -220,6 +282,26 @@ func (c *consumer) HighWaterMarks() map[string]map[int32]int64 {
 	return hwms
 }
 
+// lags returns the lag of every partition being consumed.
+func (c *consumer) lags() map[string]map[int32]ConsumerLag {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	lags := make(map[string]map[int32]ConsumerLag)
+	for topic, p := range c.children {
+		if len(p) == 0 {
+			continue
+		}
+		lag := make(map[int32]ConsumerLag, len(p))
+		for partition, pc := range p {
+			lag[partition] = pc.Lag()
+		}
+		lags[topic] = lag
+	}
+
+	return lags
+}
+
 func (c *consumer) addChild(child *partitionConsumer) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -235,6 +317,7 @@ func (c *consumer) addChild(child *partitionConsumer) error {
 	}
 
 	topicChildren[child.partition] = child
+	c.childCount.Add(1)
 	return nil
 }
 
@@ -242,7 +325,10 @@ func (c *consumer) removeChild(child *partitionConsumer) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	delete(c.children[child.topic], child.partition)
+	if c.children[child.topic][child.partition] == child {
+		delete(c.children[child.topic], child.partition)
+		c.childCount.Add(-1)
+	}
 }
 
 func (c *consumer) refBrokerConsumer(broker *Broker) *brokerConsumer {
@@ -398,6 +484,35 @@ type PartitionConsumer interface {
 
 	// IsPaused indicates if this partition consumer is paused or not
 	IsPaused() bool
+
+	// Seek repositions the PartitionConsumer so that the next message
+	// returned on the Messages channel is the one at the given offset, which
+	// may also be OffsetNewest or OffsetOldest. Messages that were fetched but
+	// not yet received from the Messages channel are discarded, and no longer
+	// wait to be acknowledged when Consumer.Offsets.Ack is enabled; the broker
+	// subscription is kept. ErrOffsetOutOfRange is returned if the offset is
+	// not in the log.
+	Seek(offset int64) error
+
+	// SeekToTimestamp repositions the PartitionConsumer at the first message
+	// with a timestamp at or after t, or at OffsetNewest if there is no such
+	// message. It otherwise behaves like Seek.
+	SeekToTimestamp(t time.Time) error
+
+	// Lag returns how far the messages handed over on the Messages channel are
+	// behind the high water mark offset of the partition.
+	Lag() ConsumerLag
+}
+
+// ConsumerLag is a snapshot of how far a consumer is behind the end of a partition.
+type ConsumerLag struct {
+	// Messages is the high water mark offset minus the offset of the next
+	// message to be handed over.
+	Messages int64
+	// Time is how long ago the last message handed over was appended to the
+	// partition, going by its timestamp. It is 0 once there is no lag, or if
+	// no message with a timestamp was handed over yet.
+	Time time.Duration
 }
 
 type partitionConsumerResponse struct {
@@ -433,10 +548,12 @@ type partitionConsumer struct {
 	broker             *brokerConsumer
 	brokerSubscription *brokerSubscription
 	messages           chan *ConsumerMessage
+	batches            chan []*ConsumerMessage // replaces messages when consuming batches
 	errors             chan *ConsumerError
 	feeder             chan *partitionConsumerResponse
 
 	leaderEpoch                int32
+	lastFetchedEpoch           atomic.Int32 // leader epoch of the batch holding the last consumed record
 	preferredReadReplica       int32
 	preferredReadReplicaExpiry time.Time
 
@@ -448,10 +565,27 @@ type partitionConsumer struct {
 	partition          int32
 	responseResult     error
 	fetchSize          int32
-	offset             int64
+	offset             atomic.Int64 // also read by the dispatcher to validate the fetch position
 	retries            atomic.Int32
 
 	paused atomic.Bool // accessed atomically, 0 = not paused, 1 = paused
+
+	seeks       chan chan none // asks the responseFeeder to flush undelivered messages
+	seekLock    sync.Mutex     // protects seekOffset, seekEpoch and seekPending
+	seekOffset  int64
+	seekEpoch   int32
+	seekPending bool
+
+	acks *ackTracker // set when the messages of a consumer group claim can be acknowledged
+
+	buffer messageBuffer // bytes of the messages not received by the user yet
+
+	// the offset following the last message handed over and its timestamp in
+	// nanoseconds, from which the lag is computed
+	delivered          atomic.Int64
+	deliveredTimestamp atomic.Int64
+	lagGauge           metrics.Gauge
+	timeLagGauge       metrics.Gauge
 }
 
 var errTimedOut = errors.New("timed out feeding messages to the user") // not user-facing
@@ -538,6 +672,11 @@ func (child *partitionConsumer) dispatcher() {
 		if child.broker != nil {
 			child.consumer.unrefBrokerConsumer(child.broker)
 		}
+		if child.lagGauge != nil {
+			registry := child.consumer.metricRegistry
+			registry.Unregister(getMetricNameForPartition("consumer-lag", child.topic, child.partition))
+			registry.Unregister(getMetricNameForPartition("consumer-time-lag", child.topic, child.partition))
+		}
 		child.consumer.removeChild(child)
 		close(child.feeder)
 	}()
@@ -646,6 +785,11 @@ func (child *partitionConsumer) dispatch() error {
 	if err != nil {
 		return err
 	}
+	if epoch != child.leaderEpoch {
+		if err := child.validateFetchPosition(epoch); err != nil {
+			return err
+		}
+	}
 	child.leaderEpoch = epoch
 	for {
 		child.broker = child.consumer.refBrokerConsumer(broker)
@@ -659,33 +803,213 @@ func (child *partitionConsumer) dispatch() error {
 	}
 }
 
+// validateFetchPosition checks, after a leader change, that the new leader's
+// log still contains everything consumed so far by asking it where the epoch
+// of the last consumed record ends (KIP-320). It is a no-op until a record
+// carrying a leader epoch has been consumed.
+func (child *partitionConsumer) validateFetchPosition(currentLeaderEpoch int32) error {
+	if child.isSeekPending() {
+		// the position is about to be replaced anyway
+		return nil
+	}
+	if child.lastFetchedEpoch.Load() < 0 || currentLeaderEpoch < 0 || !child.conf.Version.IsAtLeast(V2_1_0_0) {
+		return nil
+	}
+
+	leader, err := child.consumer.client.Leader(child.topic, child.partition)
+	if err != nil {
+		return err
+	}
+
+	request := NewOffsetForLeaderEpochRequest(child.conf.Version)
+	request.AddPartition(child.topic, child.partition, currentLeaderEpoch, child.lastFetchedEpoch.Load())
+	response, err := leader.OffsetForLeaderEpoch(request)
+	if err != nil {
+		return err
+	}
+
+	block := response.GetPartition(child.topic, child.partition)
+	if block == nil {
+		return ErrIncompleteResponse
+	}
+	if !errors.Is(block.Err, ErrNoError) {
+		return block.Err
+	}
+
+	if block.EndOffset >= 0 && block.LeaderEpoch >= 0 && block.EndOffset >= child.offset.Load() {
+		return nil
+	}
+
+	truncation := &LogTruncationError{
+		Topic:            child.topic,
+		Partition:        child.partition,
+		FetchOffset:      child.offset.Load(),
+		TruncationOffset: block.EndOffset,
+		LeaderEpoch:      block.LeaderEpoch,
+	}
+	if block.EndOffset < 0 || block.LeaderEpoch < 0 {
+		truncation.TruncationOffset = -1
+		truncation.LeaderEpoch = invalidLeaderEpoch
+	}
+	return child.handleLogTruncation(truncation)
+}
+
+// handleLogTruncation reports the truncation and either repositions the
+// consumer or, if Consumer.Group.ResetInvalidOffsets is disabled, shuts it
+// down. The error is returned only in the latter case.
+//
+// Like a seek, the new position is only recorded here: the brokerConsumer,
+// which owns the fetch position while the child is subscribed, applies it
+// before its next fetch.
+func (child *partitionConsumer) handleLogTruncation(truncation *LogTruncationError) error {
+	if !child.conf.Consumer.Group.ResetInvalidOffsets {
+		Logger.Printf("consumer/%s/%d shutting down because %s\n", child.topic, child.partition, truncation)
+		child.sendError(truncation)
+		child.AsyncClose()
+		return truncation
+	}
+
+	offset, epoch := truncation.TruncationOffset, truncation.LeaderEpoch
+	if offset < 0 {
+		resolved, err := child.resolveOffset(child.conf.Consumer.Offsets.Initial)
+		if err != nil {
+			return err
+		}
+		offset, epoch = resolved, invalidLeaderEpoch
+	}
+	child.setPendingSeek(offset, epoch)
+	Logger.Printf("consumer/%s/%d resuming from offset %d because %s\n",
+		child.topic, child.partition, offset, truncation)
+	child.sendError(truncation)
+	return nil
+}
+
 func (child *partitionConsumer) chooseStartingOffset(offset int64) error {
-	newestOffset, err := child.consumer.client.GetOffset(child.topic, child.partition, OffsetNewest)
+	resolved, err := child.resolveOffset(offset)
 	if err != nil {
 		return err
 	}
 
+	child.offset.Store(resolved)
+	return nil
+}
+
+// resolveOffset translates OffsetNewest and OffsetOldest into actual offsets
+// and checks that any other offset is within the partition's log.
+func (child *partitionConsumer) resolveOffset(offset int64) (int64, error) {
+	newestOffset, err := child.consumer.client.GetOffset(child.topic, child.partition, OffsetNewest)
+	if err != nil {
+		return 0, err
+	}
+
 	child.highWaterMarkOffset.Store(newestOffset)
 
 	oldestOffset, err := child.consumer.client.GetOffset(child.topic, child.partition, OffsetOldest)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	switch {
 	case offset == OffsetNewest:
-		child.offset = newestOffset
+		return newestOffset, nil
 	case offset == OffsetOldest:
-		child.offset = oldestOffset
+		return oldestOffset, nil
 	case offset >= oldestOffset && offset <= newestOffset:
-		child.offset = offset
+		return offset, nil
 	default:
-		return ErrOffsetOutOfRange
+		return 0, ErrOffsetOutOfRange
+	}
+}
+
+// Seek implements PartitionConsumer.
+//
+// The new offset is only recorded here. The responseFeeder discards what it
+// has not yet delivered, along with any response fetched before the seek,
+// and the brokerConsumer applies the offset before its next fetch.
+func (child *partitionConsumer) Seek(offset int64) error {
+	resolved, err := child.resolveOffset(offset)
+	if err != nil {
+		return err
 	}
 
+	child.setPendingSeek(resolved, invalidLeaderEpoch)
+
+	flushed := make(chan none)
+	select {
+	case child.seeks <- flushed:
+	case <-child.dying:
+		return ErrClosedPartitionConsumer
+	}
+	<-flushed
+
+	child.delivered.Store(resolved)
+	child.deliveredTimestamp.Store(0)
+	child.updateLagMetrics()
 	return nil
 }
 
+// SeekToTimestamp implements PartitionConsumer.
+func (child *partitionConsumer) SeekToTimestamp(t time.Time) error {
+	offset, err := child.consumer.client.GetOffset(child.topic, child.partition, t.UnixMilli())
+	if err != nil {
+		return err
+	}
+	// the broker answers -1, which is also OffsetNewest, if no message has a
+	// timestamp at or after t
+	return child.Seek(offset)
+}
+
+// setPendingSeek records the position the next fetch starts from.
+func (child *partitionConsumer) setPendingSeek(offset int64, epoch int32) {
+	child.seekLock.Lock()
+	defer child.seekLock.Unlock()
+
+	child.seekOffset = offset
+	child.seekEpoch = epoch
+	child.seekPending = true
+}
+
+func (child *partitionConsumer) isSeekPending() bool {
+	child.seekLock.Lock()
+	defer child.seekLock.Unlock()
+
+	return child.seekPending
+}
+
+// applyPendingSeek moves the fetch position to the offset of the last seek.
+// It must only be called while no response is being fed to the child.
+func (child *partitionConsumer) applyPendingSeek() {
+	child.seekLock.Lock()
+	defer child.seekLock.Unlock()
+
+	if !child.seekPending {
+		return
+	}
+	child.offset.Store(child.seekOffset)
+	child.lastFetchedEpoch.Store(child.seekEpoch)
+	child.fetchSize = child.conf.Consumer.Fetch.Default
+	if child.acks != nil {
+		// the discarded messages will never be acknowledged
+		child.acks.reset(child.seekOffset)
+	}
+	child.seekPending = false
+}
+
+// flushMessages discards the messages buffered in the Messages channel. It
+// must only be called from the responseFeeder, the channel's only writer.
+func (child *partitionConsumer) flushMessages(flushed chan none) {
+	defer close(flushed)
+	defer child.bufferFlushed()
+	for {
+		select {
+		case <-child.messages:
+		case <-child.batches:
+		default:
+			return
+		}
+	}
+}
+
 func (child *partitionConsumer) Messages() <-chan *ConsumerMessage {
 	return child.messages
 }
@@ -720,40 +1044,106 @@ func (child *partitionConsumer) HighWaterMarkOffset() int64 {
 	return child.highWaterMarkOffset.Load()
 }
 
+func (child *partitionConsumer) Lag() ConsumerLag {
+	lag := ConsumerLag{Messages: max(child.highWaterMarkOffset.Load()-child.delivered.Load(), 0)}
+	if timestamp := child.deliveredTimestamp.Load(); lag.Messages > 0 && timestamp > 0 {
+		lag.Time = max(time.Since(time.Unix(0, timestamp)), 0)
+	}
+	return lag
+}
+
+// handOver records a message as handed over to the user, just before it is
+// sent on the Messages or batches channel.
+func (child *partitionConsumer) handOver(msg *ConsumerMessage) {
+	child.interceptors(msg)
+
+	child.delivered.Store(msg.Offset + 1)
+	if msg.Timestamp.IsZero() {
+		child.deliveredTimestamp.Store(0)
+	} else {
+		child.deliveredTimestamp.Store(msg.Timestamp.UnixNano())
+	}
+	child.updateLagMetrics()
+}
+
+func (child *partitionConsumer) updateLagMetrics() {
+	if child.lagGauge == nil {
+		return
+	}
+	lag := child.Lag()
+	child.lagGauge.Update(lag.Messages)
+	child.timeLagGauge.Update(lag.Time.Milliseconds())
+}
+
 func (child *partitionConsumer) responseFeeder() {
 	var msgs []*ConsumerMessage
 	expiryTicker := time.NewTicker(child.conf.Consumer.MaxProcessingTime)
 	firstAttempt := true
 
 feederLoop:
-	for feederResponse := range child.feeder {
+	for {
+		var feederResponse *partitionConsumerResponse
+		select {
+		case response, ok := <-child.feeder:
+			if !ok {
+				break feederLoop
+			}
+			feederResponse = response
+		case flushed := <-child.seeks:
+			child.flushMessages(flushed)
+			continue feederLoop
+		}
 		broker := feederResponse.broker
 		subscription := feederResponse.subscription
 
+		if child.isSeekPending() {
+			// fetched from the position before the seek
+			broker.acks.Done()
+			continue feederLoop
+		}
+
 		msgs, child.responseResult = child.parseResponse(feederResponse.response)
 
 		if child.responseResult == nil {
 			child.retries.Store(0)
 		}
+		if child.acks != nil {
+			child.acks.deliver(msgs...)
+		}
+		child.bufferParsed(msgs)
+
+		if child.batches != nil {
+			child.feedBatch(broker, subscription, msgs, expiryTicker, &firstAttempt)
+			continue feederLoop
+		}
 
 		for i, msg := range msgs {
-			child.interceptors(msg)
+			child.handOver(msg)
 		messageSelect:
 			select {
 			case <-child.dying:
 				broker.acks.Done()
 				continue feederLoop
 			case child.messages <- msg:
+				child.bufferSent(int64(consumerMessageSize(msg)))
 				firstAttempt = true
+			case flushed := <-child.seeks:
+				child.flushMessages(flushed)
+				broker.acks.Done()
+				continue feederLoop
 			case <-expiryTicker.C:
 				if !firstAttempt {
 					child.responseResult = errTimedOut
 					broker.acks.Done()
 				remainingLoop:
 					for _, msg = range msgs[i:] {
-						child.interceptors(msg)
+						child.handOver(msg)
 						select {
 						case child.messages <- msg:
+							child.bufferSent(int64(consumerMessageSize(msg)))
+						case flushed := <-child.seeks:
+							child.flushMessages(flushed)
+							break remainingLoop
 						case <-child.dying:
 							break remainingLoop
 						}
@@ -779,9 +1169,67 @@ feederLoop:
 
 	expiryTicker.Stop()
 	close(child.messages)
+	if child.batches != nil {
+		close(child.batches)
+	}
+	child.bufferFlushed()
 	close(child.errors)
 }
 
+// feedBatch is the batches counterpart of the message loop of the
+// responseFeeder: all the messages of a response are handed over at once,
+// with the same handling of MaxProcessingTime as for single messages.
+func (child *partitionConsumer) feedBatch(broker *brokerConsumer, subscription *brokerSubscription, msgs []*ConsumerMessage, expiryTicker *time.Ticker, firstAttempt *bool) {
+	if len(msgs) == 0 {
+		broker.acks.Done()
+		return
+	}
+	var size int64
+	for _, msg := range msgs {
+		child.handOver(msg)
+		size += int64(consumerMessageSize(msg))
+	}
+
+	for {
+		select {
+		case <-child.dying:
+			broker.acks.Done()
+			return
+		case child.batches <- msgs:
+			child.bufferSent(size)
+			*firstAttempt = true
+			broker.acks.Done()
+			return
+		case flushed := <-child.seeks:
+			child.flushMessages(flushed)
+			broker.acks.Done()
+			return
+		case <-expiryTicker.C:
+			if *firstAttempt {
+				// the batch has not been sent yet, try again
+				*firstAttempt = false
+				continue
+			}
+			child.responseResult = errTimedOut
+			broker.acks.Done()
+			select {
+			case child.batches <- msgs:
+				child.bufferSent(size)
+			case flushed := <-child.seeks:
+				child.flushMessages(flushed)
+			case <-child.dying:
+			}
+			if !broker.queueSubscription(subscription) {
+				// the broker is shutting down; release so any waiter on
+				// the dispatcher side can make progress
+				subscription.release()
+				child.triggerRedispatch()
+			}
+			return
+		}
+	}
+}
+
 func (child *partitionConsumer) parseMessages(msgSet *MessageSet) ([]*ConsumerMessage, error) {
 	var messages []*ConsumerMessage
 	for _, msgBlock := range msgSet.Messages {
@@ -795,7 +1243,7 @@ func (child *partitionConsumer) parseMessages(msgSet *MessageSet) ([]*ConsumerMe
 					timestamp = msgBlock.Msg.Timestamp
 				}
 			}
-			if offset < child.offset {
+			if offset < child.offset.Load() {
 				continue
 			}
 			messages = append(messages, &ConsumerMessage{
@@ -807,11 +1255,11 @@ func (child *partitionConsumer) parseMessages(msgSet *MessageSet) ([]*ConsumerMe
 				Timestamp:      timestamp,
 				BlockTimestamp: msgBlock.Msg.Timestamp,
 			})
-			child.offset = offset + 1
+			child.offset.Store(offset + 1)
 		}
 	}
 	if len(messages) == 0 {
-		child.offset++
+		child.offset.Add(1)
 	}
 	return messages, nil
 }
@@ -821,7 +1269,7 @@ func (child *partitionConsumer) parseRecords(batch *RecordBatch) ([]*ConsumerMes
 
 	for _, rec := range batch.Records {
 		offset := batch.FirstOffset + rec.OffsetDelta
-		if offset < child.offset {
+		if offset < child.offset.Load() {
 			continue
 		}
 		timestamp := batch.FirstTimestamp.Add(rec.TimestampDelta)
@@ -837,10 +1285,11 @@ func (child *partitionConsumer) parseRecords(batch *RecordBatch) ([]*ConsumerMes
 			Timestamp: timestamp,
 			Headers:   rec.Headers,
 		})
-		child.offset = offset + 1
+		child.offset.Store(offset + 1)
+		child.lastFetchedEpoch.Store(batch.PartitionLeaderEpoch)
 	}
 	if len(messages) == 0 {
-		child.offset++
+		child.offset.Add(1)
 	}
 	return messages, nil
 }
@@ -893,7 +1342,7 @@ func (child *partitionConsumer) parseResponse(response *FetchResponse) ([]*Consu
 			if child.conf.Consumer.Fetch.Max > 0 && child.fetchSize == child.conf.Consumer.Fetch.Max {
 				// we can't ask for more data, we've hit the configured limit
 				child.sendError(ErrMessageTooLarge)
-				child.offset++ // skip this one so we can keep processing future messages
+				child.offset.Add(1) // skip this one so we can keep processing future messages
 			} else {
 				// if the broker told us the exact size of the partial batch, request that
 				// directly; otherwise fall back to doubling the fetch size
@@ -913,7 +1362,7 @@ func (child *partitionConsumer) parseResponse(response *FetchResponse) ([]*Consu
 		} else if block.recordsNextOffset != nil && *block.recordsNextOffset <= block.HighWaterMarkOffset {
 			// check last record next offset to avoid stuck if high watermark was not reached
 			Logger.Printf("consumer/broker/%d received batch with zero records but high watermark was not reached, topic %s, partition %d, next offset %d\n", child.broker.broker.ID(), child.topic, child.partition, *block.recordsNextOffset)
-			child.offset = *block.recordsNextOffset
+			child.offset.Store(*block.recordsNextOffset)
 		}
 
 		return nil, nil
@@ -922,6 +1371,7 @@ func (child *partitionConsumer) parseResponse(response *FetchResponse) ([]*Consu
 	// we got messages, reset our fetch size in case it was increased for a previous request
 	child.fetchSize = child.conf.Consumer.Fetch.Default
 	child.highWaterMarkOffset.Store(block.HighWaterMarkOffset)
+	child.updateLagMetrics()
 
 	// abortedProducerIDs contains producerID which message should be ignored as uncommitted
 	// - producerID are added when the partitionConsumer iterate over the offset at which an aborted transaction begins (abortedTransaction.FirstOffset)
@@ -1026,6 +1476,7 @@ type brokerConsumer struct {
 	input            chan *brokerSubscription
 	newSubscriptions chan []*brokerSubscription
 	subscriptions    map[*partitionConsumer]*brokerSubscription
+	session          *fetchSession
 	acks             sync.WaitGroup
 	refs             int
 	stop             chan none
@@ -1039,6 +1490,7 @@ func (c *consumer) newBrokerConsumer(broker *Broker) *brokerConsumer {
 		input:            make(chan *brokerSubscription),
 		newSubscriptions: make(chan []*brokerSubscription),
 		subscriptions:    make(map[*partitionConsumer]*brokerSubscription),
+		session:          newFetchSession(c.metricRegistry),
 		refs:             0,
 		stop:             make(chan none),
 	}
@@ -1326,13 +1778,6 @@ func (bc *brokerConsumer) fetchNewMessages() (*FetchResponse, error) {
 	if request.Version >= 4 {
 		request.Isolation = bc.consumer.conf.Consumer.IsolationLevel
 	}
-	if request.Version >= 7 {
-		// We do not currently implement KIP-227 FetchSessions. Setting the id to 0
-		// and the epoch to -1 tells the broker not to generate as session ID we're going
-		// to just ignore anyway.
-		request.SessionID = 0
-		request.SessionEpoch = -1
-	}
 	if request.Version >= 11 {
 		request.RackID = bc.consumer.conf.RackID
 	}
@@ -1346,17 +1791,30 @@ func (bc *brokerConsumer) fetchNewMessages() (*FetchResponse, error) {
 		default:
 		}
 
-		if !child.IsPaused() {
-			request.AddBlock(child.topic, child.partition, child.offset, child.fetchSize, child.leaderEpoch)
+		child.applyPendingSeek()
+		if !child.IsPaused() && (child.acks == nil || !child.acks.full()) && !child.overBudget() {
+			bc.session.add(child.topic, child.partition, fetchSessionPartition{
+				fetchOffset: child.offset.Load(),
+				maxBytes:    child.fetchSize,
+				leaderEpoch: child.leaderEpoch,
+			})
 		}
 	}
 
-	// avoid to fetch when there is no block
-	if len(request.blocks) == 0 {
+	// avoid to fetch when there is no partition to fetch. From v7 onwards
+	// the session fills in only the partitions that changed since the
+	// previous request, so an incremental fetch may carry no block at all.
+	if !bc.session.build(request) {
 		return nil, nil
 	}
 
-	return bc.broker.Fetch(request)
+	response, err := bc.broker.Fetch(request)
+	if err != nil {
+		bc.session.reset()
+		return nil, err
+	}
+	bc.session.handleResponse(request, response)
+	return response, nil
 }
 
 func (bc *brokerConsumer) stopConsuming() {
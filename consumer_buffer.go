@@ -0,0 +1,101 @@
+package sarama
+
+import "sync"
+
+// messageBuffer accounts for the bytes of the messages of a partition consumer
+// that were fetched but not yet received by the user, against
+// Consumer.MaxBufferedBytes.
+type messageBuffer struct {
+	lock sync.Mutex
+	// parsed counts the messages of the response being fed that are not sent
+	// on the Messages or batches channel yet
+	parsed int64
+	// sent holds the size of every message, or batch, sent on the channel in
+	// order, until the user receives it; sentBytes is their sum
+	sent      []int64
+	sentBytes int64
+}
+
+// bufferParsed accounts for the messages of a response about to be fed.
+func (child *partitionConsumer) bufferParsed(msgs []*ConsumerMessage) {
+	var size int64
+	for _, msg := range msgs {
+		size += int64(consumerMessageSize(msg))
+	}
+
+	child.buffer.lock.Lock()
+	child.buffer.parsed += size
+	child.buffer.lock.Unlock()
+
+	child.consumer.addBuffered(size)
+}
+
+// bufferSent records a message, or a batch, of the given size once it is sent
+// on the Messages or batches channel. It stays buffered until the user
+// receives it.
+func (child *partitionConsumer) bufferSent(size int64) {
+	child.buffer.lock.Lock()
+	child.buffer.parsed -= size
+	child.buffer.sent = append(child.buffer.sent, size)
+	child.buffer.sentBytes += size
+	child.buffer.lock.Unlock()
+}
+
+// bufferedBytes returns the number of bytes buffered for the partition, after
+// releasing the messages received by the user.
+func (child *partitionConsumer) bufferedBytes() int64 {
+	child.buffer.lock.Lock()
+	// The feeder is the only sender and records a message once it is sent, so
+	// the channels hold at least the messages recorded and not received yet:
+	// the older ones were received.
+	pending := len(child.messages) + len(child.batches)
+	var released int64
+	for len(child.buffer.sent) > pending {
+		released += child.buffer.sent[0]
+		child.buffer.sent = child.buffer.sent[1:]
+	}
+	child.buffer.sentBytes -= released
+	buffered := child.buffer.parsed + child.buffer.sentBytes
+	child.buffer.lock.Unlock()
+
+	child.consumer.addBuffered(-released)
+	return buffered
+}
+
+// bufferFlushed releases all the bytes buffered for the partition, once the
+// messages are discarded.
+func (child *partitionConsumer) bufferFlushed() {
+	child.buffer.lock.Lock()
+	released := child.buffer.parsed + child.buffer.sentBytes
+	child.buffer.parsed = 0
+	child.buffer.sent = nil
+	child.buffer.sentBytes = 0
+	child.buffer.lock.Unlock()
+
+	child.consumer.addBuffered(-released)
+}
+
+// overBudget reports whether the partition buffers more than its share of
+// Consumer.MaxBufferedBytes, in which case it should not be fetched from.
+func (child *partitionConsumer) overBudget() bool {
+	// always release the messages received, for the buffered bytes metric
+	buffered := child.bufferedBytes()
+	budget := int64(child.conf.Consumer.MaxBufferedBytes)
+	if budget <= 0 {
+		return false
+	}
+	share := budget / max(child.consumer.childCount.Load(), 1)
+	return buffered > share
+}
+
+// addBuffered updates the number of bytes buffered by all the partition
+// consumers.
+func (c *consumer) addBuffered(delta int64) {
+	if c == nil || delta == 0 {
+		return
+	}
+	buffered := c.buffered.Add(delta)
+	if c.bufferedGauge != nil {
+		c.bufferedGauge.Update(buffered)
+	}
+}
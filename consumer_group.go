@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
 	"slices"
 	"sort"
 	"sync"
@@ -29,6 +31,11 @@ var ErrSessionConsumeClaimExited = errors.New("kafka: ConsumeClaim goroutine exi
 // unreachable after retries).
 var ErrSessionHeartbeatFailed = errors.New("kafka: heartbeat loop failed")
 
+// ErrSessionSubscriptionChanged is set as the cancellation cause of a consumer group
+// session context when the set of topics matching the pattern passed to ConsumeMatching
+// has changed, requiring the member to rejoin with the new subscription.
+var ErrSessionSubscriptionChanged = errors.New("kafka: topics matching the subscribed pattern changed")
+
 // ConsumerGroup is responsible for dividing up processing of topics and partitions
 // over a collection of processes (the members of the consumer group).
 type ConsumerGroup interface {
@@ -58,8 +65,26 @@ type ConsumerGroup interface {
 	// This method should be called inside an infinite loop, when a
 	// server-side rebalance happens, the consumer session will need to be
 	// recreated to get the new claims.
+	//
+	// With Config.Consumer.Group.Protocol set to GroupProtocolConsumer, the group
+	// coordinator computes the assignment (KIP-848) and changes it incrementally
+	// during the session: the ConsumeClaim of a revoked partition sees its
+	// Messages() channel closed and must return, its offset is committed, and
+	// a ConsumeClaim is started for every newly assigned partition, while the
+	// other claims keep running. Claims() reports the current claims.
 	Consume(ctx context.Context, topics []string, handler ConsumerGroupHandler) error
 
+	// ConsumeMatching is like Consume, but subscribes to all the topics of the
+	// cluster whose name matches the pattern. The pattern is matched against
+	// every topic name, including internal ones, so it should usually be anchored.
+	//
+	// The pattern is evaluated again every Config.Metadata.RefreshFrequency and the
+	// session ends with ErrSessionSubscriptionChanged when the set of matching topics
+	// changes, so that the next call to ConsumeMatching rejoins the group with the
+	// new subscription. While no topic matches, the call blocks until one is created,
+	// the context is canceled or the group is closed.
+	ConsumeMatching(ctx context.Context, pattern *regexp.Regexp, handler ConsumerGroupHandler) error
+
 	// Errors returns a read channel of errors that occurred during the consumer life-cycle.
 	// By default, errors are logged and not returned over this channel.
 	// If you want to implement any custom error handling, set your config's
@@ -89,6 +114,10 @@ type ConsumerGroup interface {
 	// Resume resumes all partitions which have been paused with Pause()/PauseAll().
 	// New calls to the broker will return records from these partitions if there are any to be fetched.
 	ResumeAll()
+
+	// Lag returns a snapshot of the lag of every partition claimed by the
+	// running session, by topic and partition, see ConsumerGroupClaim.Lag.
+	Lag() map[string]map[int32]ConsumerLag
 }
 
 type consumerGroup struct {
@@ -112,6 +141,14 @@ type consumerGroup struct {
 	// protocol is fixed at construction from the configured balance strategies
 	protocol RebalanceProtocol
 
+	// membership state of the consumer group protocol (KIP-848), only
+	// touched by Consume and the session heartbeat loop it waits for
+	memberEpoch       int32
+	assignment        map[string][]int32
+	subscription      []string
+	heartbeatInterval time.Duration
+	topicIDs          *topicIDCache
+
 	metricRegistry metrics.Registry
 }
 
@@ -168,6 +205,9 @@ func newConsumerGroup(groupID string, client Client) (ConsumerGroup, error) {
 		userData:       config.Consumer.Group.Member.UserData,
 		protocol:       protocol,
 		metricRegistry: newCleanupRegistry(config.MetricRegistry),
+
+		heartbeatInterval: config.Consumer.Group.Heartbeat.Interval,
+		topicIDs:          newTopicIDCache(),
 	}
 	if config.Consumer.Group.InstanceId != "" && config.Version.IsAtLeast(V2_3_0_0) {
 		cg.groupInstanceId = &config.Consumer.Group.InstanceId
@@ -230,14 +270,56 @@ func (c *consumerGroup) Consume(ctx context.Context, topics []string, handler Co
 		return err
 	}
 
+	return c.consume(ctx, topics, handler, nil)
+}
+
+// ConsumeMatching implements ConsumerGroup.
+func (c *consumerGroup) ConsumeMatching(ctx context.Context, pattern *regexp.Regexp, handler ConsumerGroupHandler) error {
+	// Ensure group is not closed
+	select {
+	case <-c.closed:
+		return ErrClosedConsumerGroup
+	default:
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if pattern == nil {
+		return fmt.Errorf("no topic pattern provided")
+	}
+
+	topics, err := c.waitForMatchingTopics(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	return c.consume(ctx, topics, handler, pattern)
+}
+
+// consume runs a single session for the given topics. When pattern is set,
+// the session also ends once the topics matching it change.
+func (c *consumerGroup) consume(ctx context.Context, topics []string, handler ConsumerGroupHandler, pattern *regexp.Regexp) error {
 	// Init session
-	sess, err := c.newSession(ctx, topics, handler, c.config.Consumer.Group.Rebalance.Retry.Max)
+	var sess *consumerGroupSession
+	var err error
+	if c.config.Consumer.Group.Protocol == GroupProtocolConsumer {
+		sess, err = c.newAssignedSession(ctx, topics, handler, c.config.Consumer.Group.Rebalance.Retry.Max)
+	} else {
+		sess, err = c.newSession(ctx, topics, handler, c.config.Consumer.Group.Rebalance.Retry.Max)
+	}
 	if errors.Is(err, ErrClosedClient) {
 		return ErrClosedConsumerGroup
 	} else if err != nil {
 		return err
 	}
 
+	// every member checks its own subscription, unlike the partition count
+	// which only the leader needs to watch
+	if pattern != nil {
+		go c.loopCheckMatchingTopics(pattern, topics, sess)
+	}
+
 	// Wait for session exit signal or Close() call
 	select {
 	case <-c.closed:
@@ -278,6 +360,14 @@ func (c *consumerGroup) ResumeAll() {
 	c.consumer.ResumeAll()
 }
 
+// Lag implements ConsumerGroup.
+func (c *consumerGroup) Lag() map[string]map[int32]ConsumerLag {
+	if cons, ok := c.consumer.(*consumer); ok {
+		return cons.lags()
+	}
+	return map[string]map[int32]ConsumerLag{}
+}
+
 func (c *consumerGroup) retryJoinSync(ctx context.Context, topics []string, held *heldAssignment, retries int, refreshCoordinator bool) (*rebalanceResult, error) {
 	select {
 	case <-ctx.Done():
@@ -772,6 +862,10 @@ func (c *consumerGroup) leave() error {
 		return err
 	}
 
+	if c.config.Consumer.Group.Protocol == GroupProtocolConsumer {
+		return c.leaveAssigned(coordinator)
+	}
+
 	// as per KIP-345 if groupInstanceId is set, i.e. static membership is in action, then do not leave group when consumer closed, just clear memberID
 	if c.groupInstanceId != nil {
 		c.memberID = ""
@@ -890,6 +984,86 @@ func (c *consumerGroup) loopCheckPartitionNumbers(allSubscribedTopicPartitions m
 	}
 }
 
+// matchingTopics refreshes the metadata of all the topics of the cluster and
+// returns the sorted names of those matching the pattern.
+func (c *consumerGroup) matchingTopics(pattern *regexp.Regexp) ([]string, error) {
+	if err := c.client.RefreshMetadata(); err != nil {
+		return nil, err
+	}
+	topics, err := c.client.Topics()
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if pattern.MatchString(topic) {
+			matching = append(matching, topic)
+		}
+	}
+	sort.Strings(matching)
+	return matching, nil
+}
+
+// waitForMatchingTopics returns the topics matching the pattern, polling every
+// Metadata.RefreshFrequency while there are none.
+func (c *consumerGroup) waitForMatchingTopics(ctx context.Context, pattern *regexp.Regexp) ([]string, error) {
+	for {
+		topics, err := c.matchingTopics(pattern)
+		if errors.Is(err, ErrClosedClient) {
+			return nil, ErrClosedConsumerGroup
+		} else if err != nil {
+			return nil, err
+		}
+		if len(topics) > 0 {
+			return topics, nil
+		}
+		if c.config.Metadata.RefreshFrequency == time.Duration(0) {
+			return nil, fmt.Errorf("no topics match %q", pattern)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, ErrClosedConsumerGroup
+		case <-time.After(c.config.Metadata.RefreshFrequency):
+		}
+	}
+}
+
+func (c *consumerGroup) loopCheckMatchingTopics(pattern *regexp.Regexp, topics []string, session *consumerGroupSession) {
+	if c.config.Metadata.RefreshFrequency == time.Duration(0) {
+		return
+	}
+
+	pause := time.NewTicker(c.config.Metadata.RefreshFrequency)
+	defer pause.Stop()
+	for {
+		select {
+		case <-pause.C:
+		case <-session.ctx.Done():
+			return
+		case <-c.closed:
+			return
+		}
+
+		matching, err := c.matchingTopics(pattern)
+		if err != nil {
+			Logger.Printf(
+				"consumergroup/%s loop check matching topics goroutine failed to refresh metadata due to '%v'\n",
+				c.groupID, err)
+			continue
+		}
+		if !slices.Equal(matching, topics) {
+			Logger.Printf(
+				"consumergroup/%s loop check matching topics goroutine find topics matching %q changed from %s to %s\n",
+				c.groupID, pattern, topics, matching)
+			session.cancel(ErrSessionSubscriptionChanged)
+			return
+		}
+	}
+}
+
 func (c *consumerGroup) topicToPartitionNumbers(topics []string) (map[string]int, error) {
 	topicToPartitionNum := make(map[string]int, len(topics))
 	for _, topic := range topics {
@@ -915,7 +1089,8 @@ type ConsumerGroupSession interface {
 	// MemberID returns the cluster member ID.
 	MemberID() string
 
-	// GenerationID returns the current generation ID.
+	// GenerationID returns the current generation ID, or the member epoch the
+	// session started with when using the consumer group protocol.
 	GenerationID() int32
 
 	// MarkOffset marks the provided offset, alongside a metadata string
@@ -948,6 +1123,13 @@ type ConsumerGroupSession interface {
 	// MarkMessage marks a message as consumed.
 	MarkMessage(msg *ConsumerMessage, metadata string)
 
+	// AckMessage acknowledges a message as consumed, which unlike MarkMessage
+	// may be done in any order for the messages of a claim, for example when
+	// they are processed concurrently. Only the offset following the contiguous
+	// range of acknowledged messages is marked. It requires
+	// Config.Consumer.Offsets.Ack.Enable, see PartitionOffsetManager.Ack.
+	AckMessage(msg *ConsumerMessage) error
+
 	// Context returns the session context.
 	Context() context.Context
 }
@@ -958,14 +1140,29 @@ type consumerGroupSession struct {
 	generationID int32
 	handler      ConsumerGroupHandler
 
-	claims  map[string][]int32
 	offsets *offsetManager
 	ctx     context.Context
 	cancel  context.CancelCauseFunc
 
+	// claimsLock guards claims, running and releasing, as the consumer group
+	// protocol assigns and revokes partitions during a session
+	claimsLock sync.Mutex
+	claims     map[string][]int32
+	running    map[string]map[int32]*sessionClaim
+	releasing  bool
+
 	waitGroup       sync.WaitGroup
 	releaseOnce     sync.Once
 	hbDying, hbDead chan none
+
+	// owning is set once the handler is notified of the assigned claims
+	owning bool
+}
+
+// sessionClaim tracks the goroutine consuming a claimed partition.
+type sessionClaim struct {
+	stop chan none // closed to stop consuming a revoked partition
+	done chan none // closed once the partition is no longer consumed
 }
 
 func newConsumerGroupSession(ctx context.Context, parent *consumerGroup, claims map[string][]int32, memberID string, generationID int32, handler ConsumerGroupHandler) (*consumerGroupSession, error) {
@@ -985,7 +1182,7 @@ func newConsumerGroupSession(ctx context.Context, parent *consumerGroup, claims
 		generationID: generationID,
 		handler:      handler,
 		offsets:      offsets,
-		claims:       claims,
+		claims:       maps.Clone(claims),
 		ctx:          ctx,
 		cancel:       cancel,
 		hbDying:      make(chan none),
@@ -993,23 +1190,19 @@ func newConsumerGroupSession(ctx context.Context, parent *consumerGroup, claims
 	}
 
 	// start heartbeat loop
-	go sess.heartbeatLoop()
+	if parent.config.Consumer.Group.Protocol == GroupProtocolConsumer {
+		go sess.assignedHeartbeatLoop()
+	} else {
+		go sess.heartbeatLoop()
+	}
 
 	// create a POM for each claim
 	for topic, partitions := range claims {
 		for _, partition := range partitions {
-			pom, err := offsets.ManagePartition(topic, partition)
-			if err != nil {
+			if err := sess.managePartition(topic, partition); err != nil {
 				_ = sess.release(false)
 				return nil, err
 			}
-
-			// handle POM errors
-			go func(topic string, partition int32) {
-				for err := range pom.Errors() {
-					sess.parent.handleError(err, topic, partition)
-				}
-			}(topic, partition)
 		}
 	}
 
@@ -1018,44 +1211,107 @@ func newConsumerGroupSession(ctx context.Context, parent *consumerGroup, claims
 		_ = sess.release(true)
 		return nil, err
 	}
+	sess.assignOwned()
 
 	// start consuming each topic partition in its own goroutine
+	sess.claimsLock.Lock()
+	started := sess.addRunning(claims)
+	sess.claimsLock.Unlock()
+	for _, start := range started {
+		go start()
+	}
+	return sess, nil
+}
+
+// managePartition creates the POM of a claimed partition and handles its
+// errors.
+func (s *consumerGroupSession) managePartition(topic string, partition int32) error {
+	pom, err := s.offsets.ManagePartition(topic, partition)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for err := range pom.Errors() {
+			s.parent.handleError(err, topic, partition)
+		}
+	}()
+	return nil
+}
+
+// addRunning records the given claims as being consumed and returns the
+// functions consuming them, to be run in their own goroutine. It must be
+// called with claimsLock held, and it returns nothing once the session is
+// being released.
+func (s *consumerGroupSession) addRunning(claims map[string][]int32) []func() {
+	if s.releasing {
+		return nil
+	}
+	if s.running == nil {
+		s.running = make(map[string]map[int32]*sessionClaim)
+	}
+
+	var started []func()
 	for topic, partitions := range claims {
+		if s.running[topic] == nil {
+			s.running[topic] = make(map[int32]*sessionClaim)
+		}
 		for _, partition := range partitions {
-			sess.waitGroup.Add(1) // increment wait group before spawning goroutine
-			go func(topic string, partition int32) {
-				defer sess.waitGroup.Done()
-				// cancel the group session as soon as any of the consume calls return
-				defer sess.cancel(ErrSessionConsumeClaimExited)
-
-				// if partition not currently readable, wait for it to become readable
-				if sess.parent.client.PartitionNotReadable(topic, partition) {
-					timer := time.NewTimer(5 * time.Second)
-					defer timer.Stop()
-
-					for sess.parent.client.PartitionNotReadable(topic, partition) {
-						select {
-						case <-ctx.Done():
-							return
-						case <-parent.closed:
-							return
-						case <-timer.C:
-							timer.Reset(5 * time.Second)
-						}
-					}
-				}
+			claim := &sessionClaim{stop: make(chan none), done: make(chan none)}
+			s.running[topic][partition] = claim
+			s.waitGroup.Add(1) // increment wait group before spawning goroutine
+			started = append(started, func() { s.run(topic, partition, claim) })
+		}
+	}
+	return started
+}
+
+// run consumes a claimed partition until the session ends or the partition
+// is revoked.
+func (s *consumerGroupSession) run(topic string, partition int32, claim *sessionClaim) {
+	defer s.waitGroup.Done()
+	defer close(claim.done)
+	// cancel the group session as soon as any of the consume calls return,
+	// unless the partition was revoked
+	defer func() {
+		select {
+		case <-claim.stop:
+		default:
+			s.cancel(ErrSessionConsumeClaimExited)
+		}
+	}()
+
+	// if partition not currently readable, wait for it to become readable
+	if s.parent.client.PartitionNotReadable(topic, partition) {
+		timer := time.NewTimer(5 * time.Second)
+		defer timer.Stop()
 
-				// consume a single topic/partition, blocking
-				sess.consume(topic, partition)
-			}(topic, partition)
+		for s.parent.client.PartitionNotReadable(topic, partition) {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.parent.closed:
+				return
+			case <-claim.stop:
+				return
+			case <-timer.C:
+				timer.Reset(5 * time.Second)
+			}
 		}
 	}
-	return sess, nil
+
+	// consume a single topic/partition, blocking
+	s.consume(topic, partition, claim.stop)
 }
 
-func (s *consumerGroupSession) Claims() map[string][]int32 { return s.claims }
-func (s *consumerGroupSession) MemberID() string           { return s.memberID }
-func (s *consumerGroupSession) GenerationID() int32        { return s.generationID }
+func (s *consumerGroupSession) Claims() map[string][]int32 {
+	s.claimsLock.Lock()
+	defer s.claimsLock.Unlock()
+	return maps.Clone(s.claims)
+}
+
+func (s *consumerGroupSession) MemberID() string    { return s.memberID }
+func (s *consumerGroupSession) GenerationID() int32 { return s.generationID }
 
 func (s *consumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
 	if pom := s.offsets.findPOM(topic, partition); pom != nil {
@@ -1077,6 +1333,13 @@ func (s *consumerGroupSession) MarkMessage(msg *ConsumerMessage, metadata string
 	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
 }
 
+func (s *consumerGroupSession) AckMessage(msg *ConsumerMessage) error {
+	if pom := s.offsets.findPOM(msg.Topic, msg.Partition); pom != nil {
+		return pom.Ack(msg.Offset)
+	}
+	return nil
+}
+
 func (s *consumerGroupSession) Context() context.Context {
 	return s.ctx
 }
@@ -1125,13 +1388,15 @@ func isRetriableClaimError(err error) bool {
 		errors.Is(err, ErrReplicaNotAvailable)
 }
 
-func (s *consumerGroupSession) consume(topic string, partition int32) {
+func (s *consumerGroupSession) consume(topic string, partition int32, stop <-chan none) {
 	// quick exit if rebalance is due
 	select {
 	case <-s.ctx.Done():
 		return
 	case <-s.parent.closed:
 		return
+	case <-stop:
+		return
 	default:
 	}
 
@@ -1148,17 +1413,18 @@ func (s *consumerGroupSession) consume(topic string, partition int32) {
 		return
 	}
 
-	// trigger close when session is done
+	// trigger close when session is done or the partition is revoked
 	go func() {
 		select {
 		case <-s.ctx.Done():
 		case <-s.parent.closed:
+		case <-stop:
 		}
 		claim.AsyncClose()
 	}()
 
 	// start processing
-	if err := s.handler.ConsumeClaim(s, claim); err != nil {
+	if err := s.consumeClaim(claim); err != nil {
 		s.parent.handleError(err, topic, partition)
 	}
 
@@ -1173,6 +1439,11 @@ func (s *consumerGroupSession) release(withCleanup bool) (err error) {
 	// signal release, stop heartbeat
 	s.cancel(nil)
 
+	// no partition is consumed or revoked from now on
+	s.claimsLock.Lock()
+	s.releasing = true
+	s.claimsLock.Unlock()
+
 	// wait for consumers to exit
 	s.waitGroup.Wait()
 
@@ -1191,6 +1462,8 @@ func (s *consumerGroupSession) release(withCleanup bool) (err error) {
 
 		close(s.hbDying)
 		<-s.hbDead
+
+		s.releaseOwned()
 	})
 
 	Logger.Printf(
@@ -1294,6 +1567,8 @@ func sessionCauseToReason(cause error) string {
 		return "a ConsumeClaim handler has exited"
 	case errors.Is(cause, ErrSessionHeartbeatFailed):
 		return "the heartbeat goroutine has stopped"
+	case errors.Is(cause, ErrSessionSubscriptionChanged):
+		return "the topics matching the subscribed pattern changed"
 	default:
 		return cause.Error()
 	}
@@ -1339,6 +1614,10 @@ type ConsumerGroupClaim interface {
 	// You can use this to determine how far behind the processing is.
 	HighWaterMarkOffset() int64
 
+	// Lag returns how far the messages handed over on the Messages channel, or
+	// in batches, are behind the high watermark offset of the partition.
+	Lag() ConsumerLag
+
 	// Messages returns the read channel for the messages that are returned by
 	// the broker. The messages channel will be closed when a new rebalance cycle
 	// is due. You must finish processing and mark offsets within
@@ -1351,15 +1630,35 @@ type consumerGroupClaim struct {
 	topic     string
 	partition int32
 	offset    int64
+	batches   chan []*ConsumerMessage // only set for a BatchConsumerGroupHandler
+	seeks     chan func()             // run by the goroutine grouping the batches
+	batchDone chan none               // closed once that goroutine exits
 	PartitionConsumer
 }
 
+// consumePartition starts consuming a claimed partition, in batches when the
+// handler of the session asks for them and tracking the delivered messages
+// when they can be acknowledged.
+func (s *consumerGroupSession) consumePartition(topic string, partition int32, offset int64) (PartitionConsumer, error) {
+	c, ok := s.parent.consumer.(*consumer)
+	if !ok {
+		return s.parent.consumer.ConsumePartition(topic, partition, offset)
+	}
+
+	var opts partitionConsumerOptions
+	_, opts.batched = s.handler.(BatchConsumerGroupHandler)
+	if pom := s.offsets.findPOM(topic, partition); pom != nil {
+		opts.acks = pom.acks
+	}
+	return c.consumePartition(topic, partition, offset, opts)
+}
+
 func newConsumerGroupClaim(sess *consumerGroupSession, topic string, partition int32, offset int64) (*consumerGroupClaim, error) {
-	pcm, err := sess.parent.consumer.ConsumePartition(topic, partition, offset)
+	pcm, err := sess.consumePartition(topic, partition, offset)
 
 	if errors.Is(err, ErrOffsetOutOfRange) && sess.parent.config.Consumer.Group.ResetInvalidOffsets {
 		offset = sess.parent.config.Consumer.Offsets.Initial
-		pcm, err = sess.parent.consumer.ConsumePartition(topic, partition, offset)
+		pcm, err = sess.consumePartition(topic, partition, offset)
 	}
 	if err != nil {
 		return nil, err
@@ -1371,12 +1670,52 @@ func newConsumerGroupClaim(sess *consumerGroupSession, topic string, partition i
 		}
 	}()
 
-	return &consumerGroupClaim{
+	claim := &consumerGroupClaim{
 		topic:             topic,
 		partition:         partition,
 		offset:            offset,
 		PartitionConsumer: pcm,
-	}, nil
+	}
+	if pc, ok := pcm.(*partitionConsumer); ok && pc.batches != nil {
+		claim.batches = make(chan []*ConsumerMessage)
+		claim.seeks = make(chan func())
+		claim.batchDone = make(chan none)
+		go func() {
+			defer close(claim.batchDone)
+			batchMessages(pc.batches, claim.batches, claim.seeks, sess.parent.config)
+		}()
+	}
+	return claim, nil
+}
+
+// Seek implements PartitionConsumer.
+func (c *consumerGroupClaim) Seek(offset int64) error {
+	return c.seek(func() error {
+		return c.PartitionConsumer.Seek(offset)
+	})
+}
+
+// SeekToTimestamp implements PartitionConsumer.
+func (c *consumerGroupClaim) SeekToTimestamp(t time.Time) error {
+	return c.seek(func() error {
+		return c.PartitionConsumer.SeekToTimestamp(t)
+	})
+}
+
+// seek runs a seek of the PartitionConsumer. When the messages are grouped
+// into batches, it is run by the goroutine grouping them so that the batch
+// being grouped is discarded too.
+func (c *consumerGroupClaim) seek(fn func() error) error {
+	if c.seeks == nil {
+		return fn()
+	}
+	errs := make(chan error, 1)
+	select {
+	case c.seeks <- func() { errs <- fn() }:
+		return <-errs
+	case <-c.batchDone:
+		return fn()
+	}
 }
 
 func (c *consumerGroupClaim) Topic() string        { return c.topic }
@@ -1389,6 +1728,12 @@ func (c *consumerGroupClaim) waitClosed() (errs ConsumerErrors) {
 		for range c.Messages() {
 		}
 	}()
+	if c.batches != nil {
+		go func() {
+			for range c.batches {
+			}
+		}()
+	}
 
 	for err := range c.Errors() {
 		errs = append(errs, err)
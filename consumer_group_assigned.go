@@ -0,0 +1,512 @@
+package sarama
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// This file implements the consumer group protocol from KIP-848, selected with
+// Consumer.Group.Protocol = GroupProtocolConsumer. Instead of the JoinGroup /
+// SyncGroup barrier, every member heartbeats with ConsumerGroupHeartbeat and the
+// group coordinator hands each member its own assignment, revoking and
+// assigning partitions incrementally. When the coordinator sends a new
+// assignment, the session goes on: the claims of the revoked partitions are
+// stopped and their offsets committed before the member stops reporting them
+// as owned, the newly assigned partitions start being consumed, and the claims
+// of the other partitions are left running.
+
+const (
+	// memberEpochLeave is the member epoch sent by a dynamic member leaving the group
+	memberEpochLeave int32 = -1
+	// memberEpochLeaveStatic is the member epoch sent by a static member that
+	// leaves temporarily and intends to rejoin with the same instance id
+	memberEpochLeaveStatic int32 = -2
+)
+
+func (c *consumerGroup) newAssignedSession(ctx context.Context, topics []string, handler ConsumerGroupHandler, retries int) (*consumerGroupSession, error) {
+	claims, err := c.joinAssigned(ctx, topics, retries)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConsumerGroupSession(ctx, c, claims, c.memberID, c.memberEpoch, handler)
+}
+
+// joinAssigned heartbeats until the member has an assignment from the group
+// coordinator and returns it, joining the group first if necessary
+func (c *consumerGroup) joinAssigned(ctx context.Context, topics []string, retries int) (map[string][]int32, error) {
+	c.subscription = topics
+
+	for c.memberEpoch == 0 || c.assignment == nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, ErrClosedConsumerGroup
+		default:
+		}
+
+		coordinator, err := c.client.Coordinator(c.groupID)
+		if err == nil {
+			err = c.topicIDs.resolve(coordinator, c.config.Version, topics)
+		}
+		if err != nil {
+			if retries <= 0 {
+				return nil, err
+			}
+			retries--
+			if err := c.backoffAssigned(ctx, true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		resp, err := c.consumerGroupHeartbeatRequest(coordinator, c.memberEpoch, nil)
+		if err != nil {
+			_ = coordinator.Close()
+			if retries <= 0 {
+				return nil, err
+			}
+			retries--
+			if err := c.backoffAssigned(ctx, false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch resp.Err {
+		case ErrNoError:
+			if err := c.updateMembership(coordinator, resp); err != nil {
+				return nil, err
+			}
+			if c.assignment != nil {
+				break
+			}
+			// joined but the coordinator has not computed an assignment yet
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-c.closed:
+				return nil, ErrClosedConsumerGroup
+			case <-time.After(c.heartbeatInterval):
+			}
+		case ErrUnknownMemberId, ErrFencedMemberEpoch:
+			// rejoin from scratch
+			c.resetMembership(resp.Err)
+			if retries <= 0 {
+				return nil, resp.Err
+			}
+			retries--
+		case ErrNotCoordinatorForConsumer, ErrConsumerCoordinatorNotAvailable, ErrOffsetsLoadInProgress:
+			if retries <= 0 {
+				return nil, resp.Err
+			}
+			retries--
+			if err := c.backoffAssigned(ctx, true); err != nil {
+				return nil, err
+			}
+		case ErrUnreleasedInstanceID:
+			if c.groupInstanceId != nil {
+				Logger.Printf("ConsumerGroupHeartbeat failed: group instance id %s is still in use\n", *c.groupInstanceId)
+			}
+			return nil, resp.Err
+		default:
+			return nil, resp.Err
+		}
+	}
+
+	return c.assignment, nil
+}
+
+func (c *consumerGroup) backoffAssigned(ctx context.Context, refreshCoordinator bool) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closed:
+		return ErrClosedConsumerGroup
+	case <-time.After(c.config.Consumer.Group.Rebalance.Retry.Backoff):
+	}
+
+	if refreshCoordinator {
+		_ = c.client.RefreshCoordinator(c.groupID)
+	}
+	return nil
+}
+
+// consumerGroupHeartbeatRequest sends the full member state to the coordinator,
+// reporting the given claims as the partitions currently owned by the member
+func (c *consumerGroup) consumerGroupHeartbeatRequest(coordinator *Broker, memberEpoch int32, owned map[string][]int32) (*ConsumerGroupHeartbeatResponse, error) {
+	req := NewConsumerGroupHeartbeatRequest(c.config.Version)
+	// from version 1 onwards (KIP-1082) the member id is generated by the
+	// client and kept for the whole lifetime of the consumer group
+	if c.memberID == "" && req.Version >= 1 {
+		c.memberID = newMemberID()
+	}
+	req.GroupID = c.groupID
+	req.MemberID = c.memberID
+	req.MemberEpoch = memberEpoch
+	req.InstanceID = c.groupInstanceId
+	if c.config.RackID != "" {
+		req.RackID = &c.config.RackID
+	}
+	req.RebalanceTimeoutMs = int32(c.config.Consumer.Group.Rebalance.Timeout / time.Millisecond)
+	req.SubscribedTopicNames = c.subscription
+	if assignor := c.config.Consumer.Group.ServerAssignor; assignor != "" {
+		req.ServerAssignor = &assignor
+	}
+	req.TopicPartitions = c.ownedTopicPartitions(owned)
+
+	return coordinator.ConsumerGroupHeartbeat(req)
+}
+
+// updateMembership records the member state returned by a successful heartbeat
+func (c *consumerGroup) updateMembership(coordinator *Broker, resp *ConsumerGroupHeartbeatResponse) error {
+	if resp.MemberID != nil && *resp.MemberID != "" {
+		c.memberID = *resp.MemberID
+	}
+	c.memberEpoch = resp.MemberEpoch
+	if resp.HeartbeatIntervalMs > 0 {
+		c.heartbeatInterval = time.Duration(resp.HeartbeatIntervalMs) * time.Millisecond
+	}
+	if resp.Assignment != nil {
+		claims, err := c.topicIDs.claims(coordinator, c.config.Version, c.subscription, resp.Assignment.TopicPartitions)
+		if err != nil {
+			return err
+		}
+		c.assignment = claims
+	}
+	return nil
+}
+
+// resetMembership forgets the member epoch and assignment after the member
+// was fenced by the coordinator, so that the next heartbeat rejoins the group
+func (c *consumerGroup) resetMembership(err error) {
+	if len(c.assignment) > 0 {
+		Logger.Printf("consumergroup/%s: lost ownership of %v due to %v\n", c.groupID, c.assignment, err)
+	}
+	c.memberEpoch = 0
+	c.assignment = nil
+}
+
+// topicIDCache maps topic names to the topic ids used by the consumer group
+// protocol and the share group protocol, which identify topics by id rather
+// than by name
+type topicIDCache struct {
+	ids   map[string]Uuid
+	names map[Uuid]string
+}
+
+func newTopicIDCache() *topicIDCache {
+	return &topicIDCache{
+		ids:   make(map[string]Uuid),
+		names: make(map[Uuid]string),
+	}
+}
+
+// resolve fetches the topic ids of the given topics that are not cached yet
+func (t *topicIDCache) resolve(broker *Broker, version KafkaVersion, topics []string) error {
+	var missing []string
+	for _, topic := range topics {
+		if _, ok := t.ids[topic]; !ok {
+			missing = append(missing, topic)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	resp, err := broker.GetMetadata(NewMetadataRequest(version, missing))
+	if err != nil {
+		_ = broker.Close()
+		return err
+	}
+	for _, topic := range resp.Topics {
+		if !errors.Is(topic.Err, ErrNoError) || topic.Uuid == (Uuid{}) {
+			continue
+		}
+		t.ids[topic.Name] = topic.Uuid
+		t.names[topic.Uuid] = topic.Name
+	}
+	return nil
+}
+
+// claims translates the topic ids of an assignment into topic names
+func (t *topicIDCache) claims(broker *Broker, version KafkaVersion, subscription []string, assigned []ConsumerGroupHeartbeatTopicPartitions) (map[string][]int32, error) {
+	claims := make(map[string][]int32, len(assigned))
+	for _, tp := range assigned {
+		topic, ok := t.names[tp.TopicID]
+		if !ok {
+			// the topic may have been recreated, look the subscription up again
+			for name, id := range t.ids {
+				if slices.Contains(subscription, name) {
+					delete(t.names, id)
+					delete(t.ids, name)
+				}
+			}
+			if err := t.resolve(broker, version, subscription); err != nil {
+				return nil, err
+			}
+			if topic, ok = t.names[tp.TopicID]; !ok {
+				return nil, fmt.Errorf("kafka: assignment contains unknown topic id %s", tp.TopicID)
+			}
+		}
+		claims[topic] = append(claims[topic], tp.Partitions...)
+	}
+	for _, partitions := range claims {
+		sort.Sort(int32Slice(partitions))
+	}
+	return claims, nil
+}
+
+func (c *consumerGroup) ownedTopicPartitions(owned map[string][]int32) []ConsumerGroupHeartbeatTopicPartitions {
+	topics := make([]ConsumerGroupHeartbeatTopicPartitions, 0, len(owned))
+	for _, topic := range slices.Sorted(maps.Keys(owned)) {
+		id, ok := c.topicIDs.ids[topic]
+		if !ok || len(owned[topic]) == 0 {
+			continue
+		}
+		topics = append(topics, ConsumerGroupHeartbeatTopicPartitions{
+			TopicID:    id,
+			Partitions: owned[topic],
+		})
+	}
+	return topics
+}
+
+// leaveAssigned leaves the group by heartbeating with a leave member epoch,
+// called by leave with the consumer group lock held
+func (c *consumerGroup) leaveAssigned(coordinator *Broker) error {
+	if c.memberEpoch == 0 {
+		return nil
+	}
+
+	epoch := memberEpochLeave
+	if c.groupInstanceId != nil {
+		epoch = memberEpochLeaveStatic
+	}
+	req := NewConsumerGroupHeartbeatRequest(c.config.Version)
+	req.GroupID = c.groupID
+	req.MemberID = c.memberID
+	req.MemberEpoch = epoch
+	req.InstanceID = c.groupInstanceId
+	req.RebalanceTimeoutMs = -1
+
+	resp, err := coordinator.ConsumerGroupHeartbeat(req)
+	if err != nil {
+		_ = coordinator.Close()
+		return err
+	}
+
+	// clear the membership, the member id is owned by the client and kept
+	c.memberEpoch = 0
+	c.assignment = nil
+
+	switch resp.Err {
+	case ErrNoError, ErrUnknownMemberId, ErrFencedMemberEpoch:
+		return nil
+	default:
+		return resp.Err
+	}
+}
+
+// assignedHeartbeatLoop keeps the membership of the session alive using the
+// consumer group protocol, reporting the claims of the session and applying
+// the assignments handed out by the coordinator
+func (s *consumerGroupSession) assignedHeartbeatLoop() {
+	defer close(s.hbDead)
+	defer s.cancel(ErrSessionHeartbeatFailed) // trigger the end of the session on exit
+	defer func() {
+		Logger.Printf(
+			"consumergroup/session/%s/%d heartbeat loop stopped\n",
+			s.MemberID(), s.GenerationID())
+	}()
+
+	parent := s.parent
+
+	// heartbeat straight away to acknowledge the claims of this session
+	pause := time.NewTimer(0)
+	defer pause.Stop()
+
+	retries := parent.config.Metadata.Retry.Max
+	for {
+		select {
+		case <-pause.C:
+		case <-s.hbDying:
+			return
+		}
+		pause.Reset(parent.heartbeatInterval)
+
+		coordinator, err := parent.client.Coordinator(parent.groupID)
+		if err != nil {
+			if retries <= 0 {
+				parent.handleError(err, "", -1)
+				s.cancel(err)
+				return
+			}
+			retries--
+			pause.Reset(parent.config.Metadata.Retry.Backoff)
+			continue
+		}
+
+		resp, err := parent.consumerGroupHeartbeatRequest(coordinator, parent.memberEpoch, s.Claims())
+		if err != nil {
+			_ = coordinator.Close()
+
+			if retries <= 0 {
+				parent.handleError(err, "", -1)
+				s.cancel(err)
+				return
+			}
+
+			retries--
+			continue
+		}
+
+		switch err := resp.Err; err {
+		case ErrNoError:
+			retries = parent.config.Metadata.Retry.Max
+			if err := parent.updateMembership(coordinator, resp); err != nil {
+				parent.handleError(err, "", -1)
+				s.cancel(err)
+				return
+			}
+			s.offsets.generation.Store(parent.memberEpoch)
+			if err := s.reconcileClaims(parent.assignment); err != nil {
+				parent.handleError(err, "", -1)
+				s.cancel(err)
+				return
+			}
+		case ErrNotCoordinatorForConsumer, ErrConsumerCoordinatorNotAvailable, ErrOffsetsLoadInProgress:
+			if retries <= 0 {
+				parent.handleError(err, "", -1)
+				s.cancel(err)
+				return
+			}
+			retries--
+			_ = parent.client.RefreshCoordinator(parent.groupID)
+			pause.Reset(parent.config.Metadata.Retry.Backoff)
+		case ErrUnknownMemberId, ErrFencedMemberEpoch:
+			parent.resetMembership(err)
+			s.cancel(err)
+			return
+		default:
+			parent.handleError(err, "", -1)
+			s.cancel(err)
+			return
+		}
+	}
+}
+
+// reconcileClaims applies the assignment of the member to the session once its
+// claims are being consumed. The claims of the partitions no longer assigned
+// are stopped, then committed and released in the background, while the
+// partitions newly assigned start being consumed.
+func (s *consumerGroupSession) reconcileClaims(assignment map[string][]int32) error {
+	s.claimsLock.Lock()
+	if s.releasing || s.running == nil {
+		s.claimsLock.Unlock()
+		return nil
+	}
+
+	added := make(map[string][]int32)
+	for topic, partitions := range assignment {
+		for _, partition := range partitions {
+			if !slices.Contains(s.claims[topic], partition) {
+				added[topic] = append(added[topic], partition)
+			}
+		}
+	}
+
+	revoked := make(map[string][]int32)
+	var stopped []*sessionClaim
+	for topic, partitions := range s.claims {
+		for _, partition := range partitions {
+			// a claim no longer running is already being revoked
+			claim := s.running[topic][partition]
+			if claim == nil || slices.Contains(assignment[topic], partition) {
+				continue
+			}
+			close(claim.stop)
+			delete(s.running[topic], partition)
+			revoked[topic] = append(revoked[topic], partition)
+			stopped = append(stopped, claim)
+		}
+	}
+	if len(stopped) > 0 {
+		s.waitGroup.Add(1)
+		go s.revokeClaims(revoked, stopped)
+	}
+	s.claimsLock.Unlock()
+
+	if len(added) == 0 {
+		return nil
+	}
+	for topic, partitions := range added {
+		for _, partition := range partitions {
+			if err := s.managePartition(topic, partition); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.claimsLock.Lock()
+	started := s.addRunning(added)
+	if started != nil {
+		for topic, partitions := range added {
+			claimed := slices.Concat(s.claims[topic], partitions)
+			sort.Sort(int32Slice(claimed))
+			s.claims[topic] = claimed
+		}
+	}
+	s.claimsLock.Unlock()
+	if started == nil {
+		// the session is being released along with the new POMs
+		return nil
+	}
+
+	s.partitionsAssigned(added)
+	for _, start := range started {
+		go start()
+	}
+	return nil
+}
+
+// revokeClaims waits for the stopped claims of the revoked partitions to be
+// done, commits their offsets and removes them from the claims of the session.
+func (s *consumerGroupSession) revokeClaims(revoked map[string][]int32, stopped []*sessionClaim) {
+	defer s.waitGroup.Done()
+
+	for _, claim := range stopped {
+		<-claim.done
+	}
+	s.offsets.releasePartitions(revoked)
+
+	s.claimsLock.Lock()
+	for topic, partitions := range revoked {
+		remaining := slices.DeleteFunc(slices.Clone(s.claims[topic]), func(partition int32) bool {
+			return slices.Contains(partitions, partition)
+		})
+		if len(remaining) == 0 {
+			delete(s.claims, topic)
+		} else {
+			s.claims[topic] = remaining
+		}
+	}
+	s.claimsLock.Unlock()
+
+	s.partitionsRevoked(revoked)
+}
+
+// newMemberID returns a random member id in the same format as the topic ids
+// generated by Kafka
+func newMemberID() string {
+	var id Uuid
+	_, _ = rand.Read(id[:])
+	return id.String()
+}
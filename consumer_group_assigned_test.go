@@ -0,0 +1,286 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// mockGroupCoordinator simulates a group coordinator using the consumer group
+// protocol: it assigns partitions 0 and 1 of my-topic on join, revokes
+// partition 1 once the member has acknowledged them and assigns partition 2
+// once the member no longer owns partition 1.
+type mockGroupCoordinator struct {
+	topicID  Uuid
+	mu       sync.Mutex
+	requests []*ConsumerGroupHeartbeatRequest
+}
+
+func (m *mockGroupCoordinator) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*ConsumerGroupHeartbeatRequest)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, req)
+
+	res := &ConsumerGroupHeartbeatResponse{
+		Version:             req.Version,
+		MemberID:            &req.MemberID,
+		HeartbeatIntervalMs: 10,
+	}
+	switch {
+	case req.MemberEpoch < 0:
+		res.MemberEpoch = req.MemberEpoch
+	case req.MemberEpoch == 0:
+		res.MemberEpoch = 1
+		res.Assignment = m.assignment(0, 1)
+	case req.MemberEpoch == 1 && reportsOwned(req, 0, 1):
+		res.MemberEpoch = 2
+		res.Assignment = m.assignment(0)
+	case req.MemberEpoch == 2 && reportsOwned(req, 0):
+		res.MemberEpoch = 3
+		res.Assignment = m.assignment(0, 2)
+	default:
+		res.MemberEpoch = req.MemberEpoch
+	}
+	return res
+}
+
+func (m *mockGroupCoordinator) assignment(partitions ...int32) *ConsumerGroupHeartbeatAssignment {
+	return &ConsumerGroupHeartbeatAssignment{
+		TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{
+			{TopicID: m.topicID, Partitions: partitions},
+		},
+	}
+}
+
+// reportsOwned reports whether a heartbeat reports exactly the given
+// partitions of a single topic as owned.
+func reportsOwned(req *ConsumerGroupHeartbeatRequest, partitions ...int32) bool {
+	return len(req.TopicPartitions) == 1 && slices.Equal(req.TopicPartitions[0].Partitions, partitions)
+}
+
+// incrementalHandler is a ConsumerGroupHandler and RebalanceListener that
+// records the claims started and ended during a session, marking the first
+// offset of every claim.
+type incrementalHandler struct {
+	listenerHandler
+
+	started, ended map[int32]int
+}
+
+func (h *incrementalHandler) ConsumeClaim(sess ConsumerGroupSession, claim ConsumerGroupClaim) error {
+	h.claimed(h.started, claim.Partition())
+	sess.MarkOffset(claim.Topic(), claim.Partition(), 1, "")
+	for range claim.Messages() {
+	}
+	h.claimed(h.ended, claim.Partition())
+	return nil
+}
+
+func (h *incrementalHandler) claimed(counts map[int32]int, partition int32) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	counts[partition]++
+}
+
+func (h *incrementalHandler) counts() (started, ended map[int32]int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return maps.Clone(h.started), maps.Clone(h.ended)
+}
+
+// claimsHandler is a ConsumerGroupHandler that reports the claims of each
+// session and the cause of its end.
+type claimsHandler struct {
+	claimsCh chan map[string][]int32
+	causeCh  chan error
+}
+
+func (h *claimsHandler) Setup(sess ConsumerGroupSession) error {
+	h.claimsCh <- sess.Claims()
+	return nil
+}
+
+func (h *claimsHandler) Cleanup(sess ConsumerGroupSession) error {
+	h.causeCh <- context.Cause(sess.Context())
+	return nil
+}
+
+func (h *claimsHandler) ConsumeClaim(sess ConsumerGroupSession, claim ConsumerGroupClaim) error {
+	<-sess.Context().Done()
+	return nil
+}
+
+func TestConsumerGroupConsumerProtocol(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V4_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Group.Protocol = GroupProtocolConsumer
+	config.Consumer.Group.Rebalance.Retry.Backoff = 0
+	config.Consumer.Offsets.AutoCommit.Interval = time.Hour
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	topicID := Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	coordinator := &mockGroupCoordinator{topicID: topicID}
+	metadata := NewMockMetadataResponse(t).
+		SetBroker(broker0.Addr(), broker0.BrokerID()).
+		SetTopicID("my-topic", topicID)
+	offsets := NewMockOffsetResponse(t)
+	offsetFetch := NewMockOffsetFetchResponse(t).SetError(ErrNoError)
+	for partition := range int32(3) {
+		metadata.SetLeader("my-topic", partition, broker0.BrokerID())
+		offsets.SetOffset("my-topic", partition, OffsetOldest, 0).
+			SetOffset("my-topic", partition, OffsetNewest, 1)
+		offsetFetch.SetOffset("my-group", "my-topic", partition, 0, "", ErrNoError)
+	}
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": metadata,
+		"OffsetRequest":   offsets,
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorGroup, "my-group", broker0),
+		"ConsumerGroupHeartbeatRequest": coordinator,
+		"OffsetFetchRequest":            offsetFetch,
+		"OffsetCommitRequest":           NewMockOffsetCommitResponse(t),
+		"FetchRequest":                  NewMockFetchResponse(t, 1),
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+
+	h := &incrementalHandler{started: make(map[int32]int), ended: make(map[int32]int)}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- group.Consume(ctx, []string{"my-topic"}, h) }()
+
+	// partition 1 is revoked and partition 2 assigned within the session,
+	// without restarting the claim of partition 0
+	assert.Eventually(t, func() bool {
+		started, ended := h.counts()
+		return maps.Equal(started, map[int32]int{0: 1, 1: 1, 2: 1}) &&
+			maps.Equal(ended, map[int32]int{1: 1})
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		coordinator.mu.Lock()
+		defer coordinator.mu.Unlock()
+		last := coordinator.requests[len(coordinator.requests)-1]
+		return last.MemberEpoch == 3 && reportsOwned(last, 0, 2)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the offset of the revoked partition is committed before the member
+	// stops reporting it as owned
+	var committed, dropped int
+	for i, rr := range broker0.History() {
+		switch req := rr.Request.(type) {
+		case *OffsetCommitRequest:
+			if committed == 0 && req.blocks["my-topic"][1] != nil {
+				committed = i
+			}
+		case *ConsumerGroupHeartbeatRequest:
+			if dropped == 0 && req.MemberEpoch == 2 && reportsOwned(req, 0) {
+				dropped = i
+			}
+		}
+	}
+	assert.NotZero(t, committed)
+	assert.Less(t, committed, dropped)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.NoError(t, group.Close())
+
+	assert.Equal(t, []rebalanceEvent{
+		{"setup", nil},
+		{"assigned", map[string][]int32{"my-topic": {0, 1}}},
+		{"revoked", map[string][]int32{"my-topic": {1}}},
+		{"assigned", map[string][]int32{"my-topic": {2}}},
+		{"cleanup", nil},
+		{"revoked", map[string][]int32{"my-topic": {0, 2}}},
+	}, h.recorded())
+
+	coordinator.mu.Lock()
+	defer coordinator.mu.Unlock()
+	first := coordinator.requests[0]
+	assert.Equal(t, "my-group", first.GroupID)
+	assert.NotEmpty(t, first.MemberID)
+	assert.Equal(t, int32(0), first.MemberEpoch)
+	assert.Equal(t, []string{"my-topic"}, first.SubscribedTopicNames)
+	last := coordinator.requests[len(coordinator.requests)-1]
+	assert.Equal(t, first.MemberID, last.MemberID)
+	assert.Equal(t, memberEpochLeave, last.MemberEpoch, "Close should leave the group")
+}
+
+func TestConsumerGroupConsumerProtocolFenced(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V4_0_0_0
+	config.Consumer.Group.Protocol = GroupProtocolConsumer
+	config.Consumer.Offsets.AutoCommit.Enable = false
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	topicID := Uuid{1}
+	var mu sync.Mutex
+	var epochs []int32
+	broker0.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": func(req *request) encoderWithHeader {
+			return NewMockMetadataResponse(t).
+				SetBroker(broker0.Addr(), broker0.BrokerID()).
+				SetLeader("my-topic", 0, broker0.BrokerID()).
+				SetTopicID("my-topic", topicID).
+				For(req.body)
+		},
+		"FindCoordinatorRequest": func(req *request) encoderWithHeader {
+			return NewMockFindCoordinatorResponse(t).
+				SetCoordinator(CoordinatorGroup, "my-group", broker0).
+				For(req.body)
+		},
+		"ConsumerGroupHeartbeatRequest": func(req *request) encoderWithHeader {
+			hb := req.body.(*ConsumerGroupHeartbeatRequest)
+			mu.Lock()
+			epochs = append(epochs, hb.MemberEpoch)
+			mu.Unlock()
+			res := &ConsumerGroupHeartbeatResponse{Version: hb.Version, HeartbeatIntervalMs: 10}
+			if hb.MemberEpoch == 0 {
+				res.MemberEpoch = 5
+				res.Assignment = &ConsumerGroupHeartbeatAssignment{
+					TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{},
+				}
+			} else {
+				res.Err = ErrFencedMemberEpoch
+			}
+			return res
+		},
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	h := &claimsHandler{
+		claimsCh: make(chan map[string][]int32, 2),
+		causeCh:  make(chan error, 2),
+	}
+	for range 2 {
+		assert.NoError(t, group.Consume(t.Context(), []string{"my-topic"}, h))
+		assert.Empty(t, <-h.claimsCh)
+		assert.ErrorIs(t, <-h.causeCh, ErrFencedMemberEpoch)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int32{0, 5, 0, 5}, epochs, "a fenced member should rejoin with epoch 0")
+}
@@ -0,0 +1,116 @@
+package sarama
+
+import "time"
+
+// BatchConsumerGroupHandler is an optional interface for a ConsumerGroupHandler
+// that processes messages in batches rather than one at a time. When the handler
+// passed to ConsumerGroup.Consume implements it, ConsumeClaimBatch is called for
+// each claim instead of ConsumeClaim.
+//
+// The messages of each fetch response are handed over at once by the partition
+// consumer and grouped into batches bounded by Config.Consumer.Batch.
+type BatchConsumerGroupHandler interface {
+	ConsumerGroupHandler
+
+	// ConsumeClaimBatch must start a consumer loop of the batches channel, in
+	// place of the claim's Messages() which is not fed. Every batch holds at
+	// least one message, in offset order. Once the batches channel is closed,
+	// the handler must finish its processing loop and exit. As with ConsumeClaim,
+	// handlers should also return when ConsumerGroupSession.Context() is done.
+	ConsumeClaimBatch(session ConsumerGroupSession, claim ConsumerGroupClaim, batches <-chan []*ConsumerMessage) error
+}
+
+// consumeClaim runs the handler's consumer loop for the claim.
+func (s *consumerGroupSession) consumeClaim(claim *consumerGroupClaim) error {
+	if handler, ok := s.handler.(BatchConsumerGroupHandler); ok && claim.batches != nil {
+		return handler.ConsumeClaimBatch(s, claim, claim.batches)
+	}
+	return s.handler.ConsumeClaim(s, claim)
+}
+
+// batchMessages groups the messages of the fetch responses received on in
+// into batches bounded by Consumer.Batch and sends them on out, which is
+// closed once in is closed and the last batch has been delivered. The seeks
+// of the partition consumer received on seeks are run in between, and discard
+// the batch being grouped along with the messages the partition consumer
+// flushes.
+func batchMessages(in <-chan []*ConsumerMessage, out chan<- []*ConsumerMessage, seeks <-chan func(), conf *Config) {
+	defer close(out)
+
+	var (
+		batch  []*ConsumerMessage
+		size   int
+		timer  *time.Timer
+		linger <-chan time.Time
+	)
+	discard := func() {
+		batch, size = nil, 0
+		if timer != nil {
+			timer.Stop()
+			linger = nil
+		}
+	}
+	// flush reports false if the batch was discarded by a seek instead
+	flush := func() bool {
+		defer discard()
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case out <- batch:
+			return true
+		case seek := <-seeks:
+			seek()
+			return false
+		}
+	}
+
+	for {
+		select {
+		case msgs, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+		msgLoop:
+			for _, msg := range msgs {
+				msgSize := consumerMessageSize(msg)
+				if len(batch) > 0 && size+msgSize > conf.Consumer.Batch.MaxBytes && !flush() {
+					break msgLoop
+				}
+				batch = append(batch, msg)
+				size += msgSize
+				if len(batch) >= conf.Consumer.Batch.MaxMessages && !flush() {
+					break msgLoop
+				}
+			}
+			switch {
+			case len(batch) == 0 || linger != nil:
+			case conf.Consumer.Batch.Linger == 0:
+				flush()
+			case timer == nil:
+				timer = time.NewTimer(conf.Consumer.Batch.Linger)
+				linger = timer.C
+			default:
+				timer.Reset(conf.Consumer.Batch.Linger)
+				linger = timer.C
+			}
+		case <-linger:
+			linger = nil
+			flush()
+		case seek := <-seeks:
+			seek()
+			discard()
+		}
+	}
+}
+
+// consumerMessageSize returns the number of bytes of the keys, values and
+// headers of a message.
+func consumerMessageSize(msg *ConsumerMessage) int {
+	size := len(msg.Key) + len(msg.Value)
+	for _, header := range msg.Headers {
+		size += len(header.Key) + len(header.Value)
+	}
+	return size
+}
@@ -0,0 +1,195 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchMessages(t *testing.T) {
+	message := func(offset int64, value string) *ConsumerMessage {
+		return &ConsumerMessage{Offset: offset, Value: []byte(value)}
+	}
+	offsets := func(batch []*ConsumerMessage) []int64 {
+		var result []int64
+		for _, msg := range batch {
+			result = append(result, msg.Offset)
+		}
+		return result
+	}
+
+	t.Run("bounded by count and bytes", func(t *testing.T) {
+		conf := NewTestConfig()
+		conf.Consumer.Batch.MaxMessages = 3
+		conf.Consumer.Batch.MaxBytes = 10
+		conf.Consumer.Batch.Linger = time.Hour
+
+		in := make(chan []*ConsumerMessage, 2)
+		out := make(chan []*ConsumerMessage, 10)
+		in <- []*ConsumerMessage{message(0, "a"), message(1, "b"), message(2, "c"), message(3, "d")}
+		in <- []*ConsumerMessage{message(4, "0123456789"), message(5, "e")}
+		close(in)
+		batchMessages(in, out, nil, conf)
+
+		var batches [][]int64
+		for batch := range out {
+			batches = append(batches, offsets(batch))
+		}
+		require.Equal(t, [][]int64{{0, 1, 2}, {3}, {4}, {5}}, batches)
+	})
+
+	t.Run("delivered after linger", func(t *testing.T) {
+		conf := NewTestConfig()
+		conf.Consumer.Batch.Linger = 20 * time.Millisecond
+
+		in := make(chan []*ConsumerMessage)
+		out := make(chan []*ConsumerMessage)
+		go batchMessages(in, out, nil, conf)
+
+		in <- []*ConsumerMessage{message(0, "a")}
+		in <- []*ConsumerMessage{message(1, "b")}
+		select {
+		case batch := <-out:
+			require.Equal(t, []int64{0, 1}, offsets(batch))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the batch was not delivered once the linger time elapsed")
+		}
+
+		close(in)
+		_, ok := <-out
+		require.False(t, ok)
+	})
+
+	t.Run("delivered straight away without linger", func(t *testing.T) {
+		conf := NewTestConfig()
+		conf.Consumer.Batch.Linger = 0
+
+		in := make(chan []*ConsumerMessage)
+		out := make(chan []*ConsumerMessage)
+		go batchMessages(in, out, nil, conf)
+		defer close(in)
+
+		in <- []*ConsumerMessage{message(0, "a"), message(1, "b")}
+		require.Equal(t, []int64{0, 1}, offsets(<-out))
+	})
+
+	t.Run("discarded by a seek", func(t *testing.T) {
+		conf := NewTestConfig()
+		conf.Consumer.Batch.MaxMessages = 2
+		conf.Consumer.Batch.Linger = time.Hour
+
+		in := make(chan []*ConsumerMessage)
+		out := make(chan []*ConsumerMessage)
+		seeks := make(chan func())
+		go batchMessages(in, out, seeks, conf)
+
+		// the first batch is waiting to be delivered, the second one to be
+		// filled
+		in <- []*ConsumerMessage{message(0, "a"), message(1, "b"), message(2, "c")}
+		seeked := make(chan none)
+		seeks <- func() { close(seeked) }
+		<-seeked
+
+		in <- []*ConsumerMessage{message(10, "d")}
+		close(in)
+		var batches [][]int64
+		for batch := range out {
+			batches = append(batches, offsets(batch))
+		}
+		require.Equal(t, [][]int64{{10}}, batches)
+	})
+}
+
+type batchHandler struct {
+	batches chan []*ConsumerMessage
+}
+
+func (h *batchHandler) Setup(ConsumerGroupSession) error   { return nil }
+func (h *batchHandler) Cleanup(ConsumerGroupSession) error { return nil }
+func (h *batchHandler) ConsumeClaim(ConsumerGroupSession, ConsumerGroupClaim) error {
+	panic("ConsumeClaim should not be called for a BatchConsumerGroupHandler")
+}
+
+func (h *batchHandler) ConsumeClaimBatch(sess ConsumerGroupSession, _ ConsumerGroupClaim, batches <-chan []*ConsumerMessage) error {
+	for {
+		select {
+		case batch, ok := <-batches:
+			if !ok {
+				return nil
+			}
+			sess.MarkMessage(batch[len(batch)-1], "")
+			h.batches <- batch
+		case <-sess.Context().Done():
+			return nil
+		}
+	}
+}
+
+func TestConsumerGroupConsumeBatches(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V2_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Group.Rebalance.Retry.Max = 0
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Batch.MaxMessages = 2
+	config.Consumer.Batch.Linger = 0
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my-topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my-topic", 0, OffsetOldest, 0).
+			SetOffset("my-topic", 0, OffsetNewest, 3),
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorGroup, "my-group", broker0),
+		"HeartbeatRequest": NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": NewMockJoinGroupResponse(t).SetGroupProtocol(RangeBalanceStrategyName),
+		"SyncGroupRequest": NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{"my-topic": {0}},
+			}),
+		"OffsetFetchRequest": NewMockOffsetFetchResponse(t).SetOffset(
+			"my-group", "my-topic", 0, 0, "", ErrNoError,
+		).SetError(ErrNoError),
+		"FetchRequest": NewMockFetchResponse(t, 3).
+			SetMessage("my-topic", 0, 0, StringEncoder("foo")).
+			SetMessage("my-topic", 0, 1, StringEncoder("bar")).
+			SetMessage("my-topic", 0, 2, StringEncoder("baz")),
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	require.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	h := &batchHandler{batches: make(chan []*ConsumerMessage, 2)}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- group.Consume(ctx, []string{"my-topic"}, h)
+	}()
+
+	var values [][]string
+	for range 2 {
+		var batch []string
+		for _, msg := range <-h.batches {
+			batch = append(batch, string(msg.Value))
+		}
+		values = append(values, batch)
+	}
+	require.Equal(t, [][]string{{"foo", "bar"}, {"baz"}}, values)
+
+	cancel()
+	require.NoError(t, <-done)
+}
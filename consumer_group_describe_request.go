@@ -0,0 +1,89 @@
+package sarama
+
+// ConsumerGroupDescribeRequest describes consumer groups using the consumer
+// rebalance protocol (KIP-848).
+type ConsumerGroupDescribeRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// GroupIDs contains the ids of the groups to describe.
+	GroupIDs []string
+	// IncludeAuthorizedOperations controls whether to include the authorized
+	// operations for each group.
+	IncludeAuthorizedOperations bool
+}
+
+// NewConsumerGroupDescribeRequest returns a ConsumerGroupDescribeRequest
+// using the highest protocol version supported by the given Kafka version.
+func NewConsumerGroupDescribeRequest(version KafkaVersion, groupIDs []string) *ConsumerGroupDescribeRequest {
+	r := &ConsumerGroupDescribeRequest{GroupIDs: groupIDs}
+	if version.IsAtLeast(V4_0_0_0) {
+		// Version 1 adds the member type to the response.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ConsumerGroupDescribeRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ConsumerGroupDescribeRequest) encode(pe packetEncoder) error {
+	if err := pe.putStringArray(r.GroupIDs); err != nil {
+		return err
+	}
+
+	pe.putBool(r.IncludeAuthorizedOperations)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ConsumerGroupDescribeRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.GroupIDs, err = pd.getStringArray(); err != nil {
+		return err
+	}
+
+	if r.IncludeAuthorizedOperations, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupDescribeRequest) key() int16 {
+	return apiKeyConsumerGroupDescribe
+}
+
+func (r *ConsumerGroupDescribeRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ConsumerGroupDescribeRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *ConsumerGroupDescribeRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ConsumerGroupDescribeRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ConsumerGroupDescribeRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ConsumerGroupDescribeRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_0_0_0
+	case 0:
+		return V3_7_0_0
+	default:
+		return V4_0_0_0
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var consumerGroupDescribeRequestV0 = []byte{
+	2, 2, 'g', // GroupIDs
+	1, // IncludeAuthorizedOperations
+	0, // empty tagged fields
+}
+
+func TestConsumerGroupDescribeRequest(t *testing.T) {
+	request := &ConsumerGroupDescribeRequest{
+		Version:                     0,
+		GroupIDs:                    []string{"g"},
+		IncludeAuthorizedOperations: true,
+	}
+	testRequest(t, "v0", request, consumerGroupDescribeRequestV0)
+
+	request.Version = 1
+	testRequest(t, "v1", request, consumerGroupDescribeRequestV0)
+}
@@ -0,0 +1,428 @@
+package sarama
+
+import "time"
+
+// ConsumerGroupDescribeResponse is returned by the group coordinator in
+// response to a ConsumerGroupDescribeRequest.
+type ConsumerGroupDescribeResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+	// Groups contains each described group.
+	Groups []ConsumerGroupDescription
+}
+
+// ConsumerGroupDescription describes a single consumer group.
+type ConsumerGroupDescription struct {
+	// Err contains the describe error, or ErrNoError if there was no error.
+	Err KError
+	// ErrorMessage contains the top-level error message, or null if there was
+	// no error.
+	ErrorMessage *string
+	// GroupID contains the group id.
+	GroupID string
+	// GroupState contains the group state string, or the empty string.
+	GroupState string
+	// GroupEpoch contains the group epoch.
+	GroupEpoch int32
+	// AssignmentEpoch contains the assignment epoch.
+	AssignmentEpoch int32
+	// AssignorName contains the selected assignor.
+	AssignorName string
+	// Members contains the members.
+	Members []ConsumerGroupMemberDescription
+	// AuthorizedOperations contains a 32-bit bitfield to represent authorized
+	// operations for this group.
+	AuthorizedOperations int32
+}
+
+// ConsumerGroupMemberDescription describes a single member of a consumer
+// group.
+type ConsumerGroupMemberDescription struct {
+	// MemberID contains the member id.
+	MemberID string
+	// InstanceID contains the member instance id.
+	InstanceID *string
+	// RackID contains the member rack id.
+	RackID *string
+	// MemberEpoch contains the current member epoch.
+	MemberEpoch int32
+	// ClientID contains the client id.
+	ClientID string
+	// ClientHost contains the client host.
+	ClientHost string
+	// SubscribedTopicNames contains the subscribed topic names.
+	SubscribedTopicNames []string
+	// SubscribedTopicRegex contains the subscribed topic regex otherwise
+	// null if not provided.
+	SubscribedTopicRegex *string
+	// Assignment contains the current assignment.
+	Assignment ConsumerGroupDescribeAssignment
+	// TargetAssignment contains the target assignment.
+	TargetAssignment ConsumerGroupDescribeAssignment
+	// MemberType contains -1 for unknown, 0 for classic member and 1 for
+	// consumer member (v1+).
+	MemberType int8
+}
+
+// ConsumerGroupDescribeAssignment is a set of partitions assigned to a
+// member.
+type ConsumerGroupDescribeAssignment struct {
+	// TopicPartitions contains the assigned topic-partitions to the member.
+	TopicPartitions []ConsumerGroupDescribeTopicPartitions
+}
+
+// ConsumerGroupDescribeTopicPartitions identifies a set of partitions of a
+// topic by both topic id and name.
+type ConsumerGroupDescribeTopicPartitions struct {
+	// TopicID contains the topic id.
+	TopicID Uuid
+	// TopicName contains the topic name.
+	TopicName string
+	// Partitions contains the partitions.
+	Partitions []int32
+}
+
+func (t *ConsumerGroupDescribeTopicPartitions) encode(pe packetEncoder) error {
+	if err := pe.putUuid(t.TopicID); err != nil {
+		return err
+	}
+
+	if err := pe.putString(t.TopicName); err != nil {
+		return err
+	}
+
+	if err := pe.putInt32Array(t.Partitions); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *ConsumerGroupDescribeTopicPartitions) decode(pd packetDecoder, version int16) (err error) {
+	if t.TopicID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if t.TopicName, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if t.Partitions, err = pd.getInt32Array(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (a *ConsumerGroupDescribeAssignment) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(a.TopicPartitions)); err != nil {
+		return err
+	}
+	for i := range a.TopicPartitions {
+		if err := a.TopicPartitions[i].encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (a *ConsumerGroupDescribeAssignment) decode(pd packetDecoder, version int16) (err error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	a.TopicPartitions = make([]ConsumerGroupDescribeTopicPartitions, n)
+	for i := range n {
+		if err := a.TopicPartitions[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (m *ConsumerGroupMemberDescription) encode(pe packetEncoder, version int16) error {
+	if err := pe.putString(m.MemberID); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(m.InstanceID); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(m.RackID); err != nil {
+		return err
+	}
+
+	pe.putInt32(m.MemberEpoch)
+
+	if err := pe.putString(m.ClientID); err != nil {
+		return err
+	}
+
+	if err := pe.putString(m.ClientHost); err != nil {
+		return err
+	}
+
+	if err := pe.putStringArray(m.SubscribedTopicNames); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(m.SubscribedTopicRegex); err != nil {
+		return err
+	}
+
+	if err := m.Assignment.encode(pe); err != nil {
+		return err
+	}
+
+	if err := m.TargetAssignment.encode(pe); err != nil {
+		return err
+	}
+
+	if version >= 1 {
+		pe.putInt8(m.MemberType)
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (m *ConsumerGroupMemberDescription) decode(pd packetDecoder, version int16) (err error) {
+	if m.MemberID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if m.InstanceID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if m.RackID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if m.MemberEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if m.ClientID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if m.ClientHost, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if m.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
+		return err
+	}
+
+	if m.SubscribedTopicRegex, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if err := m.Assignment.decode(pd, version); err != nil {
+		return err
+	}
+
+	if err := m.TargetAssignment.decode(pd, version); err != nil {
+		return err
+	}
+
+	m.MemberType = -1
+	if version >= 1 {
+		if m.MemberType, err = pd.getInt8(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (g *ConsumerGroupDescription) encode(pe packetEncoder, version int16) error {
+	pe.putKError(g.Err)
+
+	if err := pe.putNullableString(g.ErrorMessage); err != nil {
+		return err
+	}
+
+	if err := pe.putString(g.GroupID); err != nil {
+		return err
+	}
+
+	if err := pe.putString(g.GroupState); err != nil {
+		return err
+	}
+
+	pe.putInt32(g.GroupEpoch)
+	pe.putInt32(g.AssignmentEpoch)
+
+	if err := pe.putString(g.AssignorName); err != nil {
+		return err
+	}
+
+	if err := pe.putArrayLength(len(g.Members)); err != nil {
+		return err
+	}
+	for i := range g.Members {
+		if err := g.Members[i].encode(pe, version); err != nil {
+			return err
+		}
+	}
+
+	pe.putInt32(g.AuthorizedOperations)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (g *ConsumerGroupDescription) decode(pd packetDecoder, version int16) (err error) {
+	if g.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if g.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if g.GroupID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if g.GroupState, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if g.GroupEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if g.AssignmentEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if g.AssignorName, err = pd.getString(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	g.Members = make([]ConsumerGroupMemberDescription, n)
+	for i := range n {
+		if err := g.Members[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	if g.AuthorizedOperations, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupDescribeResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ConsumerGroupDescribeResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+
+	if err := pe.putArrayLength(len(r.Groups)); err != nil {
+		return err
+	}
+	for i := range r.Groups {
+		if err := r.Groups[i].encode(pe, r.Version); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ConsumerGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Groups = make([]ConsumerGroupDescription, n)
+	for i := range n {
+		if err := r.Groups[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupDescribeResponse) key() int16 {
+	return apiKeyConsumerGroupDescribe
+}
+
+func (r *ConsumerGroupDescribeResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ConsumerGroupDescribeResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *ConsumerGroupDescribeResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ConsumerGroupDescribeResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ConsumerGroupDescribeResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ConsumerGroupDescribeResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_0_0_0
+	case 0:
+		return V3_7_0_0
+	default:
+		return V4_0_0_0
+	}
+}
+
+func (r *ConsumerGroupDescribeResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,146 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	consumerGroupDescribeResponseV0 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		2,    // Groups
+		0, 0, // ErrorCode
+		0,      // ErrorMessage (null)
+		2, 'g', // GroupID
+		7, 'S', 't', 'a', 'b', 'l', 'e', // GroupState
+		0, 0, 0, 3, // GroupEpoch
+		0, 0, 0, 3, // AssignmentEpoch
+		8, 'u', 'n', 'i', 'f', 'o', 'r', 'm', // AssignorName
+		2,      // Members
+		2, 'm', // MemberID
+		0,          // InstanceID (null)
+		0,          // RackID (null)
+		0, 0, 0, 3, // MemberEpoch
+		2, 'c', // ClientID
+		2, 'h', // ClientHost
+		2, 2, 't', // SubscribedTopicNames
+		0,                                                     // SubscribedTopicRegex (null)
+		2,                                                     // Assignment.TopicPartitions
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2, 't', // TopicName
+		2, 0, 0, 0, 0, // Partitions
+		0,             // empty tagged fields
+		0,             // empty tagged fields
+		1,             // TargetAssignment.TopicPartitions
+		0,             // empty tagged fields
+		0,             // empty tagged fields
+		0x80, 0, 0, 0, // AuthorizedOperations
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	consumerGroupDescribeResponseV1 = []byte{
+		0, 0, 0, 0, // ThrottleTimeMs
+		2,     // Groups
+		0, 69, // ErrorCode
+		0,      // ErrorMessage (null)
+		2, 'g', // GroupID
+		1,          // GroupState
+		0, 0, 0, 0, // GroupEpoch
+		0, 0, 0, 0, // AssignmentEpoch
+		1,      // AssignorName
+		2,      // Members
+		2, 'm', // MemberID
+		2, 'i', // InstanceID
+		2, 'r', // RackID
+		0, 0, 0, 1, // MemberEpoch
+		2, 'c', // ClientID
+		2, 'h', // ClientHost
+		1,           // SubscribedTopicNames
+		3, 't', '*', // SubscribedTopicRegex
+		1,          // Assignment.TopicPartitions
+		0,          // empty tagged fields
+		1,          // TargetAssignment.TopicPartitions
+		0,          // empty tagged fields
+		1,          // MemberType
+		0,          // empty tagged fields
+		0, 0, 0, 0, // AuthorizedOperations
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestConsumerGroupDescribeResponse(t *testing.T) {
+	response := &ConsumerGroupDescribeResponse{
+		Version:      0,
+		ThrottleTime: 100 * time.Millisecond,
+		Groups: []ConsumerGroupDescription{
+			{
+				Err:             ErrNoError,
+				GroupID:         "g",
+				GroupState:      "Stable",
+				GroupEpoch:      3,
+				AssignmentEpoch: 3,
+				AssignorName:    "uniform",
+				Members: []ConsumerGroupMemberDescription{
+					{
+						MemberID:             "m",
+						MemberEpoch:          3,
+						ClientID:             "c",
+						ClientHost:           "h",
+						SubscribedTopicNames: []string{"t"},
+						Assignment: ConsumerGroupDescribeAssignment{
+							TopicPartitions: []ConsumerGroupDescribeTopicPartitions{
+								{
+									TopicID:    Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+									TopicName:  "t",
+									Partitions: []int32{0},
+								},
+							},
+						},
+						TargetAssignment: ConsumerGroupDescribeAssignment{
+							TopicPartitions: []ConsumerGroupDescribeTopicPartitions{},
+						},
+						MemberType: -1,
+					},
+				},
+				AuthorizedOperations: -2147483648,
+			},
+		},
+	}
+	testResponse(t, "v0", response, consumerGroupDescribeResponseV0)
+
+	instance := "i"
+	rack := "r"
+	regex := "t*"
+	response = &ConsumerGroupDescribeResponse{
+		Version: 1,
+		Groups: []ConsumerGroupDescription{
+			{
+				Err:     ErrGroupIDNotFound,
+				GroupID: "g",
+				Members: []ConsumerGroupMemberDescription{
+					{
+						MemberID:             "m",
+						InstanceID:           &instance,
+						RackID:               &rack,
+						MemberEpoch:          1,
+						ClientID:             "c",
+						ClientHost:           "h",
+						SubscribedTopicRegex: &regex,
+						Assignment: ConsumerGroupDescribeAssignment{
+							TopicPartitions: []ConsumerGroupDescribeTopicPartitions{},
+						},
+						TargetAssignment: ConsumerGroupDescribeAssignment{
+							TopicPartitions: []ConsumerGroupDescribeTopicPartitions{},
+						},
+						MemberType: 1,
+					},
+				},
+			},
+		},
+	}
+	testResponse(t, "v1", response, consumerGroupDescribeResponseV1)
+}
@@ -0,0 +1,254 @@
+package sarama
+
+// ConsumerGroupHeartbeatRequest is sent by members of a consumer group using
+// the consumer rebalance protocol (KIP-848) to join the group, keep their
+// membership alive and acknowledge their assigned partitions.
+type ConsumerGroupHeartbeatRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// GroupID contains the group identifier.
+	GroupID string
+	// MemberID contains the member id generated by the consumer (v1+) or
+	// assigned by the coordinator (v0). The member id must be kept during the
+	// entire lifetime of the member.
+	MemberID string
+	// MemberEpoch contains the current member epoch; 0 to join the group; -1
+	// to leave the group; -2 to indicate that the static member will rejoin.
+	MemberEpoch int32
+	// InstanceID contains the instance id if provided, null otherwise.
+	InstanceID *string
+	// RackID contains the rack id of the member if provided, null otherwise.
+	RackID *string
+	// RebalanceTimeoutMs contains the maximum time in milliseconds that the
+	// coordinator will wait on the member to revoke its partitions, -1 if it
+	// did not change since the last heartbeat.
+	RebalanceTimeoutMs int32
+	// SubscribedTopicNames contains the list of subscribed topic names, null
+	// if it did not change since the last heartbeat.
+	SubscribedTopicNames []string
+	// SubscribedTopicRegex contains the regular expression used to subscribe
+	// to topics (v1+), null if it did not change since the last heartbeat.
+	SubscribedTopicRegex *string
+	// ServerAssignor contains the server side assignor to use, null if it did
+	// not change since the last heartbeat.
+	ServerAssignor *string
+	// TopicPartitions contains the partitions owned by the member, null if it
+	// did not change since the last heartbeat.
+	TopicPartitions []ConsumerGroupHeartbeatTopicPartitions
+}
+
+// ConsumerGroupHeartbeatTopicPartitions identifies a set of partitions of a
+// topic by topic id.
+type ConsumerGroupHeartbeatTopicPartitions struct {
+	// TopicID contains the topic id.
+	TopicID Uuid
+	// Partitions contains the partitions.
+	Partitions []int32
+}
+
+func (t *ConsumerGroupHeartbeatTopicPartitions) encode(pe packetEncoder) error {
+	if err := pe.putUuid(t.TopicID); err != nil {
+		return err
+	}
+
+	if err := pe.putInt32Array(t.Partitions); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *ConsumerGroupHeartbeatTopicPartitions) decode(pd packetDecoder, version int16) (err error) {
+	if t.TopicID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if t.Partitions, err = pd.getInt32Array(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func encodeConsumerGroupHeartbeatTopicPartitions(pe packetEncoder, topics []ConsumerGroupHeartbeatTopicPartitions) error {
+	if topics == nil {
+		return pe.putArrayLength(-1)
+	}
+
+	if err := pe.putArrayLength(len(topics)); err != nil {
+		return err
+	}
+	for i := range topics {
+		if err := topics[i].encode(pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeConsumerGroupHeartbeatTopicPartitions(pd packetDecoder, version int16) ([]ConsumerGroupHeartbeatTopicPartitions, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, nil
+	}
+
+	topics := make([]ConsumerGroupHeartbeatTopicPartitions, n)
+	for i := range n {
+		if err := topics[i].decode(pd, version); err != nil {
+			return nil, err
+		}
+	}
+	return topics, nil
+}
+
+// NewConsumerGroupHeartbeatRequest returns a ConsumerGroupHeartbeatRequest
+// using the highest protocol version supported by the given Kafka version.
+func NewConsumerGroupHeartbeatRequest(version KafkaVersion) *ConsumerGroupHeartbeatRequest {
+	r := &ConsumerGroupHeartbeatRequest{}
+	if version.IsAtLeast(V4_0_0_0) {
+		// Version 1 adds the regular expression subscription and requires
+		// the member id to be generated by the consumer.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ConsumerGroupHeartbeatRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ConsumerGroupHeartbeatRequest) encode(pe packetEncoder) error {
+	if err := pe.putString(r.GroupID); err != nil {
+		return err
+	}
+
+	if err := pe.putString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.MemberEpoch)
+
+	if err := pe.putNullableString(r.InstanceID); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(r.RackID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.RebalanceTimeoutMs)
+
+	if r.SubscribedTopicNames == nil {
+		if err := pe.putArrayLength(-1); err != nil {
+			return err
+		}
+	} else if err := pe.putStringArray(r.SubscribedTopicNames); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		if err := pe.putNullableString(r.SubscribedTopicRegex); err != nil {
+			return err
+		}
+	}
+
+	if err := pe.putNullableString(r.ServerAssignor); err != nil {
+		return err
+	}
+
+	if err := encodeConsumerGroupHeartbeatTopicPartitions(pe, r.TopicPartitions); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ConsumerGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.GroupID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if r.MemberEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.InstanceID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.RackID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.RebalanceTimeoutMs, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		if r.SubscribedTopicRegex, err = pd.getNullableString(); err != nil {
+			return err
+		}
+	}
+
+	if r.ServerAssignor, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.TopicPartitions, err = decodeConsumerGroupHeartbeatTopicPartitions(pd, version); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupHeartbeatRequest) key() int16 {
+	return apiKeyConsumerGroupHeartbeat
+}
+
+func (r *ConsumerGroupHeartbeatRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ConsumerGroupHeartbeatRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *ConsumerGroupHeartbeatRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ConsumerGroupHeartbeatRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ConsumerGroupHeartbeatRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ConsumerGroupHeartbeatRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_0_0_0
+	case 0:
+		return V3_5_0_0
+	default:
+		return V4_0_0_0
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	consumerGroupHeartbeatRequestV0 = []byte{
+		2, 'g', // GroupID
+		2, 'm', // MemberID
+		0, 0, 0, 5, // MemberEpoch
+		0,      // InstanceID (null)
+		2, 'r', // RackID
+		0, 0, 0xea, 0x60, // RebalanceTimeoutMs
+		2, 2, 't', // SubscribedTopicNames
+		8, 'u', 'n', 'i', 'f', 'o', 'r', 'm', // ServerAssignor
+		2,                                                     // TopicPartitions
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		3, 0, 0, 0, 0, 0, 0, 0, 1, // Partitions
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	consumerGroupHeartbeatRequestV1 = []byte{
+		2, 'g', // GroupID
+		2, 'm', // MemberID
+		0, 0, 0, 5, // MemberEpoch
+		2, 'i', // InstanceID
+		0,                // RackID (null)
+		0, 0, 0xea, 0x60, // RebalanceTimeoutMs
+		2, 2, 't', // SubscribedTopicNames
+		0,                                                     // SubscribedTopicRegex (null)
+		0,                                                     // ServerAssignor (null)
+		2,                                                     // TopicPartitions
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		3, 0, 0, 0, 0, 0, 0, 0, 1, // Partitions
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestConsumerGroupHeartbeatRequest(t *testing.T) {
+	rack := "r"
+	assignor := "uniform"
+	instance := "i"
+	topicID := Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	request := &ConsumerGroupHeartbeatRequest{
+		Version:              0,
+		GroupID:              "g",
+		MemberID:             "m",
+		MemberEpoch:          5,
+		RackID:               &rack,
+		RebalanceTimeoutMs:   60000,
+		SubscribedTopicNames: []string{"t"},
+		ServerAssignor:       &assignor,
+		TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{
+			{TopicID: topicID, Partitions: []int32{0, 1}},
+		},
+	}
+	testRequest(t, "v0", request, consumerGroupHeartbeatRequestV0)
+
+	request = &ConsumerGroupHeartbeatRequest{
+		Version:              1,
+		GroupID:              "g",
+		MemberID:             "m",
+		MemberEpoch:          5,
+		InstanceID:           &instance,
+		RebalanceTimeoutMs:   60000,
+		SubscribedTopicNames: []string{"t"},
+		TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{
+			{TopicID: topicID, Partitions: []int32{0, 1}},
+		},
+	}
+	testRequest(t, "v1", request, consumerGroupHeartbeatRequestV1)
+}
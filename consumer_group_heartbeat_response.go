@@ -0,0 +1,169 @@
+package sarama
+
+import "time"
+
+// ConsumerGroupHeartbeatResponse is returned by the group coordinator in
+// response to a ConsumerGroupHeartbeatRequest.
+type ConsumerGroupHeartbeatResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+	// Err contains the top-level error code, or ErrNoError if there was no
+	// error.
+	Err KError
+	// ErrorMessage contains the top-level error message, or null if there was
+	// no error.
+	ErrorMessage *string
+	// MemberID contains the member id generated by the coordinator. Only
+	// provided when the member joins with an empty member id (v0).
+	MemberID *string
+	// MemberEpoch contains the member epoch.
+	MemberEpoch int32
+	// HeartbeatIntervalMs contains the heartbeat interval in milliseconds.
+	HeartbeatIntervalMs int32
+	// Assignment contains the new assignment of the member, or null if it
+	// did not change since the last heartbeat.
+	Assignment *ConsumerGroupHeartbeatAssignment
+}
+
+// ConsumerGroupHeartbeatAssignment is the assignment sent to a member by the
+// group coordinator.
+type ConsumerGroupHeartbeatAssignment struct {
+	// TopicPartitions contains the partitions assigned to the member that
+	// can be used immediately.
+	TopicPartitions []ConsumerGroupHeartbeatTopicPartitions
+}
+
+func (a *ConsumerGroupHeartbeatAssignment) encode(pe packetEncoder) error {
+	if err := encodeConsumerGroupHeartbeatTopicPartitions(pe, a.TopicPartitions); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (a *ConsumerGroupHeartbeatAssignment) decode(pd packetDecoder, version int16) (err error) {
+	if a.TopicPartitions, err = decodeConsumerGroupHeartbeatTopicPartitions(pd, version); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupHeartbeatResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ConsumerGroupHeartbeatResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.Err)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.MemberEpoch)
+	pe.putInt32(r.HeartbeatIntervalMs)
+
+	if r.Assignment == nil {
+		pe.putInt8(-1)
+	} else {
+		pe.putInt8(1)
+		if err := r.Assignment.encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ConsumerGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.HeartbeatIntervalMs, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	present, err := pd.getInt8()
+	if err != nil {
+		return err
+	}
+	if present >= 0 {
+		r.Assignment = new(ConsumerGroupHeartbeatAssignment)
+		if err := r.Assignment.decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ConsumerGroupHeartbeatResponse) key() int16 {
+	return apiKeyConsumerGroupHeartbeat
+}
+
+func (r *ConsumerGroupHeartbeatResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ConsumerGroupHeartbeatResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *ConsumerGroupHeartbeatResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ConsumerGroupHeartbeatResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ConsumerGroupHeartbeatResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ConsumerGroupHeartbeatResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_0_0_0
+	case 0:
+		return V3_5_0_0
+	default:
+		return V4_0_0_0
+	}
+}
+
+func (r *ConsumerGroupHeartbeatResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
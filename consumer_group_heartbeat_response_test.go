@@ -0,0 +1,67 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	consumerGroupHeartbeatResponseV0 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		0, 0, // ErrorCode
+		0,      // ErrorMessage (null)
+		2, 'm', // MemberID
+		0, 0, 0, 1, // MemberEpoch
+		0, 0, 0x13, 0x88, // HeartbeatIntervalMs
+		1,                                                     // Assignment (present)
+		2,                                                     // TopicPartitions
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2, 0, 0, 0, 2, // Partitions
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	consumerGroupHeartbeatResponseV1Fenced = []byte{
+		0, 0, 0, 0, // ThrottleTimeMs
+		0, 110, // ErrorCode
+		2, 'x', // ErrorMessage
+		0,          // MemberID (null)
+		0, 0, 0, 0, // MemberEpoch
+		0, 0, 0, 0, // HeartbeatIntervalMs
+		0xff, // Assignment (null)
+		0,    // empty tagged fields
+	}
+)
+
+func TestConsumerGroupHeartbeatResponse(t *testing.T) {
+	memberID := "m"
+	message := "x"
+
+	response := &ConsumerGroupHeartbeatResponse{
+		Version:             0,
+		ThrottleTime:        100 * time.Millisecond,
+		Err:                 ErrNoError,
+		MemberID:            &memberID,
+		MemberEpoch:         1,
+		HeartbeatIntervalMs: 5000,
+		Assignment: &ConsumerGroupHeartbeatAssignment{
+			TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{
+				{
+					TopicID:    Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+					Partitions: []int32{2},
+				},
+			},
+		},
+	}
+	testResponse(t, "v0", response, consumerGroupHeartbeatResponseV0)
+
+	response = &ConsumerGroupHeartbeatResponse{
+		Version:      1,
+		Err:          ErrFencedMemberEpoch,
+		ErrorMessage: &message,
+	}
+	testResponse(t, "v1 fenced", response, consumerGroupHeartbeatResponseV1Fenced)
+}
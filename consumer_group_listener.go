@@ -0,0 +1,91 @@
+package sarama
+
+import (
+	"context"
+	"errors"
+	"maps"
+)
+
+// RebalanceListener is an optional interface for a ConsumerGroupHandler that
+// needs to know which partitions a rebalance added to or took away from the
+// member, for example to flush or discard per-partition state. As every
+// session is released before the member joins the group again, the partitions
+// of a session are all given up when it ends, whatever the balance strategy,
+// and the partitions of the next session are all assigned again.
+//
+// The callbacks are run from the goroutine calling ConsumerGroup.Consume, or
+// ConsumerGroup.Close, while no ConsumeClaim of the member is running.
+//
+// With Consumer.Group.Protocol set to GroupProtocolConsumer, partitions are
+// also assigned and revoked during a session, without stopping the claims of
+// the other partitions. OnPartitionsAssigned is then called before the claims
+// of the new partitions start, and OnPartitionsRevoked once the claims of the
+// revoked ones have returned and their offsets have been committed, from a
+// goroutine of the session while the other claims are running.
+type RebalanceListener interface {
+	// OnPartitionsAssigned is called with the claims of a new session, after
+	// Setup and before ConsumeClaim.
+	OnPartitionsAssigned(partitions map[string][]int32)
+
+	// OnPartitionsRevoked is called with the claims of a session as it is
+	// released, after Cleanup and before the member joins the group again.
+	// Their offsets have been committed.
+	OnPartitionsRevoked(partitions map[string][]int32)
+
+	// OnPartitionsLost is called, in place of OnPartitionsRevoked, with the
+	// claims of a session that ended because the member was removed from the
+	// group, typically after its session timed out. The partitions may already
+	// be owned by other members and their offsets may not have been
+	// committed, so their state should be discarded rather than flushed.
+	OnPartitionsLost(partitions map[string][]int32)
+}
+
+// assignOwned notifies the handler of a session once it is set up of the
+// partitions assigned to the member.
+func (s *consumerGroupSession) assignOwned() {
+	s.owning = true
+	if listener, ok := s.handler.(RebalanceListener); ok && len(s.claims) > 0 {
+		listener.OnPartitionsAssigned(maps.Clone(s.claims))
+	}
+}
+
+// releaseOwned notifies the handler of a released session that the member
+// gave its partitions up: they are lost if the session ended because the
+// member was removed from the group, and revoked otherwise.
+func (s *consumerGroupSession) releaseOwned() {
+	listener, ok := s.handler.(RebalanceListener)
+	if !s.owning || !ok || len(s.claims) == 0 {
+		return
+	}
+	if isLostSessionCause(context.Cause(s.ctx)) {
+		listener.OnPartitionsLost(maps.Clone(s.claims))
+	} else {
+		listener.OnPartitionsRevoked(maps.Clone(s.claims))
+	}
+}
+
+// partitionsAssigned notifies the handler of the partitions assigned to the
+// member during a session by the consumer group protocol.
+func (s *consumerGroupSession) partitionsAssigned(partitions map[string][]int32) {
+	if listener, ok := s.handler.(RebalanceListener); ok {
+		listener.OnPartitionsAssigned(partitions)
+	}
+}
+
+// partitionsRevoked notifies the handler of the partitions revoked from the
+// member during a session by the consumer group protocol.
+func (s *consumerGroupSession) partitionsRevoked(partitions map[string][]int32) {
+	if listener, ok := s.handler.(RebalanceListener); ok {
+		listener.OnPartitionsRevoked(partitions)
+	}
+}
+
+// isLostSessionCause reports whether a session ended because the member was
+// removed from the group, in which case its claims may already be owned by
+// other members.
+func isLostSessionCause(cause error) bool {
+	return errors.Is(cause, ErrUnknownMemberId) ||
+		errors.Is(cause, ErrIllegalGeneration) ||
+		errors.Is(cause, ErrFencedInstancedId) ||
+		errors.Is(cause, ErrFencedMemberEpoch)
+}
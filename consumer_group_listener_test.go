@@ -0,0 +1,183 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rebalanceEvent struct {
+	kind       string
+	partitions map[string][]int32
+}
+
+type listenerHandler struct {
+	drainHandler
+
+	lock   sync.Mutex
+	events []rebalanceEvent
+}
+
+func (h *listenerHandler) Setup(ConsumerGroupSession) error {
+	h.record("setup", nil)
+	return nil
+}
+
+func (h *listenerHandler) Cleanup(ConsumerGroupSession) error {
+	h.record("cleanup", nil)
+	return nil
+}
+
+func (h *listenerHandler) OnPartitionsAssigned(partitions map[string][]int32) {
+	h.record("assigned", partitions)
+}
+
+func (h *listenerHandler) OnPartitionsRevoked(partitions map[string][]int32) {
+	h.record("revoked", partitions)
+}
+
+func (h *listenerHandler) OnPartitionsLost(partitions map[string][]int32) {
+	h.record("lost", partitions)
+}
+
+func (h *listenerHandler) record(kind string, partitions map[string][]int32) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.events = append(h.events, rebalanceEvent{kind: kind, partitions: partitions})
+}
+
+func (h *listenerHandler) recorded() []rebalanceEvent {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]rebalanceEvent(nil), h.events...)
+}
+
+func TestRebalanceListenerOwnedPartitions(t *testing.T) {
+	h := &listenerHandler{}
+
+	session := func(claims map[string][]int32, cause error) {
+		ctx, cancel := context.WithCancelCause(t.Context())
+		cancel(cause)
+		sess := &consumerGroupSession{handler: h, claims: claims, ctx: ctx}
+		sess.assignOwned()
+		sess.releaseOwned()
+	}
+
+	session(map[string][]int32{"a": {0, 1}, "b": {0}}, ErrRebalanceInProgress)
+	session(map[string][]int32{"a": {1, 2}}, ErrUnknownMemberId)
+	session(map[string][]int32{"a": {2}}, ErrSessionHeartbeatFailed)
+	session(map[string][]int32{"b": {1}}, ErrFencedMemberEpoch)
+	session(nil, nil)
+
+	require.Equal(t, []rebalanceEvent{
+		{"assigned", map[string][]int32{"a": {0, 1}, "b": {0}}},
+		{"revoked", map[string][]int32{"a": {0, 1}, "b": {0}}},
+		{"assigned", map[string][]int32{"a": {1, 2}}},
+		{"lost", map[string][]int32{"a": {1, 2}}},
+		{"assigned", map[string][]int32{"a": {2}}},
+		{"revoked", map[string][]int32{"a": {2}}},
+		{"assigned", map[string][]int32{"b": {1}}},
+		{"lost", map[string][]int32{"b": {1}}},
+	}, h.recorded())
+
+	// a session that failed to set up never owned its claims
+	(&consumerGroupSession{handler: h, claims: map[string][]int32{"a": {0}}, ctx: t.Context()}).releaseOwned()
+	require.Len(t, h.recorded(), 8)
+}
+
+func TestConsumerGroupRebalanceListener(t *testing.T) {
+	setup := func(t *testing.T, heartbeat MockResponse) ConsumerGroup {
+		t.Helper()
+		config := NewTestConfig()
+		config.ClientID = t.Name()
+		config.Version = V2_0_0_0
+		config.Consumer.Return.Errors = true
+		config.Consumer.Group.Rebalance.Retry.Max = 0
+		config.Consumer.Offsets.AutoCommit.Enable = false
+		config.Consumer.Group.Heartbeat.Interval = 50 * time.Millisecond
+
+		broker0 := NewMockBroker(t, 0)
+		t.Cleanup(broker0.Close)
+
+		broker0.SetHandlerByMap(map[string]MockResponse{
+			"MetadataRequest": NewMockMetadataResponse(t).
+				SetBroker(broker0.Addr(), broker0.BrokerID()).
+				SetLeader("my-topic", 0, broker0.BrokerID()),
+			"OffsetRequest": NewMockOffsetResponse(t).
+				SetOffset("my-topic", 0, OffsetOldest, 0).
+				SetOffset("my-topic", 0, OffsetNewest, 1),
+			"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+				SetCoordinator(CoordinatorGroup, "my-group", broker0),
+			"HeartbeatRequest": heartbeat,
+			"JoinGroupRequest": NewMockJoinGroupResponse(t).SetGroupProtocol(RangeBalanceStrategyName),
+			"SyncGroupRequest": NewMockSyncGroupResponse(t).SetMemberAssignment(
+				&ConsumerGroupMemberAssignment{
+					Version: 0,
+					Topics:  map[string][]int32{"my-topic": {0}},
+				}),
+			"OffsetFetchRequest": NewMockOffsetFetchResponse(t).SetOffset(
+				"my-group", "my-topic", 0, 0, "", ErrNoError,
+			).SetError(ErrNoError),
+			"OffsetCommitRequest": NewMockOffsetCommitResponse(t),
+			"LeaveGroupRequest":   NewMockLeaveGroupResponse(t),
+			"FetchRequest": NewMockFetchResponse(t, 1).
+				SetMessage("my-topic", 0, 0, StringEncoder("foo")),
+		})
+
+		group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+		require.NoError(t, err)
+		return group
+	}
+	claims := map[string][]int32{"my-topic": {0}}
+
+	t.Run("revoked on close", func(t *testing.T) {
+		group := setup(t, NewMockHeartbeatResponse(t))
+		h := &listenerHandler{}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() { done <- group.Consume(ctx, []string{"my-topic"}, h) }()
+
+		require.Eventually(t, func() bool { return len(h.recorded()) == 2 }, 5*time.Second, time.Millisecond)
+		cancel()
+		require.NoError(t, <-done)
+		require.Equal(t, []rebalanceEvent{
+			{"setup", nil}, {"assigned", claims}, {"cleanup", nil}, {"revoked", claims},
+		}, h.recorded(), "the partitions are revoked as the session is released")
+
+		require.NoError(t, group.Close())
+		require.Len(t, h.recorded(), 4)
+	})
+
+	t.Run("revoked before rejoining", func(t *testing.T) {
+		group := setup(t, NewMockHeartbeatResponse(t).SetError(ErrRebalanceInProgress))
+		defer func() { _ = group.Close() }()
+		h := &listenerHandler{}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		for range 2 {
+			require.NoError(t, group.Consume(ctx, []string{"my-topic"}, h))
+		}
+		session := []rebalanceEvent{{"setup", nil}, {"assigned", claims}, {"cleanup", nil}, {"revoked", claims}}
+		require.Equal(t, append(session, session...), h.recorded())
+	})
+
+	t.Run("lost when removed from the group", func(t *testing.T) {
+		group := setup(t, NewMockHeartbeatResponse(t).SetError(ErrUnknownMemberId))
+		defer func() { _ = group.Close() }()
+		h := &listenerHandler{}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, group.Consume(ctx, []string{"my-topic"}, h))
+		require.Equal(t, []rebalanceEvent{
+			{"setup", nil}, {"assigned", claims}, {"cleanup", nil}, {"lost", claims},
+		}, h.recorded())
+	})
+}
@@ -0,0 +1,271 @@
+package sarama
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The headers recorded on the messages forwarded by a RetryHandler to a retry
+// or dead-letter topic. The attempt, partition and offset are decimal strings.
+const (
+	// RetryHeaderAttempt holds the number of times the message failed to be processed.
+	RetryHeaderAttempt = "retry-attempt"
+	// RetryHeaderOriginalTopic holds the topic the message was first consumed from.
+	RetryHeaderOriginalTopic = "retry-original-topic"
+	// RetryHeaderOriginalPartition holds the partition the message was first consumed from.
+	RetryHeaderOriginalPartition = "retry-original-partition"
+	// RetryHeaderOriginalOffset holds the offset the message was first consumed at.
+	RetryHeaderOriginalOffset = "retry-original-offset"
+	// RetryHeaderError holds the error of the last failed attempt.
+	RetryHeaderError = "retry-error"
+)
+
+// RetryTopics describes the retry tiers and the dead-letter topic of a RetryHandler.
+type RetryTopics struct {
+	// Delays are the delays of the retry tiers, in order. A message that fails
+	// to be processed is forwarded to the retry topic of the next tier, where it
+	// is processed again once its delay has elapsed, and to the dead-letter
+	// topic once every tier has been tried.
+	Delays []time.Duration
+	// RetryTopic returns the retry topic of a tier for the original topic of a
+	// message. Defaults to "<topic>.retry.<delay>", for instance
+	// "orders.retry.1m" and "orders.retry.10m".
+	RetryTopic func(topic string, delay time.Duration) string
+	// DeadLetterTopic returns the dead-letter topic for the original topic of a
+	// message. Defaults to "<topic>.dlq".
+	DeadLetterTopic func(topic string) string
+}
+
+// RetryHandler is a ConsumerGroupHandler that processes messages one at a time
+// and forwards the ones that fail to tiered retry topics, and finally to a
+// dead-letter topic, using an AsyncProducer. Create it with NewRetryHandler.
+//
+// The group must consume the original topics as well as their retry topics.
+// A message consumed from a retry topic is only processed once its timestamp
+// plus the delay of its tier has passed; until then its partition is paused
+// with ConsumerGroup.Pause, so that no more messages are fetched from it.
+//
+// The offset of a failed message is only marked once it has been forwarded, so
+// messages are forwarded at least once.
+type RetryHandler struct {
+	group    ConsumerGroup
+	producer AsyncProducer
+	topics   RetryTopics
+	process  func(ConsumerGroupSession, *ConsumerMessage) error
+}
+
+// NewRetryHandler returns a RetryHandler calling process for every message
+// consumed by group. Failed messages are forwarded with producer, which must be
+// dedicated to the handler as the handler reads its Successes() and Errors()
+// channels until it is closed. The forwarded messages are tracked with
+// ProducerMessage.OnDelivery, so Producer.Return.Successes is not needed.
+func NewRetryHandler(group ConsumerGroup, producer AsyncProducer, topics RetryTopics, process func(ConsumerGroupSession, *ConsumerMessage) error) *RetryHandler {
+	if topics.RetryTopic == nil {
+		topics.RetryTopic = defaultRetryTopic
+	}
+	if topics.DeadLetterTopic == nil {
+		topics.DeadLetterTopic = defaultDeadLetterTopic
+	}
+	h := &RetryHandler{
+		group:    group,
+		producer: producer,
+		topics:   topics,
+		process:  process,
+	}
+	go withRecover(h.drainResults)
+	return h
+}
+
+// Setup implements ConsumerGroupHandler.
+func (h *RetryHandler) Setup(ConsumerGroupSession) error { return nil }
+
+// Cleanup implements ConsumerGroupHandler.
+func (h *RetryHandler) Cleanup(ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim implements ConsumerGroupHandler. It returns the error of
+// forwarding a failed message, which ends the session so that the message is
+// consumed again.
+func (h *RetryHandler) ConsumeClaim(session ConsumerGroupSession, claim ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if err := h.handle(session, msg); err != nil {
+				return err
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (h *RetryHandler) handle(session ConsumerGroupSession, msg *ConsumerMessage) error {
+	attempt := retryHeaderInt(msg, RetryHeaderAttempt, 0)
+	if attempt > 0 && attempt <= int64(len(h.topics.Delays)) {
+		if !h.waitForRetry(session, msg, h.topics.Delays[attempt-1]) {
+			return nil
+		}
+	}
+
+	cause := h.process(session, msg)
+	if cause == nil {
+		session.MarkMessage(msg, "")
+		return nil
+	}
+
+	forwarded, err := h.forward(session, msg, attempt+1, cause)
+	if err != nil {
+		return err
+	}
+	if forwarded {
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+// waitForRetry pauses the partition of a message consumed from a retry topic
+// until its delay has elapsed. It returns false if the session ends first.
+func (h *RetryHandler) waitForRetry(session ConsumerGroupSession, msg *ConsumerMessage, delay time.Duration) bool {
+	wait := time.Until(msg.Timestamp.Add(delay))
+	if wait <= 0 {
+		return true
+	}
+
+	partitions := map[string][]int32{msg.Topic: {msg.Partition}}
+	h.group.Pause(partitions)
+	defer h.group.Resume(partitions)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-session.Context().Done():
+		return false
+	}
+}
+
+// forward produces a failed message to the topic of its next attempt and waits
+// for it to be acknowledged. It returns false if the session ends first.
+func (h *RetryHandler) forward(session ConsumerGroupSession, msg *ConsumerMessage, attempt int64, cause error) (bool, error) {
+	origin := retryHeaderString(msg, RetryHeaderOriginalTopic, msg.Topic)
+	topic := h.topics.DeadLetterTopic(origin)
+	if attempt <= int64(len(h.topics.Delays)) {
+		topic = h.topics.RetryTopic(origin, h.topics.Delays[attempt-1])
+	}
+
+	done := make(chan error, 1)
+	forward := &ProducerMessage{
+		Topic:      topic,
+		Value:      ByteEncoder(msg.Value),
+		Headers:    retryHeaders(msg, attempt, cause),
+		Timestamp:  time.Now(),
+		OnDelivery: func(_ *ProducerMessage, err error) { done <- err },
+	}
+	if msg.Key != nil {
+		forward.Key = ByteEncoder(msg.Key)
+	}
+
+	ctx := session.Context()
+	select {
+	case h.producer.Input() <- forward:
+	case <-ctx.Done():
+		return false, nil
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			return false, fmt.Errorf("kafka: failed to forward message %s/%d/%d to %s: %w", msg.Topic, msg.Partition, msg.Offset, topic, err)
+		}
+		return true, nil
+	case <-ctx.Done():
+		return false, nil
+	}
+}
+
+// drainResults reads the results of the producer, which are handed over to
+// the forward calls waiting for them by OnDelivery, until the producer is
+// closed.
+func (h *RetryHandler) drainResults() {
+	successes, errs := h.producer.Successes(), h.producer.Errors()
+	for successes != nil || errs != nil {
+		select {
+		case _, ok := <-successes:
+			if !ok {
+				successes = nil
+			}
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
+			}
+		}
+	}
+}
+
+// retryHeaders returns the headers of a message forwarded for its given
+// attempt, keeping the original location recorded by an earlier attempt.
+func retryHeaders(msg *ConsumerMessage, attempt int64, cause error) []RecordHeader {
+	headers := make([]RecordHeader, 0, len(msg.Headers)+5)
+	for _, header := range msg.Headers {
+		switch string(header.Key) {
+		case RetryHeaderAttempt, RetryHeaderOriginalTopic, RetryHeaderOriginalPartition,
+			RetryHeaderOriginalOffset, RetryHeaderError:
+		default:
+			headers = append(headers, *header)
+		}
+	}
+
+	origin := func(key, value string) RecordHeader {
+		return RecordHeader{Key: []byte(key), Value: []byte(retryHeaderString(msg, key, value))}
+	}
+	return append(headers,
+		RecordHeader{Key: []byte(RetryHeaderAttempt), Value: []byte(strconv.FormatInt(attempt, 10))},
+		origin(RetryHeaderOriginalTopic, msg.Topic),
+		origin(RetryHeaderOriginalPartition, strconv.FormatInt(int64(msg.Partition), 10)),
+		origin(RetryHeaderOriginalOffset, strconv.FormatInt(msg.Offset, 10)),
+		RecordHeader{Key: []byte(RetryHeaderError), Value: []byte(cause.Error())},
+	)
+}
+
+func retryHeaderString(msg *ConsumerMessage, key, def string) string {
+	for _, header := range msg.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return def
+}
+
+func retryHeaderInt(msg *ConsumerMessage, key string, def int64) int64 {
+	value, err := strconv.ParseInt(retryHeaderString(msg, key, ""), 10, 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func defaultRetryTopic(topic string, delay time.Duration) string {
+	return topic + ".retry." + formatRetryDelay(delay)
+}
+
+func defaultDeadLetterTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// formatRetryDelay formats a delay in its largest whole unit, such as 1m or
+// 10m, rather than the 1m0s of time.Duration.
+func formatRetryDelay(delay time.Duration) string {
+	switch {
+	case delay >= time.Hour && delay%time.Hour == 0:
+		return strconv.FormatInt(int64(delay/time.Hour), 10) + "h"
+	case delay >= time.Minute && delay%time.Minute == 0:
+		return strconv.FormatInt(int64(delay/time.Minute), 10) + "m"
+	case delay >= time.Second && delay%time.Second == 0:
+		return strconv.FormatInt(int64(delay/time.Second), 10) + "s"
+	default:
+		return strconv.FormatInt(delay.Milliseconds(), 10) + "ms"
+	}
+}
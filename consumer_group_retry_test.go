@@ -0,0 +1,236 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type retryTestProducer struct {
+	AsyncProducer
+
+	input     chan *ProducerMessage
+	successes chan *ProducerMessage
+	errors    chan *ProducerError
+}
+
+func newRetryTestProducer() *retryTestProducer {
+	return &retryTestProducer{
+		input:     make(chan *ProducerMessage, 10),
+		successes: make(chan *ProducerMessage, 10),
+		errors:    make(chan *ProducerError, 10),
+	}
+}
+
+func (p *retryTestProducer) Input() chan<- *ProducerMessage     { return p.input }
+func (p *retryTestProducer) Successes() <-chan *ProducerMessage { return p.successes }
+func (p *retryTestProducer) Errors() <-chan *ProducerError      { return p.errors }
+
+func (p *retryTestProducer) Close() error {
+	close(p.successes)
+	close(p.errors)
+	return nil
+}
+
+type retryTestGroup struct {
+	ConsumerGroup
+
+	lock    sync.Mutex
+	paused  []map[string][]int32
+	resumed []map[string][]int32
+}
+
+func (g *retryTestGroup) Pause(partitions map[string][]int32) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.paused = append(g.paused, partitions)
+}
+
+func (g *retryTestGroup) Resume(partitions map[string][]int32) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.resumed = append(g.resumed, partitions)
+}
+
+func retryTestHeaders(msg *ProducerMessage) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return headers
+}
+
+func TestRetryHandlerForwardsFailedMessages(t *testing.T) {
+	producer := newRetryTestProducer()
+	defer func() { _ = producer.Close() }()
+	topics := RetryTopics{Delays: []time.Duration{time.Minute, 10 * time.Minute}}
+
+	failed := errors.New("failed")
+	handler := NewRetryHandler(&retryTestGroup{}, producer, topics, func(_ ConsumerGroupSession, msg *ConsumerMessage) error {
+		if string(msg.Value) == "bad" {
+			return failed
+		}
+		return nil
+	})
+
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 2)}
+	claim.messages <- &ConsumerMessage{Topic: "orders", Partition: 3, Offset: 7, Value: []byte("good")}
+	claim.messages <- &ConsumerMessage{
+		Topic: "orders", Partition: 3, Offset: 8, Key: []byte("key"), Value: []byte("bad"),
+		Headers: []*RecordHeader{{Key: []byte("trace"), Value: []byte("abc")}},
+	}
+	close(claim.messages)
+
+	done := make(chan error, 1)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	forwarded := <-producer.input
+	require.Equal(t, "orders.retry.1m", forwarded.Topic)
+	require.Equal(t, ByteEncoder("key"), forwarded.Key)
+	require.Equal(t, ByteEncoder("bad"), forwarded.Value)
+	require.Equal(t, map[string]string{
+		"trace":                      "abc",
+		RetryHeaderAttempt:           "1",
+		RetryHeaderOriginalTopic:     "orders",
+		RetryHeaderOriginalPartition: "3",
+		RetryHeaderOriginalOffset:    "8",
+		RetryHeaderError:             "failed",
+	}, retryTestHeaders(forwarded))
+
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, int64(8), session.markedOffset(), "the failed message must be forwarded before it is marked")
+
+	forwarded.OnDelivery(forwarded, nil)
+	require.NoError(t, <-done)
+	require.Equal(t, int64(9), session.markedOffset())
+}
+
+func TestRetryHandlerForwardsToDeadLetterTopic(t *testing.T) {
+	producer := newRetryTestProducer()
+	defer func() { _ = producer.Close() }()
+	topics := RetryTopics{Delays: []time.Duration{time.Minute, 10 * time.Minute}}
+
+	handler := NewRetryHandler(&retryTestGroup{}, producer, topics, func(ConsumerGroupSession, *ConsumerMessage) error {
+		return errors.New("still failing")
+	})
+
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 1)}
+	claim.messages <- &ConsumerMessage{
+		Topic: "orders.retry.10m", Offset: 2, Value: []byte("bad"),
+		Timestamp: time.Now().Add(-time.Hour),
+		Headers: []*RecordHeader{
+			{Key: []byte(RetryHeaderAttempt), Value: []byte("2")},
+			{Key: []byte(RetryHeaderOriginalTopic), Value: []byte("orders")},
+			{Key: []byte(RetryHeaderOriginalPartition), Value: []byte("3")},
+			{Key: []byte(RetryHeaderOriginalOffset), Value: []byte("8")},
+			{Key: []byte(RetryHeaderError), Value: []byte("failed")},
+		},
+	}
+	close(claim.messages)
+
+	done := make(chan error, 1)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+
+	forwarded := <-producer.input
+	require.Equal(t, "orders.dlq", forwarded.Topic)
+	require.Nil(t, forwarded.Key)
+	require.Equal(t, map[string]string{
+		RetryHeaderAttempt:           "3",
+		RetryHeaderOriginalTopic:     "orders",
+		RetryHeaderOriginalPartition: "3",
+		RetryHeaderOriginalOffset:    "8",
+		RetryHeaderError:             "still failing",
+	}, retryTestHeaders(forwarded))
+
+	forwarded.OnDelivery(forwarded, ErrOutOfBrokers)
+	err := <-done
+	require.ErrorIs(t, err, ErrOutOfBrokers)
+	require.Equal(t, int64(0), session.markedOffset())
+}
+
+func TestRetryHandlerDelaysRetries(t *testing.T) {
+	producer := newRetryTestProducer()
+	defer func() { _ = producer.Close() }()
+	group := &retryTestGroup{}
+	topics := RetryTopics{Delays: []time.Duration{100 * time.Millisecond}}
+
+	var processed time.Time
+	handler := NewRetryHandler(group, producer, topics, func(ConsumerGroupSession, *ConsumerMessage) error {
+		processed = time.Now()
+		return nil
+	})
+
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 1)}
+	produced := time.Now()
+	claim.messages <- &ConsumerMessage{
+		Topic: "orders.retry.100ms", Partition: 1, Offset: 4, Timestamp: produced,
+		Headers: []*RecordHeader{{Key: []byte(RetryHeaderAttempt), Value: []byte("1")}},
+	}
+	close(claim.messages)
+
+	require.NoError(t, handler.ConsumeClaim(session, claim))
+	require.GreaterOrEqual(t, processed.Sub(produced), 100*time.Millisecond)
+	require.Equal(t, int64(5), session.markedOffset())
+
+	partitions := []map[string][]int32{{"orders.retry.100ms": {1}}}
+	require.Equal(t, partitions, group.paused)
+	require.Equal(t, partitions, group.resumed)
+}
+
+func TestRetryHandlerWithoutReturnSuccesses(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()).
+			SetLeader("orders.dlq", 0, seedBroker.BrokerID()),
+		"ProduceRequest": NewMockProduceResponse(t),
+	})
+
+	config := NewTestConfig()
+	config.Version = V0_11_0_0
+	config.Producer.Return.Successes = false
+	producer, err := NewAsyncProducer([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, producer)
+
+	handler := NewRetryHandler(&retryTestGroup{}, producer, RetryTopics{}, func(ConsumerGroupSession, *ConsumerMessage) error {
+		return errors.New("failed")
+	})
+
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 1)}
+	claim.messages <- &ConsumerMessage{Topic: "orders", Offset: 3, Value: []byte("bad")}
+	close(claim.messages)
+
+	done := make(chan error, 1)
+	go func() { done <- handler.ConsumeClaim(session, claim) }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the forwarded message was never acknowledged")
+	}
+	require.Equal(t, int64(4), session.markedOffset())
+}
+
+func TestFormatRetryDelay(t *testing.T) {
+	for delay, expected := range map[time.Duration]string{
+		time.Minute:             "1m",
+		10 * time.Minute:        "10m",
+		2 * time.Hour:           "2h",
+		90 * time.Minute:        "90m",
+		30 * time.Second:        "30s",
+		1500 * time.Millisecond: "1500ms",
+	} {
+		require.Equal(t, expected, formatRetryDelay(delay))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"maps"
+	"regexp"
 	"slices"
 	"sync"
 	"testing"
@@ -796,3 +797,282 @@ func TestConsumerGroupReason(t *testing.T) {
 		assert.Equal(t, "the consumer is being closed", *leaveCapture.reasons[0])
 	})
 }
+
+func TestConsumerGroupConsumeMatching(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V2_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Group.Rebalance.Retry.Max = 0
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Metadata.RefreshFrequency = 50 * time.Millisecond
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	metadata := func(topics ...string) MockResponse {
+		response := NewMockMetadataResponse(t).SetBroker(broker0.Addr(), broker0.BrokerID())
+		for _, topic := range topics {
+			response.SetLeader(topic, 0, broker0.BrokerID())
+		}
+		return response
+	}
+	handlers := map[string]MockResponse{
+		"MetadataRequest": metadata("orders-eu", "payments"),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("orders-eu", 0, OffsetOldest, 0).
+			SetOffset("orders-eu", 0, OffsetNewest, 0),
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorGroup, "my-group", broker0),
+		"HeartbeatRequest": NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": NewMockJoinGroupResponse(t).SetGroupProtocol(RangeBalanceStrategyName),
+		"SyncGroupRequest": NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{"orders-eu": {0}},
+			}),
+		"OffsetFetchRequest": NewMockOffsetFetchResponse(t).SetOffset(
+			"my-group", "orders-eu", 0, 0, "", ErrNoError,
+		).SetError(ErrNoError),
+		"FetchRequest": NewMockFetchResponse(t, 1),
+	}
+	broker0.SetHandlerByMap(handlers)
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	h := &causeHandler{causeCh: make(chan error, 1)}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- group.ConsumeMatching(ctx, regexp.MustCompile(`^orders-`), h)
+	}()
+
+	assert.Eventually(t, func() bool {
+		for _, rr := range broker0.History() {
+			if _, ok := rr.Request.(*HeartbeatRequest); ok {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "the session should start")
+
+	var joined *JoinGroupRequest
+	for _, rr := range broker0.History() {
+		if request, ok := rr.Request.(*JoinGroupRequest); ok {
+			joined = request
+		}
+	}
+	assert.NotNil(t, joined)
+	var meta ConsumerGroupMemberMetadata
+	assert.NoError(t, decode(joined.OrderedGroupProtocols[0].Metadata, &meta, nil))
+	assert.Equal(t, []string{"orders-eu"}, meta.Topics)
+
+	handlers["MetadataRequest"] = metadata("orders-eu", "orders-us", "payments")
+	broker0.SetHandlerByMap(handlers)
+
+	assert.ErrorIs(t, <-h.causeCh, ErrSessionSubscriptionChanged)
+	assert.NoError(t, <-done)
+}
+
+func TestConsumerGroupConsumeMatchingWaitsForTopics(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V2_0_0_0
+	config.Metadata.RefreshFrequency = 20 * time.Millisecond
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("payments", 0, broker0.BrokerID()),
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	err = group.ConsumeMatching(ctx, regexp.MustCompile(`^orders-`), &drainHandler{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// ackHandler acknowledges the messages of a claim in the order given by the
+// test over the acks channel, and commits after each of them.
+type ackHandler struct {
+	messages chan *ConsumerMessage
+	acks     chan int64
+	acked    chan error
+}
+
+func (*ackHandler) Setup(_ ConsumerGroupSession) error   { return nil }
+func (*ackHandler) Cleanup(_ ConsumerGroupSession) error { return nil }
+func (h *ackHandler) ConsumeClaim(sess ConsumerGroupSession, claim ConsumerGroupClaim) error {
+	received := make(map[int64]*ConsumerMessage)
+	for {
+		select {
+		case msg := <-claim.Messages():
+			received[msg.Offset] = msg
+			h.messages <- msg
+		case offset := <-h.acks:
+			err := sess.AckMessage(received[offset])
+			sess.Commit()
+			h.acked <- err
+		case <-sess.Context().Done():
+			return nil
+		}
+	}
+}
+
+func TestConsumerGroupAckMessage(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V2_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Group.Rebalance.Retry.Max = 0
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Offsets.Ack.Enable = true
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my-topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my-topic", 0, OffsetOldest, 0).
+			SetOffset("my-topic", 0, OffsetNewest, 4),
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorGroup, "my-group", broker0),
+		"HeartbeatRequest": NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": NewMockJoinGroupResponse(t).SetGroupProtocol(RangeBalanceStrategyName),
+		"SyncGroupRequest": NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{"my-topic": {0}},
+			}),
+		"OffsetFetchRequest": NewMockOffsetFetchResponse(t).SetOffset(
+			"my-group", "my-topic", 0, 0, "", ErrNoError,
+		).SetError(ErrNoError),
+		"OffsetCommitRequest": NewMockOffsetCommitResponse(t),
+		// offset 2 was compacted away
+		"FetchRequest": NewMockFetchResponse(t, 3).
+			SetMessage("my-topic", 0, 0, StringEncoder("foo")).
+			SetMessage("my-topic", 0, 1, StringEncoder("bar")).
+			SetMessage("my-topic", 0, 3, StringEncoder("baz")),
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	h := &ackHandler{
+		messages: make(chan *ConsumerMessage, 3),
+		acks:     make(chan int64),
+		acked:    make(chan error),
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- group.Consume(ctx, []string{"my-topic"}, h)
+	}()
+	for range 3 {
+		<-h.messages
+	}
+
+	lastCommit := func() int64 {
+		var offset int64 = -1
+		for _, rr := range broker0.History() {
+			if request, ok := rr.Request.(*OffsetCommitRequest); ok {
+				offset = request.blocks["my-topic"][0].offset
+			}
+		}
+		return offset
+	}
+	for _, step := range []struct {
+		ack, committed int64
+	}{
+		{ack: 3, committed: -1},
+		{ack: 0, committed: 1},
+		{ack: 1, committed: 4},
+	} {
+		h.acks <- step.ack
+		assert.NoError(t, <-h.acked)
+		assert.Equal(t, step.committed, lastCommit(), "after acknowledging offset %d", step.ack)
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestConsumerGroupLag(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V2_0_0_0
+	config.Consumer.Return.Errors = true
+	config.Consumer.Group.Rebalance.Retry.Max = 0
+	config.Consumer.Offsets.AutoCommit.Enable = false
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my-topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my-topic", 0, OffsetOldest, 0).
+			SetOffset("my-topic", 0, OffsetNewest, 3),
+		"FindCoordinatorRequest": NewMockFindCoordinatorResponse(t).
+			SetCoordinator(CoordinatorGroup, "my-group", broker0),
+		"HeartbeatRequest": NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": NewMockJoinGroupResponse(t).SetGroupProtocol(RangeBalanceStrategyName),
+		"SyncGroupRequest": NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{"my-topic": {0}},
+			}),
+		"OffsetFetchRequest": NewMockOffsetFetchResponse(t).SetOffset(
+			"my-group", "my-topic", 0, 0, "", ErrNoError,
+		).SetError(ErrNoError),
+		"FetchRequest": NewMockFetchResponse(t, 3).
+			SetMessage("my-topic", 0, 0, StringEncoder("foo")).
+			SetMessage("my-topic", 0, 1, StringEncoder("bar")).
+			SetMessage("my-topic", 0, 2, StringEncoder("baz")).
+			SetHighWaterMark("my-topic", 0, 5),
+	})
+
+	group, err := NewConsumerGroup([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	defer func() { _ = group.Close() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- group.Consume(ctx, []string{"my-topic"}, &drainHandler{})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return group.Lag()["my-topic"][0].Messages == 2
+	}, 5*time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Empty(t, group.Lag(), "no partition is claimed once the session is released")
+}
+
+func TestConsumerGroupLagOtherConsumer(t *testing.T) {
+	group := &consumerGroup{config: NewTestConfig(), consumer: struct{ Consumer }{}}
+	assert.Empty(t, group.Lag())
+}
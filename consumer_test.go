@@ -154,6 +154,126 @@ func TestConsumerMessageWithKey(t *testing.T) {
 	broker0.Close()
 }
 
+func TestConsumerSeek(t *testing.T) {
+	// Given
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	mockFetchResponse := NewMockFetchResponse(t, 1)
+	for i := range int64(20) {
+		mockFetchResponse.SetMessage("my_topic", 0, i, testMsg)
+	}
+	mockFetchResponse.SetHighWaterMark("my_topic", 0, 20)
+
+	timestamp := time.Now().Add(-time.Hour)
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my_topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my_topic", 0, OffsetOldest, 0).
+			SetOffset("my_topic", 0, OffsetNewest, 20).
+			SetOffset("my_topic", 0, timestamp.UnixMilli(), 15),
+		"FetchRequest": mockFetchResponse,
+	})
+
+	config := NewTestConfig()
+	config.Version = V2_7_0_0
+	master, err := NewConsumer([]string{broker0.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, master)
+
+	consumer, err := master.ConsumePartition("my_topic", 0, 10)
+	require.NoError(t, err)
+
+	expectOffsets := func(from, to int64) {
+		t.Helper()
+		for offset := from; offset < to; offset++ {
+			select {
+			case message := <-consumer.Messages():
+				assertMessageOffset(t, message, offset)
+			case err := <-consumer.Errors():
+				t.Fatal(err)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for offset %d", offset)
+			}
+		}
+	}
+
+	// When / Then
+	expectOffsets(10, 13)
+
+	require.NoError(t, consumer.Seek(2))
+	expectOffsets(2, 5)
+
+	require.NoError(t, consumer.SeekToTimestamp(timestamp))
+	expectOffsets(15, 17)
+
+	require.ErrorIs(t, consumer.Seek(100), ErrOffsetOutOfRange)
+	expectOffsets(17, 18)
+
+	safeClose(t, consumer)
+	require.ErrorIs(t, consumer.Seek(OffsetOldest), ErrClosedPartitionConsumer)
+}
+
+func TestConsumerSeekAcks(t *testing.T) {
+	// Given
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	mockFetchResponse := NewMockFetchResponse(t, 1)
+	for i := range int64(20) {
+		mockFetchResponse.SetMessage("my_topic", 0, i, testMsg)
+	}
+	mockFetchResponse.SetHighWaterMark("my_topic", 0, 20)
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my_topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my_topic", 0, OffsetOldest, 0).
+			SetOffset("my_topic", 0, OffsetNewest, 20),
+		"FetchRequest": mockFetchResponse,
+	})
+
+	config := NewTestConfig()
+	config.Version = V2_7_0_0
+	master, err := NewConsumer([]string{broker0.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, master)
+
+	acks := newAckTracker(10)
+	consumer, err := master.(*consumer).consumePartition("my_topic", 0, 0, partitionConsumerOptions{acks: acks})
+	require.NoError(t, err)
+	defer safeClose(t, consumer)
+
+	consumeAndAck := func(from, to int64) int64 {
+		t.Helper()
+		var committable int64
+		for offset := from; offset < to; offset++ {
+			select {
+			case message := <-consumer.Messages():
+				assertMessageOffset(t, message, offset)
+				committable, err = acks.ack(message.Offset)
+				require.NoError(t, err)
+			case err := <-consumer.Errors():
+				t.Fatal(err)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for offset %d", offset)
+			}
+		}
+		return committable
+	}
+
+	// When
+	consumeAndAck(0, 3)
+	require.NoError(t, consumer.Seek(15))
+
+	// Then the messages skipped by the seek do not hold the commits back
+	require.Equal(t, int64(18), consumeAndAck(15, 18))
+}
+
 func TestPauseResumeConsumption(t *testing.T) {
 	// Given
 	broker0 := NewMockBroker(t, 0)
@@ -836,12 +956,76 @@ func TestConsumeMessageWithSessionIDs(t *testing.T) {
 	safeClose(t, master)
 	broker0.Close()
 
+	// the broker did not create a session so every fetch asks for a new one
 	fetchReq := broker0.History()[3].Request.(*FetchRequest)
-	if fetchReq.SessionID != 0 || fetchReq.SessionEpoch != -1 {
-		t.Error("Expected session ID to be zero & Epoch to be -1")
+	if fetchReq.SessionID != 0 || fetchReq.SessionEpoch != 0 {
+		t.Error("Expected session ID to be zero & Epoch to be 0")
 	}
 }
 
+func TestConsumeMessagesWithIncrementalFetchSession(t *testing.T) {
+	cfg := NewTestConfig()
+	cfg.Version = V2_1_0_0
+
+	broker0 := NewMockBroker(t, 0)
+	broker0.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": func(req *request) encoderWithHeader {
+			return NewMockMetadataResponse(t).
+				SetBroker(broker0.Addr(), broker0.BrokerID()).
+				SetLeader("my_topic", 0, broker0.BrokerID()).
+				For(req.body)
+		},
+		"OffsetRequest": func(req *request) encoderWithHeader {
+			return NewMockOffsetResponse(t).
+				SetOffset("my_topic", 0, OffsetNewest, 1234).
+				SetOffset("my_topic", 0, OffsetOldest, 0).
+				For(req.body)
+		},
+		"FetchRequest": func(req *request) encoderWithHeader {
+			res := NewMockFetchResponse(t, 1).
+				SetMessage("my_topic", 0, 1, testMsg).
+				For(req.body).(*FetchResponse)
+			res.SessionID = 42
+			return res
+		},
+	})
+
+	master, err := NewConsumer([]string{broker0.Addr()}, cfg)
+	require.NoError(t, err)
+	consumer, err := master.ConsumePartition("my_topic", 0, 1)
+	require.NoError(t, err)
+
+	assertMessageOffset(t, <-consumer.Messages(), 1)
+
+	var fetches []*FetchRequest
+	require.Eventually(t, func() bool {
+		fetches = fetches[:0]
+		for _, rr := range broker0.History() {
+			if fetch, ok := rr.Request.(*FetchRequest); ok {
+				fetches = append(fetches, fetch)
+			}
+		}
+		return len(fetches) >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	safeClose(t, consumer)
+	safeClose(t, master)
+	broker0.Close()
+
+	// full fetch creating the session
+	require.Equal(t, int32(0), fetches[0].SessionID)
+	require.Equal(t, int32(0), fetches[0].SessionEpoch)
+	require.Equal(t, int64(1), fetches[0].blocks["my_topic"][0].fetchOffset)
+	// the fetch offset moved forward so the partition is sent again
+	require.Equal(t, int32(42), fetches[1].SessionID)
+	require.Equal(t, int32(1), fetches[1].SessionEpoch)
+	require.Equal(t, int64(2), fetches[1].blocks["my_topic"][0].fetchOffset)
+	// nothing changed since
+	require.Equal(t, int32(42), fetches[2].SessionID)
+	require.Equal(t, int32(2), fetches[2].SessionEpoch)
+	require.Empty(t, fetches[2].blocks)
+}
+
 func TestConsumeMessagesFromReadReplica(t *testing.T) {
 	withRefreshFrequency := func(frequency time.Duration) func(*Config) {
 		return func(cfg *Config) {
@@ -1163,6 +1347,129 @@ func TestConsumeMessagesTrackLeader(t *testing.T) {
 	leader2.Close()
 }
 
+// runLogTruncationTest consumes offsets 1 and 2 (leader epoch 0) from
+// leader1, then moves leadership to leader2 at epoch 1 whose log for epoch 0
+// ends at offset 2, i.e. offset 2 was truncated away.
+func runLogTruncationTest(t *testing.T, resetInvalidOffsets bool) (PartitionConsumer, func()) {
+	t.Helper()
+
+	cfg := NewTestConfig()
+	cfg.ClientID = t.Name()
+	cfg.Consumer.Retry.Backoff = 0
+	cfg.Consumer.Return.Errors = true
+	cfg.Consumer.Group.ResetInvalidOffsets = resetInvalidOffsets
+	cfg.Version = V2_1_0_0
+
+	leader1 := NewMockBroker(t, 1)
+	leader2 := NewMockBroker(t, 2)
+
+	var leaderID atomic.Int32
+	leaderID.Store(leader1.BrokerID())
+
+	metadataHandler := func(req *request) encoderWithHeader {
+		res := &MetadataResponse{Version: req.body.version()}
+		res.AddBroker(leader1.Addr(), leader1.BrokerID())
+		res.AddBroker(leader2.Addr(), leader2.BrokerID())
+		replicas := []int32{leader1.BrokerID(), leader2.BrokerID()}
+		res.AddTopicPartition("my_topic", 0, leaderID.Load(), replicas, replicas, nil, ErrNoError)
+		res.Topics[0].Partitions[0].LeaderEpoch = leaderID.Load() - 1
+		return res
+	}
+	offsetHandler := func(req *request) encoderWithHeader {
+		return NewMockOffsetResponse(t).
+			SetOffset("my_topic", 0, OffsetNewest, 1234).
+			SetOffset("my_topic", 0, OffsetOldest, 0).
+			For(req.body)
+	}
+	fetchHandler := func(brokerID int32, offsets ...int64) requestHandlerFunc {
+		return func(req *request) encoderWithHeader {
+			fetchRequest := req.body.(*FetchRequest)
+			res := &FetchResponse{Version: fetchRequest.Version}
+			if leaderID.Load() != brokerID {
+				res.AddError("my_topic", 0, ErrNotLeaderForPartition)
+				return res
+			}
+			for _, offset := range offsets {
+				if offset >= fetchRequest.blocks["my_topic"][0].fetchOffset {
+					res.AddRecord("my_topic", 0, nil, testMsg, offset)
+				}
+			}
+			res.AddError("my_topic", 0, ErrNoError)
+			return res
+		}
+	}
+
+	leader1.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": metadataHandler,
+		"OffsetRequest":   offsetHandler,
+		"FetchRequest":    fetchHandler(leader1.BrokerID(), 1, 2),
+	})
+	leader2.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": metadataHandler,
+		"FetchRequest":    fetchHandler(leader2.BrokerID(), 2),
+		"OffsetForLeaderEpochRequest": func(req *request) encoderWithHeader {
+			offsetForLeaderEpochRequest := req.body.(*OffsetForLeaderEpochRequest)
+			require.Equal(t, int32(1), offsetForLeaderEpochRequest.Topics[0].Partitions[0].CurrentLeaderEpoch)
+			require.Equal(t, int32(0), offsetForLeaderEpochRequest.Topics[0].Partitions[0].LeaderEpoch)
+			return NewMockOffsetForLeaderEpochResponse(t).
+				SetEndOffset("my_topic", 0, 0, 2).
+				For(req.body)
+		},
+	})
+
+	client, err := NewClient([]string{leader1.Addr()}, cfg)
+	require.NoError(t, err)
+	consumer, err := NewConsumerFromClient(client)
+	require.NoError(t, err)
+	pConsumer, err := consumer.ConsumePartition("my_topic", 0, 1)
+	require.NoError(t, err)
+
+	assertMessageOffset(t, <-pConsumer.Messages(), 1)
+	assertMessageOffset(t, <-pConsumer.Messages(), 2)
+
+	// move leadership to leader2, leader1 now answers fetches with
+	// NOT_LEADER_FOR_PARTITION which triggers a redispatch
+	leaderID.Store(leader2.BrokerID())
+
+	return pConsumer, func() {
+		safeClose(t, consumer)
+		safeClose(t, client)
+		leader1.Close()
+		leader2.Close()
+	}
+}
+
+func TestConsumerLogTruncationShutsDown(t *testing.T) {
+	pConsumer, cleanup := runLogTruncationTest(t, false)
+	defer cleanup()
+
+	consErr := <-pConsumer.Errors()
+	var truncation *LogTruncationError
+	require.ErrorAs(t, consErr.Err, &truncation)
+	require.Equal(t, int64(3), truncation.FetchOffset)
+	require.Equal(t, int64(2), truncation.TruncationOffset)
+	require.Equal(t, int32(0), truncation.LeaderEpoch)
+
+	if _, ok := <-pConsumer.Messages(); ok {
+		t.Error("Expected the consumer to shut down")
+	}
+	safeClose(t, pConsumer)
+}
+
+func TestConsumerLogTruncationResets(t *testing.T) {
+	pConsumer, cleanup := runLogTruncationTest(t, true)
+	defer cleanup()
+
+	consErr := <-pConsumer.Errors()
+	var truncation *LogTruncationError
+	require.ErrorAs(t, consErr.Err, &truncation)
+	require.Equal(t, int64(2), truncation.TruncationOffset)
+
+	// consumption resumes from the truncation offset on the new leader
+	assertMessageOffset(t, <-pConsumer.Messages(), 2)
+	safeClose(t, pConsumer)
+}
+
 // It is fine if offsets of fetched messages are not sequential (although
 // strictly increasing!).
 func TestConsumerNonSequentialOffsets(t *testing.T) {
@@ -2198,8 +2505,8 @@ func Test_partitionConsumer_parseResponseEmptyBatch(t *testing.T) {
 	if got != nil {
 		t.Errorf("partitionConsumer.parseResponse() should be nil, got %v", got)
 	}
-	if child.offset != 6 {
-		t.Errorf("child.offset should be recordsNextOffset: %d, got %d", lrbOffset, child.offset)
+	if child.offset.Load() != 6 {
+		t.Errorf("child.offset should be recordsNextOffset: %d, got %d", lrbOffset, child.offset.Load())
 	}
 }
 
@@ -2581,3 +2888,163 @@ func TestConsumerAbortNoGoroutineLeak(t *testing.T) {
 		}
 	})
 }
+
+func TestConsumerLag(t *testing.T) {
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my_topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my_topic", 0, OffsetOldest, 0).
+			SetOffset("my_topic", 0, OffsetNewest, 3),
+		"FetchRequest": NewMockFetchResponse(t, 3).
+			SetMessage("my_topic", 0, 0, testMsg).
+			SetMessage("my_topic", 0, 1, testMsg).
+			SetMessage("my_topic", 0, 2, testMsg).
+			SetHighWaterMark("my_topic", 0, 10),
+	})
+
+	config := NewTestConfig()
+	config.ChannelBufferSize = 0
+	master, err := NewConsumer([]string{broker0.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, master)
+
+	pc, err := master.ConsumePartition("my_topic", 0, 0)
+	require.NoError(t, err)
+
+	gauge := func() any {
+		return config.MetricRegistry.Get("consumer-lag-for-partition-my_topic-0")
+	}
+	for i := range int64(3) {
+		assertMessageOffset(t, <-pc.Messages(), i)
+	}
+	require.Eventually(t, func() bool { return pc.Lag().Messages == 7 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, int64(7), gauge().(metrics.Gauge).Snapshot().Value())
+	require.Equal(t, map[string]map[int32]ConsumerLag{"my_topic": {0: pc.Lag()}}, master.(*consumer).lags())
+
+	safeClose(t, pc)
+	require.Nil(t, gauge(), "the lag metrics must be unregistered once the partition consumer is closed")
+
+	// the partition is consumed again as soon as it is released, while the
+	// previous partition consumer may still be shutting down
+	pc, err = master.ConsumePartition("my_topic", 0, 0)
+	require.NoError(t, err)
+	closed := gauge()
+	pc.AsyncClose()
+	var reopened PartitionConsumer
+	require.Eventually(t, func() bool {
+		reopened, err = master.ConsumePartition("my_topic", 0, 0)
+		return err == nil
+	}, 5*time.Second, time.Millisecond)
+	defer safeClose(t, reopened)
+	require.NotSame(t, closed, gauge(), "the lag metrics must be unregistered before the partition is released")
+	for range pc.Errors() {
+	}
+	require.NotNil(t, gauge(), "the lag metrics of the new partition consumer must stay registered")
+}
+
+func TestPartitionConsumerTimeLag(t *testing.T) {
+	child := &partitionConsumer{conf: NewTestConfig()}
+	child.highWaterMarkOffset.Store(5)
+
+	child.handOver(&ConsumerMessage{Offset: 2, Timestamp: time.Now().Add(-time.Minute)})
+	lag := child.Lag()
+	require.Equal(t, int64(2), lag.Messages)
+	require.InDelta(t, time.Minute, lag.Time, float64(time.Second))
+
+	child.handOver(&ConsumerMessage{Offset: 4, Timestamp: time.Now().Add(-time.Second)})
+	require.Equal(t, ConsumerLag{}, child.Lag(), "there is no time lag without lag")
+}
+
+func TestConsumerMaxBufferedBytes(t *testing.T) {
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetLeader("my_topic", 0, broker0.BrokerID()),
+		"OffsetRequest": NewMockOffsetResponse(t).
+			SetOffset("my_topic", 0, OffsetOldest, 0).
+			SetOffset("my_topic", 0, OffsetNewest, 3),
+		"FetchRequest": NewMockFetchResponse(t, 3).
+			SetMessage("my_topic", 0, 0, testMsg).
+			SetMessage("my_topic", 0, 1, testMsg).
+			SetMessage("my_topic", 0, 2, testMsg),
+	})
+
+	config := NewTestConfig()
+	config.Consumer.MaxBufferedBytes = 1
+	master, err := NewConsumer([]string{broker0.Addr()}, config)
+	require.NoError(t, err)
+	defer safeClose(t, master)
+
+	pc, err := master.ConsumePartition("my_topic", 0, 0)
+	require.NoError(t, err)
+	defer safeClose(t, pc)
+
+	fetches := func() int {
+		n := 0
+		for _, rr := range broker0.History() {
+			if _, ok := rr.Request.(*FetchRequest); ok {
+				n++
+			}
+		}
+		return n
+	}
+	gauge := config.MetricRegistry.Get("consumer-buffered-bytes").(metrics.Gauge)
+
+	// the reader holds the messages waiting in the Messages channel, which
+	// has room for all of them
+	require.Eventually(t, func() bool { return len(pc.Messages()) == 3 }, 5*time.Second, time.Millisecond)
+	require.Never(t, func() bool { return fetches() > 1 }, 100*time.Millisecond, time.Millisecond,
+		"the partition is not fetched from while its messages are not received")
+	buffered := gauge.Snapshot().Value()
+
+	msg := <-pc.Messages()
+	assertMessageOffset(t, msg, 0)
+	size := int64(consumerMessageSize(msg))
+	require.Equal(t, 3*size, buffered)
+	require.Eventually(t, func() bool { return gauge.Snapshot().Value() == 2*size }, 5*time.Second, time.Millisecond,
+		"the message is released once received")
+	require.Equal(t, 1, fetches())
+
+	assertMessageOffset(t, <-pc.Messages(), 1)
+	assertMessageOffset(t, <-pc.Messages(), 2)
+	require.Eventually(t, func() bool { return fetches() > 1 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, int64(0), gauge.Snapshot().Value())
+}
+
+func TestPartitionConsumerBufferedBytes(t *testing.T) {
+	config := NewTestConfig()
+	config.Consumer.MaxBufferedBytes = 100
+	c := &consumer{}
+	c.childCount.Store(2)
+	child := &partitionConsumer{conf: config, consumer: c, messages: make(chan *ConsumerMessage, 2)}
+
+	msgs := []*ConsumerMessage{
+		{Value: make([]byte, 30)},
+		{Value: make([]byte, 40)},
+	}
+	child.bufferParsed(msgs)
+	require.Equal(t, int64(70), child.bufferedBytes())
+	require.True(t, child.overBudget(), "the share of the partition is 50 bytes")
+
+	child.messages <- msgs[0]
+	child.bufferSent(int64(consumerMessageSize(msgs[0])))
+	require.Equal(t, int64(70), child.bufferedBytes(), "messages waiting in the channel are buffered")
+	require.True(t, child.overBudget())
+
+	<-child.messages
+	require.Equal(t, int64(40), child.bufferedBytes(), "messages received are released")
+	require.False(t, child.overBudget())
+	require.Equal(t, int64(40), c.buffered.Load())
+
+	child.bufferFlushed()
+	require.Equal(t, int64(0), child.bufferedBytes())
+	require.Equal(t, int64(0), c.buffered.Load())
+}
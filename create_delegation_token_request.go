@@ -0,0 +1,183 @@
+package sarama
+
+// DelegationTokenPrincipal identifies the owner, requester or an allowed
+// renewer of a delegation token.
+type DelegationTokenPrincipal struct {
+	// PrincipalType contains the type of the principal, e.g. "User".
+	PrincipalType string
+	// PrincipalName contains the name of the principal.
+	PrincipalName string
+}
+
+func (p *DelegationTokenPrincipal) encode(pe packetEncoder) error {
+	if err := pe.putString(p.PrincipalType); err != nil {
+		return err
+	}
+	if err := pe.putString(p.PrincipalName); err != nil {
+		return err
+	}
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (p *DelegationTokenPrincipal) decode(pd packetDecoder) (err error) {
+	if p.PrincipalType, err = pd.getString(); err != nil {
+		return err
+	}
+	if p.PrincipalName, err = pd.getString(); err != nil {
+		return err
+	}
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func encodeDelegationTokenPrincipals(pe packetEncoder, principals []DelegationTokenPrincipal) error {
+	if err := pe.putArrayLength(len(principals)); err != nil {
+		return err
+	}
+	for i := range principals {
+		if err := principals[i].encode(pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeDelegationTokenPrincipals(pd packetDecoder) ([]DelegationTokenPrincipal, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, nil
+	}
+	principals := make([]DelegationTokenPrincipal, n)
+	for i := range n {
+		if err := principals[i].decode(pd); err != nil {
+			return nil, err
+		}
+	}
+	return principals, nil
+}
+
+// CreateDelegationTokenRequest asks the broker to issue a delegation token
+// (KIP-48).
+type CreateDelegationTokenRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// OwnerPrincipalType contains the principal type of the owner of the
+	// token, if null this defaults to the token request principal (v3+).
+	OwnerPrincipalType *string
+	// OwnerPrincipalName contains the principal name of the owner of the
+	// token, if null this defaults to the token request principal (v3+).
+	OwnerPrincipalName *string
+	// Renewers contains the principals which are allowed to renew this token.
+	Renewers []DelegationTokenPrincipal
+	// MaxLifetimeMs contains the maximum lifetime of the token in
+	// milliseconds, or -1 to use the broker's delegation.token.max.lifetime.ms.
+	MaxLifetimeMs int64
+}
+
+// NewCreateDelegationTokenRequest returns a CreateDelegationTokenRequest using
+// the highest protocol version supported by the given Kafka version.
+func NewCreateDelegationTokenRequest(version KafkaVersion) *CreateDelegationTokenRequest {
+	r := &CreateDelegationTokenRequest{MaxLifetimeMs: -1}
+	switch {
+	case version.IsAtLeast(V3_3_0_0):
+		// Version 3 adds the owner principal.
+		r.Version = 3
+	case version.IsAtLeast(V2_4_0_0):
+		// Version 2 is the first flexible version.
+		r.Version = 2
+	case version.IsAtLeast(V2_0_0_0):
+		// Version 1 is the same as version 0.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *CreateDelegationTokenRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *CreateDelegationTokenRequest) encode(pe packetEncoder) error {
+	if r.Version >= 3 {
+		if err := pe.putNullableString(r.OwnerPrincipalType); err != nil {
+			return err
+		}
+		if err := pe.putNullableString(r.OwnerPrincipalName); err != nil {
+			return err
+		}
+	}
+
+	if err := encodeDelegationTokenPrincipals(pe, r.Renewers); err != nil {
+		return err
+	}
+	pe.putInt64(r.MaxLifetimeMs)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *CreateDelegationTokenRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.Version >= 3 {
+		if r.OwnerPrincipalType, err = pd.getNullableString(); err != nil {
+			return err
+		}
+		if r.OwnerPrincipalName, err = pd.getNullableString(); err != nil {
+			return err
+		}
+	}
+
+	if r.Renewers, err = decodeDelegationTokenPrincipals(pd); err != nil {
+		return err
+	}
+	if r.MaxLifetimeMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *CreateDelegationTokenRequest) key() int16 {
+	return apiKeyCreateDelegationToken
+}
+
+func (r *CreateDelegationTokenRequest) version() int16 {
+	return r.Version
+}
+
+func (r *CreateDelegationTokenRequest) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (r *CreateDelegationTokenRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 3
+}
+
+func (r *CreateDelegationTokenRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *CreateDelegationTokenRequest) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *CreateDelegationTokenRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 3:
+		return V3_3_0_0
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	createDelegationTokenRequestV0 = []byte{
+		0, 0, 0, 1, // Renewers
+		0, 4, 'U', 's', 'e', 'r', // PrincipalType
+		0, 3, 'b', 'o', 'b', // PrincipalName
+		255, 255, 255, 255, 255, 255, 255, 255, // MaxLifetimeMs
+	}
+
+	createDelegationTokenRequestV3 = []byte{
+		5, 'U', 's', 'e', 'r', // OwnerPrincipalType
+		6, 'a', 'l', 'i', 'c', 'e', // OwnerPrincipalName
+		2,                     // Renewers
+		5, 'U', 's', 'e', 'r', // PrincipalType
+		4, 'b', 'o', 'b', // PrincipalName
+		0,                               // empty tagged fields
+		0, 0, 0, 0, 0, 0x36, 0xee, 0x80, // MaxLifetimeMs
+		0, // empty tagged fields
+	}
+)
+
+func TestCreateDelegationTokenRequest(t *testing.T) {
+	request := NewCreateDelegationTokenRequest(V1_1_0_0)
+	request.Renewers = []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "bob"}}
+	testRequest(t, "v0", request, createDelegationTokenRequestV0)
+
+	ownerType, ownerName := "User", "alice"
+	request = NewCreateDelegationTokenRequest(V3_3_0_0)
+	if request.Version != 3 {
+		t.Fatalf("expected version 3, got %d", request.Version)
+	}
+	request.OwnerPrincipalType = &ownerType
+	request.OwnerPrincipalName = &ownerName
+	request.Renewers = []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "bob"}}
+	request.MaxLifetimeMs = 3600000
+	testRequest(t, "v3", request, createDelegationTokenRequestV3)
+}
@@ -0,0 +1,152 @@
+package sarama
+
+import "time"
+
+// CreateDelegationTokenResponse is the response to a
+// CreateDelegationTokenRequest.
+type CreateDelegationTokenResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// Err contains the top-level error, or zero if there was no error.
+	Err KError
+	// Owner contains the principal that owns the token.
+	Owner DelegationTokenPrincipal
+	// TokenRequester contains the principal that requested the token (v3+).
+	TokenRequester DelegationTokenPrincipal
+	// IssueTimestampMs contains when the token was issued.
+	IssueTimestampMs int64
+	// ExpiryTimestampMs contains when the token expires.
+	ExpiryTimestampMs int64
+	// MaxTimestampMs contains the maximum lifetime of the token.
+	MaxTimestampMs int64
+	// TokenID contains the token's ID.
+	TokenID string
+	// HMAC contains the HMAC of the token.
+	HMAC []byte
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+}
+
+func (r *CreateDelegationTokenResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *CreateDelegationTokenResponse) encode(pe packetEncoder) error {
+	pe.putKError(r.Err)
+	if err := pe.putString(r.Owner.PrincipalType); err != nil {
+		return err
+	}
+	if err := pe.putString(r.Owner.PrincipalName); err != nil {
+		return err
+	}
+	if r.Version >= 3 {
+		if err := pe.putString(r.TokenRequester.PrincipalType); err != nil {
+			return err
+		}
+		if err := pe.putString(r.TokenRequester.PrincipalName); err != nil {
+			return err
+		}
+	}
+	pe.putInt64(r.IssueTimestampMs)
+	pe.putInt64(r.ExpiryTimestampMs)
+	pe.putInt64(r.MaxTimestampMs)
+	if err := pe.putString(r.TokenID); err != nil {
+		return err
+	}
+	if err := pe.putBytes(r.HMAC); err != nil {
+		return err
+	}
+	pe.putDurationMs(r.ThrottleTime)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *CreateDelegationTokenResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+	if r.Owner.PrincipalType, err = pd.getString(); err != nil {
+		return err
+	}
+	if r.Owner.PrincipalName, err = pd.getString(); err != nil {
+		return err
+	}
+	if r.Version >= 3 {
+		if r.TokenRequester.PrincipalType, err = pd.getString(); err != nil {
+			return err
+		}
+		if r.TokenRequester.PrincipalName, err = pd.getString(); err != nil {
+			return err
+		}
+	}
+	if r.IssueTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if r.ExpiryTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if r.MaxTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if r.TokenID, err = pd.getString(); err != nil {
+		return err
+	}
+	if r.HMAC, err = pd.getBytes(); err != nil {
+		return err
+	}
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *CreateDelegationTokenResponse) key() int16 {
+	return apiKeyCreateDelegationToken
+}
+
+func (r *CreateDelegationTokenResponse) version() int16 {
+	return r.Version
+}
+
+func (r *CreateDelegationTokenResponse) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 1
+	}
+	return 0
+}
+
+func (r *CreateDelegationTokenResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 3
+}
+
+func (r *CreateDelegationTokenResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *CreateDelegationTokenResponse) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *CreateDelegationTokenResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 3:
+		return V3_3_0_0
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
+
+func (r *CreateDelegationTokenResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,51 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	createDelegationTokenResponseV0 = []byte{
+		0, 0, // ErrorCode
+		0, 4, 'U', 's', 'e', 'r', // PrincipalType
+		0, 5, 'a', 'l', 'i', 'c', 'e', // PrincipalName
+		0, 0, 0, 0, 0, 0, 0x03, 0xe8, // IssueTimestampMs
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestampMs
+		0, 0, 0, 0, 0, 0, 0x0b, 0xb8, // MaxTimestampMs
+		0, 2, 'i', 'd', // TokenId
+		0, 0, 0, 2, 1, 2, // Hmac
+		0, 0, 0, 0, // ThrottleTimeMs
+	}
+
+	createDelegationTokenResponseV3 = []byte{
+		0, 0, // ErrorCode
+		5, 'U', 's', 'e', 'r', // PrincipalType
+		6, 'a', 'l', 'i', 'c', 'e', // PrincipalName
+		5, 'U', 's', 'e', 'r', // TokenRequesterPrincipalType
+		4, 'b', 'o', 'b', // TokenRequesterPrincipalName
+		0, 0, 0, 0, 0, 0, 0x03, 0xe8, // IssueTimestampMs
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestampMs
+		0, 0, 0, 0, 0, 0, 0x0b, 0xb8, // MaxTimestampMs
+		3, 'i', 'd', // TokenId
+		3, 1, 2, // Hmac
+		0, 0, 0, 0, // ThrottleTimeMs
+		0, // empty tagged fields
+	}
+)
+
+func TestCreateDelegationTokenResponse(t *testing.T) {
+	response := &CreateDelegationTokenResponse{
+		Version:           0,
+		Owner:             DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+		IssueTimestampMs:  1000,
+		ExpiryTimestampMs: 2000,
+		MaxTimestampMs:    3000,
+		TokenID:           "id",
+		HMAC:              []byte{1, 2},
+	}
+	testResponse(t, "v0", response, createDelegationTokenResponseV0)
+
+	response.Version = 3
+	response.TokenRequester = DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "bob"}
+	testResponse(t, "v3", response, createDelegationTokenResponseV3)
+}
@@ -0,0 +1,97 @@
+package sarama
+
+// DescribeDelegationTokenRequest asks the broker to describe the delegation
+// tokens owned by, or renewable by, the given principals.
+type DescribeDelegationTokenRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// Owners contains the principals whose tokens should be described, or
+	// nil to describe all the tokens the requester is allowed to see.
+	Owners []DelegationTokenPrincipal
+}
+
+// NewDescribeDelegationTokenRequest returns a DescribeDelegationTokenRequest
+// using the highest protocol version supported by the given Kafka version.
+func NewDescribeDelegationTokenRequest(version KafkaVersion) *DescribeDelegationTokenRequest {
+	r := &DescribeDelegationTokenRequest{}
+	switch {
+	case version.IsAtLeast(V3_3_0_0):
+		// Version 3 adds the token requester to the response.
+		r.Version = 3
+	case version.IsAtLeast(V2_4_0_0):
+		// Version 2 is the first flexible version.
+		r.Version = 2
+	case version.IsAtLeast(V2_0_0_0):
+		// Version 1 is the same as version 0.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *DescribeDelegationTokenRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *DescribeDelegationTokenRequest) encode(pe packetEncoder) error {
+	if r.Owners == nil {
+		if err := pe.putArrayLength(-1); err != nil {
+			return err
+		}
+	} else if err := encodeDelegationTokenPrincipals(pe, r.Owners); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeDelegationTokenRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.Owners, err = decodeDelegationTokenPrincipals(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeDelegationTokenRequest) key() int16 {
+	return apiKeyDescribeDelegationToken
+}
+
+func (r *DescribeDelegationTokenRequest) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeDelegationTokenRequest) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (r *DescribeDelegationTokenRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 3
+}
+
+func (r *DescribeDelegationTokenRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeDelegationTokenRequest) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *DescribeDelegationTokenRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 3:
+		return V3_3_0_0
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	describeDelegationTokenRequestV0 = []byte{
+		255, 255, 255, 255, // Owners
+	}
+
+	describeDelegationTokenRequestV2 = []byte{
+		2,                     // Owners
+		5, 'U', 's', 'e', 'r', // PrincipalType
+		6, 'a', 'l', 'i', 'c', 'e', // PrincipalName
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestDescribeDelegationTokenRequest(t *testing.T) {
+	request := NewDescribeDelegationTokenRequest(V1_1_0_0)
+	testRequest(t, "v0", request, describeDelegationTokenRequestV0)
+
+	request = NewDescribeDelegationTokenRequest(V2_4_0_0)
+	if request.Version != 2 {
+		t.Fatalf("expected version 2, got %d", request.Version)
+	}
+	request.Owners = []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "alice"}}
+	testRequest(t, "v2", request, describeDelegationTokenRequestV2)
+}
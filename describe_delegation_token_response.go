@@ -0,0 +1,201 @@
+package sarama
+
+import "time"
+
+// DescribedDelegationToken describes a single delegation token.
+type DescribedDelegationToken struct {
+	// Owner contains the principal that owns the token.
+	Owner DelegationTokenPrincipal
+	// TokenRequester contains the principal that requested the token (v3+).
+	TokenRequester DelegationTokenPrincipal
+	// IssueTimestampMs contains when the token was issued.
+	IssueTimestampMs int64
+	// ExpiryTimestampMs contains when the token expires.
+	ExpiryTimestampMs int64
+	// MaxTimestampMs contains the maximum lifetime of the token.
+	MaxTimestampMs int64
+	// TokenID contains the token's ID.
+	TokenID string
+	// HMAC contains the HMAC of the token.
+	HMAC []byte
+	// Renewers contains the principals which are allowed to renew the token.
+	Renewers []DelegationTokenPrincipal
+}
+
+func (t *DescribedDelegationToken) encode(pe packetEncoder, version int16) error {
+	if err := pe.putString(t.Owner.PrincipalType); err != nil {
+		return err
+	}
+	if err := pe.putString(t.Owner.PrincipalName); err != nil {
+		return err
+	}
+	if version >= 3 {
+		if err := pe.putString(t.TokenRequester.PrincipalType); err != nil {
+			return err
+		}
+		if err := pe.putString(t.TokenRequester.PrincipalName); err != nil {
+			return err
+		}
+	}
+	pe.putInt64(t.IssueTimestampMs)
+	pe.putInt64(t.ExpiryTimestampMs)
+	pe.putInt64(t.MaxTimestampMs)
+	if err := pe.putString(t.TokenID); err != nil {
+		return err
+	}
+	if err := pe.putBytes(t.HMAC); err != nil {
+		return err
+	}
+	if err := encodeDelegationTokenPrincipals(pe, t.Renewers); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *DescribedDelegationToken) decode(pd packetDecoder, version int16) (err error) {
+	if t.Owner.PrincipalType, err = pd.getString(); err != nil {
+		return err
+	}
+	if t.Owner.PrincipalName, err = pd.getString(); err != nil {
+		return err
+	}
+	if version >= 3 {
+		if t.TokenRequester.PrincipalType, err = pd.getString(); err != nil {
+			return err
+		}
+		if t.TokenRequester.PrincipalName, err = pd.getString(); err != nil {
+			return err
+		}
+	}
+	if t.IssueTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if t.ExpiryTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if t.MaxTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if t.TokenID, err = pd.getString(); err != nil {
+		return err
+	}
+	if t.HMAC, err = pd.getBytes(); err != nil {
+		return err
+	}
+	if t.Renewers, err = decodeDelegationTokenPrincipals(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+// DescribeDelegationTokenResponse is the response to a
+// DescribeDelegationTokenRequest.
+type DescribeDelegationTokenResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// Err contains the error, or zero if there was no error.
+	Err KError
+	// Tokens contains the described tokens.
+	Tokens []DescribedDelegationToken
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+}
+
+func (r *DescribeDelegationTokenResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *DescribeDelegationTokenResponse) encode(pe packetEncoder) error {
+	pe.putKError(r.Err)
+	if err := pe.putArrayLength(len(r.Tokens)); err != nil {
+		return err
+	}
+	for i := range r.Tokens {
+		if err := r.Tokens[i].encode(pe, r.Version); err != nil {
+			return err
+		}
+	}
+	pe.putDurationMs(r.ThrottleTime)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeDelegationTokenResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+	r.Tokens = make([]DescribedDelegationToken, n)
+	for i := range n {
+		if err := r.Tokens[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeDelegationTokenResponse) key() int16 {
+	return apiKeyDescribeDelegationToken
+}
+
+func (r *DescribeDelegationTokenResponse) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeDelegationTokenResponse) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 1
+	}
+	return 0
+}
+
+func (r *DescribeDelegationTokenResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 3
+}
+
+func (r *DescribeDelegationTokenResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeDelegationTokenResponse) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *DescribeDelegationTokenResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 3:
+		return V3_3_0_0
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
+
+func (r *DescribeDelegationTokenResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
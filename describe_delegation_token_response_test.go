@@ -0,0 +1,64 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	describeDelegationTokenResponseV0 = []byte{
+		0, 0, // ErrorCode
+		0, 0, 0, 1, // Tokens
+		0, 4, 'U', 's', 'e', 'r', // PrincipalType
+		0, 5, 'a', 'l', 'i', 'c', 'e', // PrincipalName
+		0, 0, 0, 0, 0, 0, 0x03, 0xe8, // IssueTimestamp
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestamp
+		0, 0, 0, 0, 0, 0, 0x0b, 0xb8, // MaxTimestamp
+		0, 2, 'i', 'd', // TokenId
+		0, 0, 0, 2, 1, 2, // Hmac
+		0, 0, 0, 1, // Renewers
+		0, 4, 'U', 's', 'e', 'r', // PrincipalType
+		0, 3, 'b', 'o', 'b', // PrincipalName
+		0, 0, 0, 0, // ThrottleTimeMs
+	}
+
+	describeDelegationTokenResponseV3 = []byte{
+		0, 0, // ErrorCode
+		2,                     // Tokens
+		5, 'U', 's', 'e', 'r', // PrincipalType
+		6, 'a', 'l', 'i', 'c', 'e', // PrincipalName
+		5, 'U', 's', 'e', 'r', // TokenRequesterPrincipalType
+		4, 'b', 'o', 'b', // TokenRequesterPrincipalName
+		0, 0, 0, 0, 0, 0, 0x03, 0xe8, // IssueTimestamp
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestamp
+		0, 0, 0, 0, 0, 0, 0x0b, 0xb8, // MaxTimestamp
+		3, 'i', 'd', // TokenId
+		3, 1, 2, // Hmac
+		2,                     // Renewers
+		5, 'U', 's', 'e', 'r', // PrincipalType
+		4, 'b', 'o', 'b', // PrincipalName
+		0,          // empty tagged fields
+		0,          // empty tagged fields
+		0, 0, 0, 0, // ThrottleTimeMs
+		0, // empty tagged fields
+	}
+)
+
+func TestDescribeDelegationTokenResponse(t *testing.T) {
+	response := &DescribeDelegationTokenResponse{
+		Version: 0,
+		Tokens: []DescribedDelegationToken{{
+			Owner:             DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "alice"},
+			IssueTimestampMs:  1000,
+			ExpiryTimestampMs: 2000,
+			MaxTimestampMs:    3000,
+			TokenID:           "id",
+			HMAC:              []byte{1, 2},
+			Renewers:          []DelegationTokenPrincipal{{PrincipalType: "User", PrincipalName: "bob"}},
+		}},
+	}
+	testResponse(t, "v0", response, describeDelegationTokenResponseV0)
+
+	response.Version = 3
+	response.Tokens[0].TokenRequester = DelegationTokenPrincipal{PrincipalType: "User", PrincipalName: "bob"}
+	testResponse(t, "v3", response, describeDelegationTokenResponseV3)
+}
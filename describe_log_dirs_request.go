@@ -10,6 +10,22 @@ type DescribeLogDirsRequest struct {
 	DescribeTopics []DescribeLogDirsRequestTopic
 }
 
+// NewDescribeLogDirsRequest returns a DescribeLogDirsRequest using the highest
+// protocol version supported by the given Kafka version.
+func NewDescribeLogDirsRequest(version KafkaVersion) *DescribeLogDirsRequest {
+	r := &DescribeLogDirsRequest{}
+	if version.IsAtLeast(V3_3_0_0) {
+		r.Version = 4
+	} else if version.IsAtLeast(V3_2_0_0) {
+		r.Version = 3
+	} else if version.IsAtLeast(V2_6_0_0) {
+		r.Version = 2
+	} else if version.IsAtLeast(V2_0_0_0) {
+		r.Version = 1
+	}
+	return r
+}
+
 func (r *DescribeLogDirsRequest) setVersion(v int16) {
 	r.Version = v
 }
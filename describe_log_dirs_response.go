@@ -268,7 +268,8 @@ type DescribeLogDirsResponsePartition struct {
 	OffsetLag int64
 
 	// True if this log is created by AlterReplicaLogDirsRequest and will replace the current log of
-	// the replica in the future.
+	// the replica in the future. Such a future replica is reported in the destination log dir
+	// for as long as an intra-broker move is in progress.
 	IsTemporary bool
 }
 
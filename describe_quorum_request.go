@@ -0,0 +1,147 @@
+package sarama
+
+// clusterMetadataTopic is the internal topic holding the KRaft metadata log,
+// replicated by the metadata quorum on its single partition.
+const clusterMetadataTopic = "__cluster_metadata"
+
+// DescribeQuorumRequest is sent to describe the state of a KRaft (KIP-595)
+// quorum, typically the metadata quorum replicating the __cluster_metadata
+// topic.
+type DescribeQuorumRequest struct {
+	Version int16
+
+	// Topics contains the topics to describe
+	Topics []DescribeQuorumRequestTopic
+}
+
+type DescribeQuorumRequestTopic struct {
+	// TopicName is the topic name
+	TopicName string
+
+	// Partitions contains the partition indexes to describe
+	Partitions []int32
+}
+
+// NewDescribeQuorumRequest returns a DescribeQuorumRequest for the metadata
+// quorum using the highest protocol version supported by the given Kafka
+// version.
+func NewDescribeQuorumRequest(version KafkaVersion) *DescribeQuorumRequest {
+	r := &DescribeQuorumRequest{
+		Topics: []DescribeQuorumRequestTopic{{TopicName: clusterMetadataTopic, Partitions: []int32{0}}},
+	}
+	if version.IsAtLeast(V3_9_0_0) {
+		r.Version = 2
+	} else if version.IsAtLeast(V3_3_0_0) {
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *DescribeQuorumRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *DescribeQuorumRequest) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range r.Topics {
+		if err := pe.putString(topic.TopicName); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition)
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeQuorumRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]DescribeQuorumRequestTopic, n)
+	for i := range r.Topics {
+		topic := &r.Topics[i]
+		if topic.TopicName, err = pd.getString(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]int32, m)
+		for j := range topic.Partitions {
+			if topic.Partitions[j], err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeQuorumRequest) key() int16 {
+	return apiKeyDescribeQuorum
+}
+
+func (r *DescribeQuorumRequest) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeQuorumRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *DescribeQuorumRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *DescribeQuorumRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeQuorumRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *DescribeQuorumRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V3_9_0_0
+	case 1:
+		return V3_3_0_0
+	default:
+		return V2_7_0_0
+	}
+}
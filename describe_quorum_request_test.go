@@ -0,0 +1,23 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var describeQuorumRequestV0 = []byte{
+	2,                                                                                            // Topics
+	19, '_', '_', 'c', 'l', 'u', 's', 't', 'e', 'r', '_', 'm', 'e', 't', 'a', 'd', 'a', 't', 'a', // TopicName
+	2,          // Partitions
+	0, 0, 0, 0, // PartitionIndex
+	0, // empty tagged fields
+	0, // empty tagged fields
+	0, // empty tagged fields
+}
+
+func TestDescribeQuorumRequest(t *testing.T) {
+	for _, version := range []int16{0, 1, 2} {
+		request := NewDescribeQuorumRequest(V3_9_0_0)
+		request.Version = version
+		testRequest(t, "all versions", request, describeQuorumRequestV0)
+	}
+}
@@ -0,0 +1,459 @@
+package sarama
+
+// DescribeQuorumResponse describes the state of a KRaft quorum.
+type DescribeQuorumResponse struct {
+	Version int16
+
+	// ErrorCode is the top level error code
+	ErrorCode KError
+
+	// ErrorMessage is the top level error message (v2+)
+	ErrorMessage *string
+
+	// Topics contains the per-topic results
+	Topics []DescribeQuorumResponseTopic
+
+	// Nodes contains the endpoints of the voters (v2+)
+	Nodes []DescribeQuorumResponseNode
+}
+
+type DescribeQuorumResponseTopic struct {
+	// TopicName is the topic name
+	TopicName string
+
+	// Partitions contains the per-partition results
+	Partitions []DescribeQuorumResponsePartition
+}
+
+type DescribeQuorumResponsePartition struct {
+	// PartitionIndex is the partition index
+	PartitionIndex int32
+
+	ErrorCode KError
+
+	// ErrorMessage is the error message (v2+)
+	ErrorMessage *string
+
+	// LeaderID is the ID of the current leader or -1 if the leader is unknown
+	LeaderID int32
+
+	// LeaderEpoch is the latest known leader epoch
+	LeaderEpoch int32
+
+	// HighWatermark is the high watermark of the partition
+	HighWatermark int64
+
+	// CurrentVoters contains the state of the voters
+	CurrentVoters []QuorumReplicaState
+
+	// Observers contains the state of the observers
+	Observers []QuorumReplicaState
+}
+
+// QuorumReplicaState is the replication state of a voter or observer of a
+// KRaft quorum.
+type QuorumReplicaState struct {
+	// ReplicaID is the ID of the replica
+	ReplicaID int32
+
+	// ReplicaDirectoryID is the directory id of the replica (v2+)
+	ReplicaDirectoryID Uuid
+
+	// LogEndOffset is the last known log end offset of the follower or -1 if
+	// it is unknown
+	LogEndOffset int64
+
+	// LastFetchTimestamp is the last known leader wall clock time in
+	// milliseconds when a follower fetched from the leader, or -1 (v1+)
+	LastFetchTimestamp int64
+
+	// LastCaughtUpTimestamp is the leader wall clock append time in
+	// milliseconds of the offset for which the follower made the most recent
+	// fetch request, or -1 (v1+)
+	LastCaughtUpTimestamp int64
+}
+
+// DescribeQuorumResponseNode contains the endpoints of a quorum node.
+type DescribeQuorumResponseNode struct {
+	// NodeID is the ID of the associated node
+	NodeID int32
+
+	// Listeners contains the listeners of this controller
+	Listeners []RaftVoterListener
+}
+
+// RaftVoterListener is a listener endpoint of a KRaft voter.
+type RaftVoterListener struct {
+	// Name is the name of the endpoint
+	Name string
+
+	// Host is the hostname
+	Host string
+
+	// Port is the port
+	Port uint16
+}
+
+func encodeRaftVoterListeners(pe packetEncoder, listeners []RaftVoterListener) error {
+	if err := pe.putArrayLength(len(listeners)); err != nil {
+		return err
+	}
+	for _, listener := range listeners {
+		if err := pe.putString(listener.Name); err != nil {
+			return err
+		}
+
+		if err := pe.putString(listener.Host); err != nil {
+			return err
+		}
+
+		pe.putInt16(int16(listener.Port))
+		pe.putEmptyTaggedFieldArray()
+	}
+	return nil
+}
+
+func decodeRaftVoterListeners(pd packetDecoder) ([]RaftVoterListener, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errInvalidArrayLength
+	}
+
+	listeners := make([]RaftVoterListener, n)
+	for i := range listeners {
+		listener := &listeners[i]
+		if listener.Name, err = pd.getString(); err != nil {
+			return nil, err
+		}
+
+		if listener.Host, err = pd.getString(); err != nil {
+			return nil, err
+		}
+
+		port, err := pd.getInt16()
+		if err != nil {
+			return nil, err
+		}
+		listener.Port = uint16(port)
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return nil, err
+		}
+	}
+	return listeners, nil
+}
+
+func (s *QuorumReplicaState) encode(pe packetEncoder, version int16) error {
+	pe.putInt32(s.ReplicaID)
+
+	if version >= 2 {
+		if err := pe.putUuid(s.ReplicaDirectoryID); err != nil {
+			return err
+		}
+	}
+
+	pe.putInt64(s.LogEndOffset)
+
+	if version >= 1 {
+		pe.putInt64(s.LastFetchTimestamp)
+		pe.putInt64(s.LastCaughtUpTimestamp)
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (s *QuorumReplicaState) decode(pd packetDecoder, version int16) (err error) {
+	if s.ReplicaID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if version >= 2 {
+		if s.ReplicaDirectoryID, err = pd.getUuid(); err != nil {
+			return err
+		}
+	}
+
+	if s.LogEndOffset, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	if version >= 1 {
+		if s.LastFetchTimestamp, err = pd.getInt64(); err != nil {
+			return err
+		}
+
+		if s.LastCaughtUpTimestamp, err = pd.getInt64(); err != nil {
+			return err
+		}
+	} else {
+		s.LastFetchTimestamp = -1
+		s.LastCaughtUpTimestamp = -1
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func encodeQuorumReplicaStates(pe packetEncoder, states []QuorumReplicaState, version int16) error {
+	if err := pe.putArrayLength(len(states)); err != nil {
+		return err
+	}
+	for i := range states {
+		if err := states[i].encode(pe, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeQuorumReplicaStates(pd packetDecoder, version int16) ([]QuorumReplicaState, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errInvalidArrayLength
+	}
+
+	states := make([]QuorumReplicaState, n)
+	for i := range states {
+		if err := states[i].decode(pd, version); err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+func (p *DescribeQuorumResponsePartition) encode(pe packetEncoder, version int16) error {
+	pe.putInt32(p.PartitionIndex)
+	pe.putKError(p.ErrorCode)
+
+	if version >= 2 {
+		if err := pe.putNullableString(p.ErrorMessage); err != nil {
+			return err
+		}
+	}
+
+	pe.putInt32(p.LeaderID)
+	pe.putInt32(p.LeaderEpoch)
+	pe.putInt64(p.HighWatermark)
+
+	if err := encodeQuorumReplicaStates(pe, p.CurrentVoters, version); err != nil {
+		return err
+	}
+
+	if err := encodeQuorumReplicaStates(pe, p.Observers, version); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (p *DescribeQuorumResponsePartition) decode(pd packetDecoder, version int16) (err error) {
+	if p.PartitionIndex, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if version >= 2 {
+		if p.ErrorMessage, err = pd.getNullableString(); err != nil {
+			return err
+		}
+	}
+
+	if p.LeaderID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.HighWatermark, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	if p.CurrentVoters, err = decodeQuorumReplicaStates(pd, version); err != nil {
+		return err
+	}
+
+	if p.Observers, err = decodeQuorumReplicaStates(pd, version); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeQuorumResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *DescribeQuorumResponse) encode(pe packetEncoder) error {
+	pe.putKError(r.ErrorCode)
+
+	if r.Version >= 2 {
+		if err := pe.putNullableString(r.ErrorMessage); err != nil {
+			return err
+		}
+	}
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range r.Topics {
+		if err := pe.putString(topic.TopicName); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for i := range topic.Partitions {
+			if err := topic.Partitions[i].encode(pe, r.Version); err != nil {
+				return err
+			}
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	if r.Version >= 2 {
+		if err := pe.putArrayLength(len(r.Nodes)); err != nil {
+			return err
+		}
+		for _, node := range r.Nodes {
+			pe.putInt32(node.NodeID)
+
+			if err := encodeRaftVoterListeners(pe, node.Listeners); err != nil {
+				return err
+			}
+
+			pe.putEmptyTaggedFieldArray()
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.Version >= 2 {
+		if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+			return err
+		}
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]DescribeQuorumResponseTopic, n)
+	for i := range r.Topics {
+		topic := &r.Topics[i]
+		if topic.TopicName, err = pd.getString(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]DescribeQuorumResponsePartition, m)
+		for j := range topic.Partitions {
+			if err := topic.Partitions[j].decode(pd, r.Version); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if r.Version >= 2 {
+		n, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return errInvalidArrayLength
+		}
+
+		r.Nodes = make([]DescribeQuorumResponseNode, n)
+		for i := range r.Nodes {
+			node := &r.Nodes[i]
+			if node.NodeID, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if node.Listeners, err = decodeRaftVoterListeners(pd); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeQuorumResponse) key() int16 {
+	return apiKeyDescribeQuorum
+}
+
+func (r *DescribeQuorumResponse) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeQuorumResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *DescribeQuorumResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *DescribeQuorumResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeQuorumResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *DescribeQuorumResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V3_9_0_0
+	case 1:
+		return V3_3_0_0
+	default:
+		return V2_7_0_0
+	}
+}
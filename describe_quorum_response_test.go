@@ -0,0 +1,104 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	describeQuorumResponseV1 = []byte{
+		0, 0, // ErrorCode
+		2,                                                                                            // Topics
+		19, '_', '_', 'c', 'l', 'u', 's', 't', 'e', 'r', '_', 'm', 'e', 't', 'a', 'd', 'a', 't', 'a', // TopicName
+		2,          // Partitions
+		0, 0, 0, 0, // PartitionIndex
+		0, 0, // ErrorCode
+		0, 0, 0, 1, // LeaderID
+		0, 0, 0, 5, // LeaderEpoch
+		0, 0, 0, 0, 0, 0, 0, 100, // HighWatermark
+		2,          // CurrentVoters
+		0, 0, 0, 1, // ReplicaID
+		0, 0, 0, 0, 0, 0, 0, 100, // LogEndOffset
+		0, 0, 0, 0, 0, 0, 3, 232, // LastFetchTimestamp
+		0, 0, 0, 0, 0, 0, 3, 232, // LastCaughtUpTimestamp
+		0,          // empty tagged fields
+		2,          // Observers
+		0, 0, 0, 4, // ReplicaID
+		0, 0, 0, 0, 0, 0, 0, 90, // LogEndOffset
+		255, 255, 255, 255, 255, 255, 255, 255, // LastFetchTimestamp
+		255, 255, 255, 255, 255, 255, 255, 255, // LastCaughtUpTimestamp
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	describeQuorumResponseV2 = []byte{
+		0, 0, // ErrorCode
+		0,                                                                                            // ErrorMessage
+		2,                                                                                            // Topics
+		19, '_', '_', 'c', 'l', 'u', 's', 't', 'e', 'r', '_', 'm', 'e', 't', 'a', 'd', 'a', 't', 'a', // TopicName
+		2,          // Partitions
+		0, 0, 0, 0, // PartitionIndex
+		0, 0, // ErrorCode
+		0,          // ErrorMessage
+		0, 0, 0, 1, // LeaderID
+		0, 0, 0, 5, // LeaderEpoch
+		0, 0, 0, 0, 0, 0, 0, 100, // HighWatermark
+		2,          // CurrentVoters
+		0, 0, 0, 1, // ReplicaID
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // ReplicaDirectoryID
+		0, 0, 0, 0, 0, 0, 0, 100, // LogEndOffset
+		0, 0, 0, 0, 0, 0, 3, 232, // LastFetchTimestamp
+		0, 0, 0, 0, 0, 0, 3, 232, // LastCaughtUpTimestamp
+		0,          // empty tagged fields
+		1,          // Observers
+		0,          // empty tagged fields
+		0,          // empty tagged fields
+		2,          // Nodes
+		0, 0, 0, 1, // NodeID
+		2,                                                    // Listeners
+		11, 'C', 'O', 'N', 'T', 'R', 'O', 'L', 'L', 'E', 'R', // Name
+		10, 'l', 'o', 'c', 'a', 'l', 'h', 'o', 's', 't', // Host
+		0x23, 0x83, // Port
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestDescribeQuorumResponse(t *testing.T) {
+	response := &DescribeQuorumResponse{
+		Version: 1,
+		Topics: []DescribeQuorumResponseTopic{{
+			TopicName: "__cluster_metadata",
+			Partitions: []DescribeQuorumResponsePartition{{
+				LeaderID:      1,
+				LeaderEpoch:   5,
+				HighWatermark: 100,
+				CurrentVoters: []QuorumReplicaState{{
+					ReplicaID:             1,
+					LogEndOffset:          100,
+					LastFetchTimestamp:    1000,
+					LastCaughtUpTimestamp: 1000,
+				}},
+				Observers: []QuorumReplicaState{{
+					ReplicaID:             4,
+					LogEndOffset:          90,
+					LastFetchTimestamp:    -1,
+					LastCaughtUpTimestamp: -1,
+				}},
+			}},
+		}},
+	}
+	testResponse(t, "v1", response, describeQuorumResponseV1)
+
+	response.Version = 2
+	partition := &response.Topics[0].Partitions[0]
+	partition.CurrentVoters[0].ReplicaDirectoryID = Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	partition.Observers = []QuorumReplicaState{}
+	response.Nodes = []DescribeQuorumResponseNode{{
+		NodeID:    1,
+		Listeners: []RaftVoterListener{{Name: "CONTROLLER", Host: "localhost", Port: 9091}},
+	}}
+	testResponse(t, "v2", response, describeQuorumResponseV2)
+}
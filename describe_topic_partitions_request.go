@@ -0,0 +1,170 @@
+package sarama
+
+// DescribeTopicPartitionsRequest describes the partitions of topics, paging
+// through them with a cursor so that the metadata of clusters with a large
+// number of partitions can be fetched incrementally (KIP-966).
+type DescribeTopicPartitionsRequest struct {
+	Version int16
+
+	// Topics contains the topics to fetch details for, or all topics if
+	// empty
+	Topics []string
+
+	// ResponsePartitionLimit is the maximum number of partitions included in
+	// the response
+	ResponsePartitionLimit int32
+
+	// Cursor is the first topic and partition index to fetch details for, or
+	// nil to start from the first topic
+	Cursor *DescribeTopicPartitionsCursor
+}
+
+// defaultDescribeTopicPartitionsLimit is the number of partitions requested
+// per page, matching the broker's default max partitions per response.
+const defaultDescribeTopicPartitionsLimit = 2000
+
+// NewDescribeTopicPartitionsRequest returns a request for the first page of
+// partitions of the given topics, or of all topics if none are given.
+func NewDescribeTopicPartitionsRequest(topics []string) *DescribeTopicPartitionsRequest {
+	return &DescribeTopicPartitionsRequest{
+		Topics:                 topics,
+		ResponsePartitionLimit: defaultDescribeTopicPartitionsLimit,
+	}
+}
+
+// DescribeTopicPartitionsCursor points to the topic partition at which a page
+// of DescribeTopicPartitions results starts.
+type DescribeTopicPartitionsCursor struct {
+	// TopicName is the name for the first topic to process
+	TopicName string
+
+	// PartitionIndex is the partition index to start with
+	PartitionIndex int32
+}
+
+func encodeDescribeTopicPartitionsCursor(pe packetEncoder, cursor *DescribeTopicPartitionsCursor) error {
+	if cursor == nil {
+		pe.putInt8(-1)
+		return nil
+	}
+	pe.putInt8(1)
+
+	if err := pe.putString(cursor.TopicName); err != nil {
+		return err
+	}
+
+	pe.putInt32(cursor.PartitionIndex)
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func decodeDescribeTopicPartitionsCursor(pd packetDecoder) (*DescribeTopicPartitionsCursor, error) {
+	present, err := pd.getInt8()
+	if err != nil {
+		return nil, err
+	}
+	if present < 0 {
+		return nil, nil
+	}
+
+	cursor := &DescribeTopicPartitionsCursor{}
+	if cursor.TopicName, err = pd.getString(); err != nil {
+		return nil, err
+	}
+
+	if cursor.PartitionIndex, err = pd.getInt32(); err != nil {
+		return nil, err
+	}
+
+	if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (r *DescribeTopicPartitionsRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *DescribeTopicPartitionsRequest) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range r.Topics {
+		if err := pe.putString(topic); err != nil {
+			return err
+		}
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putInt32(r.ResponsePartitionLimit)
+
+	if err := encodeDescribeTopicPartitionsCursor(pe, r.Cursor); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeTopicPartitionsRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]string, n)
+	for i := range r.Topics {
+		if r.Topics[i], err = pd.getString(); err != nil {
+			return err
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if r.ResponsePartitionLimit, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.Cursor, err = decodeDescribeTopicPartitionsCursor(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeTopicPartitionsRequest) key() int16 {
+	return apiKeyDescribeTopicPartitions
+}
+
+func (r *DescribeTopicPartitionsRequest) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeTopicPartitionsRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *DescribeTopicPartitionsRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *DescribeTopicPartitionsRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeTopicPartitionsRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *DescribeTopicPartitionsRequest) requiredVersion() KafkaVersion {
+	return V3_8_0_0
+}
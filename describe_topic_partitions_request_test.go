@@ -0,0 +1,36 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	describeTopicPartitionsRequestV0 = []byte{
+		2,                // Topics
+		4, 'f', 'o', 'o', // Name
+		0,            // empty tagged fields
+		0, 0, 7, 208, // ResponsePartitionLimit
+		255, // Cursor
+		0,   // empty tagged fields
+	}
+
+	describeTopicPartitionsRequestV0Cursor = []byte{
+		1,            // Topics
+		0, 0, 7, 208, // ResponsePartitionLimit
+		1,                // Cursor
+		4, 'f', 'o', 'o', // TopicName
+		0, 0, 0, 5, // PartitionIndex
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestDescribeTopicPartitionsRequest(t *testing.T) {
+	request := NewDescribeTopicPartitionsRequest([]string{"foo"})
+	testRequest(t, "no cursor", request, describeTopicPartitionsRequestV0)
+
+	request = NewDescribeTopicPartitionsRequest(nil)
+	request.Topics = []string{}
+	request.Cursor = &DescribeTopicPartitionsCursor{TopicName: "foo", PartitionIndex: 5}
+	testRequest(t, "cursor", request, describeTopicPartitionsRequestV0Cursor)
+}
@@ -0,0 +1,292 @@
+package sarama
+
+import "time"
+
+type DescribeTopicPartitionsResponse struct {
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// Topics contains each topic in the response
+	Topics []DescribeTopicPartitionsResponseTopic
+
+	// NextCursor is the next topic and partition index to fetch details for,
+	// or nil if all the requested partitions have been described
+	NextCursor *DescribeTopicPartitionsCursor
+}
+
+type DescribeTopicPartitionsResponseTopic struct {
+	// ErrorCode is the topic error, or 0 if there was no error
+	ErrorCode KError
+
+	// Name is the topic name
+	Name *string
+
+	// TopicID is the topic id
+	TopicID Uuid
+
+	// IsInternal is true if the topic is internal
+	IsInternal bool
+
+	// Partitions contains each partition in the topic
+	Partitions []DescribeTopicPartitionsResponsePartition
+
+	// TopicAuthorizedOperations is a 32-bit bitfield to represent authorized
+	// operations for this topic
+	TopicAuthorizedOperations int32
+}
+
+type DescribeTopicPartitionsResponsePartition struct {
+	// ErrorCode is the partition error, or 0 if there was no error
+	ErrorCode KError
+
+	// PartitionIndex is the partition index
+	PartitionIndex int32
+
+	// LeaderID is the ID of the leader broker
+	LeaderID int32
+
+	// LeaderEpoch is the leader epoch of this partition
+	LeaderEpoch int32
+
+	// ReplicaNodes contains the set of all nodes that host this partition
+	ReplicaNodes []int32
+
+	// IsrNodes contains the set of nodes that are in sync with the leader for
+	// this partition
+	IsrNodes []int32
+
+	// EligibleLeaderReplicas contains the replicas, not in the ISR, that are
+	// eligible to become leader, or nil if ELR is disabled
+	EligibleLeaderReplicas []int32
+
+	// LastKnownELR contains the last known ELR, or nil if ELR is disabled
+	LastKnownELR []int32
+
+	// OfflineReplicas contains the set of offline replicas of this partition
+	OfflineReplicas []int32
+}
+
+func (r *DescribeTopicPartitionsResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (p *DescribeTopicPartitionsResponsePartition) encode(pe packetEncoder) error {
+	pe.putKError(p.ErrorCode)
+	pe.putInt32(p.PartitionIndex)
+	pe.putInt32(p.LeaderID)
+	pe.putInt32(p.LeaderEpoch)
+
+	if err := pe.putInt32Array(p.ReplicaNodes); err != nil {
+		return err
+	}
+
+	if err := pe.putInt32Array(p.IsrNodes); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableInt32Array(p.EligibleLeaderReplicas); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableInt32Array(p.LastKnownELR); err != nil {
+		return err
+	}
+
+	if err := pe.putInt32Array(p.OfflineReplicas); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (p *DescribeTopicPartitionsResponsePartition) decode(pd packetDecoder) (err error) {
+	if p.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if p.PartitionIndex, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.LeaderID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if p.ReplicaNodes, err = pd.getInt32Array(); err != nil {
+		return err
+	}
+
+	if p.IsrNodes, err = pd.getInt32Array(); err != nil {
+		return err
+	}
+
+	if p.EligibleLeaderReplicas, err = pd.getNullableInt32Array(); err != nil {
+		return err
+	}
+
+	if p.LastKnownELR, err = pd.getNullableInt32Array(); err != nil {
+		return err
+	}
+
+	if p.OfflineReplicas, err = pd.getInt32Array(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (t *DescribeTopicPartitionsResponseTopic) encode(pe packetEncoder) error {
+	pe.putKError(t.ErrorCode)
+
+	if err := pe.putNullableString(t.Name); err != nil {
+		return err
+	}
+
+	if err := pe.putUuid(t.TopicID); err != nil {
+		return err
+	}
+
+	pe.putBool(t.IsInternal)
+
+	if err := pe.putArrayLength(len(t.Partitions)); err != nil {
+		return err
+	}
+	for i := range t.Partitions {
+		if err := t.Partitions[i].encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putInt32(t.TopicAuthorizedOperations)
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *DescribeTopicPartitionsResponseTopic) decode(pd packetDecoder) (err error) {
+	if t.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if t.Name, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if t.TopicID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if t.IsInternal, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	t.Partitions = make([]DescribeTopicPartitionsResponsePartition, n)
+	for i := range t.Partitions {
+		if err := t.Partitions[i].decode(pd); err != nil {
+			return err
+		}
+	}
+
+	if t.TopicAuthorizedOperations, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeTopicPartitionsResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for i := range r.Topics {
+		if err := r.Topics[i].encode(pe); err != nil {
+			return err
+		}
+	}
+
+	if err := encodeDescribeTopicPartitionsCursor(pe, r.NextCursor); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *DescribeTopicPartitionsResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]DescribeTopicPartitionsResponseTopic, n)
+	for i := range r.Topics {
+		if err := r.Topics[i].decode(pd); err != nil {
+			return err
+		}
+	}
+
+	if r.NextCursor, err = decodeDescribeTopicPartitionsCursor(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *DescribeTopicPartitionsResponse) key() int16 {
+	return apiKeyDescribeTopicPartitions
+}
+
+func (r *DescribeTopicPartitionsResponse) version() int16 {
+	return r.Version
+}
+
+func (r *DescribeTopicPartitionsResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *DescribeTopicPartitionsResponse) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *DescribeTopicPartitionsResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *DescribeTopicPartitionsResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *DescribeTopicPartitionsResponse) requiredVersion() KafkaVersion {
+	return V3_8_0_0
+}
+
+func (r *DescribeTopicPartitionsResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,61 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var describeTopicPartitionsResponseV0 = []byte{
+	0, 0, 0, 100, // ThrottleTime
+	2,    // Topics
+	0, 0, // ErrorCode
+	4, 'f', 'o', 'o', // Name
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+	0,    // IsInternal
+	2,    // Partitions
+	0, 0, // ErrorCode
+	0, 0, 0, 0, // PartitionIndex
+	0, 0, 0, 1, // LeaderID
+	0, 0, 0, 2, // LeaderEpoch
+	3,          // ReplicaNodes
+	0, 0, 0, 1, //
+	0, 0, 0, 2, //
+	2,          // IsrNodes
+	0, 0, 0, 1, //
+	0,          // EligibleLeaderReplicas
+	2,          // LastKnownELR
+	0, 0, 0, 2, //
+	1,          // OfflineReplicas
+	0,          // empty tagged fields
+	0, 0, 0, 8, // TopicAuthorizedOperations
+	0,                // empty tagged fields
+	1,                // NextCursor
+	4, 'f', 'o', 'o', // TopicName
+	0, 0, 0, 1, // PartitionIndex
+	0, // empty tagged fields
+	0, // empty tagged fields
+}
+
+func TestDescribeTopicPartitionsResponse(t *testing.T) {
+	name := "foo"
+	response := &DescribeTopicPartitionsResponse{
+		ThrottleTime: 100 * time.Millisecond,
+		Topics: []DescribeTopicPartitionsResponseTopic{{
+			Name:    &name,
+			TopicID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			Partitions: []DescribeTopicPartitionsResponsePartition{{
+				LeaderID:        1,
+				LeaderEpoch:     2,
+				ReplicaNodes:    []int32{1, 2},
+				IsrNodes:        []int32{1},
+				LastKnownELR:    []int32{2},
+				OfflineReplicas: []int32{},
+			}},
+			TopicAuthorizedOperations: 8,
+		}},
+		NextCursor: &DescribeTopicPartitionsCursor{TopicName: "foo", PartitionIndex: 1},
+	}
+	testResponse(t, "v0", response, describeTopicPartitionsResponseV0)
+}
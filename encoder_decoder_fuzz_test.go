@@ -64,9 +64,31 @@ func FuzzVersionedDecodeRequest(f *testing.F) {
 		{key: apiKeyDescribeUserScramCredentials, version: 0, body: emptyDescribeUserScramCredentialsRequest},
 		{key: apiKeyAlterUserScramCredentials, version: 0, body: emptyAlterUserScramCredentialsRequest},
 		{key: apiKeyUpdateFeatures, version: 0, body: updateFeaturesRequestV0},
+		{key: apiKeyCreateDelegationToken, version: 3, body: createDelegationTokenRequestV3},
+		{key: apiKeyRenewDelegationToken, version: 2, body: renewDelegationTokenRequestV2},
+		{key: apiKeyExpireDelegationToken, version: 0, body: expireDelegationTokenRequestV0},
+		{key: apiKeyDescribeDelegationToken, version: 2, body: describeDelegationTokenRequestV2},
+		{key: apiKeyOffsetForLeaderEpoch, version: 0, body: offsetForLeaderEpochRequestV0},
+		{key: apiKeyOffsetForLeaderEpoch, version: 4, body: offsetForLeaderEpochRequestV4},
 		{key: apiKeyDescribeProducers, version: 0, body: describeProducersRequestV0},
 		{key: apiKeyDescribeTransactions, version: 0, body: describeTransactionsRequestV0},
 		{key: apiKeyListTransactions, version: 0, body: listTransactionsRequestV0},
+		{key: apiKeyConsumerGroupHeartbeat, version: 1, body: consumerGroupHeartbeatRequestV1},
+		{key: apiKeyConsumerGroupDescribe, version: 0, body: consumerGroupDescribeRequestV0},
+		{key: apiKeyShareGroupHeartbeat, version: 0, body: shareGroupHeartbeatRequestV0},
+		{key: apiKeyShareFetch, version: 0, body: shareFetchRequestV0},
+		{key: apiKeyShareFetch, version: 1, body: shareFetchRequestV1},
+		{key: apiKeyShareAcknowledge, version: 0, body: shareAcknowledgeRequestV0},
+		{key: apiKeyWriteTxnMarkers, version: 1, body: writeTxnMarkersRequestV1},
+		{key: apiKeyAlterReplicaLogDirs, version: 2, body: alterReplicaLogDirsRequestV2},
+		{key: apiKeyDescribeQuorum, version: 2, body: describeQuorumRequestV0},
+		{key: apiKeyUnregisterBroker, version: 0, body: unregisterBrokerRequestV0},
+		{key: apiKeyAddRaftVoter, version: 0, body: addRaftVoterRequestV0},
+		{key: apiKeyRemoveRaftVoter, version: 0, body: removeRaftVoterRequestV0},
+		{key: apiKeyDescribeTopicPartitions, version: 0, body: describeTopicPartitionsRequestV0},
+		{key: apiKeyDescribeTopicPartitions, version: 0, body: describeTopicPartitionsRequestV0Cursor},
+		{key: apiKeyGetTelemetrySubscriptions, version: 0, body: getTelemetrySubscriptionsRequestV0},
+		{key: apiKeyPushTelemetry, version: 0, body: pushTelemetryRequestV0},
 		{key: apiKeyListTransactions, version: 1, body: listTransactionsRequestV1},
 	} {
 		f.Add(seed.key, seed.version, seed.body)
@@ -123,6 +145,27 @@ func FuzzVersionedDecodeResponse(f *testing.F) {
 		{key: apiKeyDescribeUserScramCredentials, version: 0, body: emptyDescribeUserScramCredentialsResponse},
 		{key: apiKeyAlterUserScramCredentials, version: 0, body: emptyAlterUserScramCredentialsResponse},
 		{key: apiKeyUpdateFeatures, version: 0, body: updateFeaturesResponseV0},
+		{key: apiKeyCreateDelegationToken, version: 0, body: createDelegationTokenResponseV0},
+		{key: apiKeyRenewDelegationToken, version: 2, body: renewDelegationTokenResponseV2},
+		{key: apiKeyExpireDelegationToken, version: 0, body: expireDelegationTokenResponseV0},
+		{key: apiKeyDescribeDelegationToken, version: 3, body: describeDelegationTokenResponseV3},
+		{key: apiKeyConsumerGroupHeartbeat, version: 0, body: consumerGroupHeartbeatResponseV0},
+		{key: apiKeyConsumerGroupDescribe, version: 1, body: consumerGroupDescribeResponseV1},
+		{key: apiKeyShareGroupHeartbeat, version: 0, body: shareGroupHeartbeatResponseV0},
+		{key: apiKeyShareFetch, version: 0, body: shareFetchResponseV0},
+		{key: apiKeyShareFetch, version: 1, body: shareFetchResponseV1},
+		{key: apiKeyShareAcknowledge, version: 0, body: shareAcknowledgeResponseV0},
+		{key: apiKeyWriteTxnMarkers, version: 1, body: writeTxnMarkersResponseV1},
+		{key: apiKeyAlterReplicaLogDirs, version: 2, body: alterReplicaLogDirsResponseV2},
+		{key: apiKeyDescribeQuorum, version: 1, body: describeQuorumResponseV1},
+		{key: apiKeyDescribeQuorum, version: 2, body: describeQuorumResponseV2},
+		{key: apiKeyUnregisterBroker, version: 0, body: unregisterBrokerResponseV0},
+		{key: apiKeyAddRaftVoter, version: 0, body: addRaftVoterResponseV0},
+		{key: apiKeyRemoveRaftVoter, version: 0, body: removeRaftVoterResponseV0},
+		{key: apiKeyDescribeTopicPartitions, version: 0, body: describeTopicPartitionsResponseV0},
+		{key: apiKeyGetTelemetrySubscriptions, version: 0, body: getTelemetrySubscriptionsResponseV0},
+		{key: apiKeyPushTelemetry, version: 0, body: pushTelemetryResponseV0},
+		{key: apiKeyOffsetForLeaderEpoch, version: 2, body: offsetForLeaderEpochResponseV2},
 		{key: apiKeyDescribeProducers, version: 0, body: describeProducersResponseV0},
 		{key: apiKeyDescribeTransactions, version: 0, body: describeTransactionsResponseV0},
 		{key: apiKeyListTransactions, version: 0, body: listTransactionsResponseV0},
@@ -0,0 +1,92 @@
+package sarama
+
+// ExpireDelegationTokenRequest asks the broker to change the expiry time of a delegation token.
+type ExpireDelegationTokenRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// HMAC contains the HMAC of the delegation token.
+	HMAC []byte
+	// ExpiryTimePeriodMs contains the expiry time period in milliseconds, a negative value expires the token immediately.
+	ExpiryTimePeriodMs int64
+}
+
+// NewExpireDelegationTokenRequest returns a ExpireDelegationTokenRequest using
+// the highest protocol version supported by the given Kafka version.
+func NewExpireDelegationTokenRequest(version KafkaVersion) *ExpireDelegationTokenRequest {
+	r := &ExpireDelegationTokenRequest{}
+	switch {
+	case version.IsAtLeast(V2_4_0_0):
+		// Version 2 is the first flexible version.
+		r.Version = 2
+	case version.IsAtLeast(V2_0_0_0):
+		// Version 1 is the same as version 0.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ExpireDelegationTokenRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ExpireDelegationTokenRequest) encode(pe packetEncoder) error {
+	if err := pe.putBytes(r.HMAC); err != nil {
+		return err
+	}
+	pe.putInt64(r.ExpiryTimePeriodMs)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ExpireDelegationTokenRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.HMAC, err = pd.getBytes(); err != nil {
+		return err
+	}
+	if r.ExpiryTimePeriodMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ExpireDelegationTokenRequest) key() int16 {
+	return apiKeyExpireDelegationToken
+}
+
+func (r *ExpireDelegationTokenRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ExpireDelegationTokenRequest) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (r *ExpireDelegationTokenRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *ExpireDelegationTokenRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ExpireDelegationTokenRequest) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *ExpireDelegationTokenRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
@@ -0,0 +1,91 @@
+package sarama
+
+import "time"
+
+// ExpireDelegationTokenResponse is the response to a
+// ExpireDelegationTokenRequest.
+type ExpireDelegationTokenResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// Err contains the error, or zero if there was no error.
+	Err KError
+	// ExpiryTimestampMs contains the timestamp in milliseconds at which the
+	// token expires.
+	ExpiryTimestampMs int64
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+}
+
+func (r *ExpireDelegationTokenResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ExpireDelegationTokenResponse) encode(pe packetEncoder) error {
+	pe.putKError(r.Err)
+	pe.putInt64(r.ExpiryTimestampMs)
+	pe.putDurationMs(r.ThrottleTime)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ExpireDelegationTokenResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+	if r.ExpiryTimestampMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ExpireDelegationTokenResponse) key() int16 {
+	return apiKeyExpireDelegationToken
+}
+
+func (r *ExpireDelegationTokenResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ExpireDelegationTokenResponse) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 1
+	}
+	return 0
+}
+
+func (r *ExpireDelegationTokenResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *ExpireDelegationTokenResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ExpireDelegationTokenResponse) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *ExpireDelegationTokenResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
+
+func (r *ExpireDelegationTokenResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,40 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	expireDelegationTokenResponseV0 = []byte{
+		0, 62, // ErrorCode
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestampMs
+		0, 0, 0, 100, // ThrottleTimeMs
+	}
+
+	expireDelegationTokenResponseV2 = []byte{
+		0, 0, // ErrorCode
+		0, 0, 0, 0, 0, 0, 0x07, 0xd0, // ExpiryTimestampMs
+		0, 0, 0, 100, // ThrottleTimeMs
+		0, // empty tagged fields
+	}
+)
+
+func TestExpireDelegationTokenResponse(t *testing.T) {
+	response := &ExpireDelegationTokenResponse{
+		Version:           0,
+		Err:               ErrDelegationTokenNotFound,
+		ExpiryTimestampMs: 2000,
+		ThrottleTime:      100 * time.Millisecond,
+	}
+	testResponse(t, "v0", response, expireDelegationTokenResponseV0)
+
+	response = &ExpireDelegationTokenResponse{
+		Version:           2,
+		ExpiryTimestampMs: 2000,
+		ThrottleTime:      100 * time.Millisecond,
+	}
+	testResponse(t, "v2", response, expireDelegationTokenResponseV2)
+}
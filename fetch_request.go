@@ -367,3 +367,12 @@ func (r *FetchRequest) AddBlock(topic string, partitionID int32, fetchOffset int
 
 	r.blocks[topic][partitionID] = tmp
 }
+
+// AddForgottenPartition asks the broker to remove a partition from the fetch
+// session of an incremental fetch request (v7+).
+func (r *FetchRequest) AddForgottenPartition(topic string, partitionID int32) {
+	if r.forgotten == nil {
+		r.forgotten = make(map[string][]int32)
+	}
+	r.forgotten[topic] = append(r.forgotten[topic], partitionID)
+}
@@ -0,0 +1,170 @@
+package sarama
+
+import (
+	"math"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// fetchSessionInitialEpoch is sent along with a zero session ID to ask the
+// broker to create a new fetch session.
+const fetchSessionInitialEpoch int32 = 0
+
+// fetchSessionPartition is the fetch state of a partition that the broker
+// caches as part of a fetch session.
+type fetchSessionPartition struct {
+	fetchOffset int64
+	maxBytes    int32
+	leaderEpoch int32
+}
+
+// fetchSession implements the client side of KIP-227 incremental fetch
+// sessions for a single broker. The first request of a session is a full
+// fetch that lists every partition; once the broker has assigned a session ID
+// subsequent requests only carry the partitions whose fetch state changed and
+// the partitions that should be dropped from the session. Any session error
+// falls back to a full fetch which establishes a new session.
+//
+// A fetchSession is not safe for concurrent use, it is owned by the
+// subscriptionConsumer goroutine of a brokerConsumer.
+type fetchSession struct {
+	id    int32
+	epoch int32
+	// partitions is the fetch state known to the broker for this session.
+	partitions map[string]map[int32]fetchSessionPartition
+	// next is the fetch state sent with the in-flight request, it becomes the
+	// session state once the request succeeds.
+	next map[string]map[int32]fetchSessionPartition
+
+	fullFetchRate        metrics.Meter
+	incrementalFetchRate metrics.Meter
+	errorRate            metrics.Meter
+	partitionsPerRequest metrics.Histogram
+}
+
+func newFetchSession(metricRegistry metrics.Registry) *fetchSession {
+	return &fetchSession{
+		epoch:                fetchSessionInitialEpoch,
+		fullFetchRate:        metrics.GetOrRegisterMeter("consumer-fetch-session-full-rate", metricRegistry),
+		incrementalFetchRate: metrics.GetOrRegisterMeter("consumer-fetch-session-incremental-rate", metricRegistry),
+		errorRate:            metrics.GetOrRegisterMeter("consumer-fetch-session-error-rate", metricRegistry),
+		partitionsPerRequest: getOrRegisterHistogram("consumer-fetch-session-partitions", metricRegistry),
+	}
+}
+
+func (s *fetchSession) add(topic string, partition int32, state fetchSessionPartition) {
+	if s.next == nil {
+		s.next = make(map[string]map[int32]fetchSessionPartition)
+	}
+	if s.next[topic] == nil {
+		s.next[topic] = make(map[int32]fetchSessionPartition)
+	}
+	s.next[topic][partition] = state
+}
+
+// build fills in the session fields, the fetch blocks and the forgotten
+// partitions of request from the partitions added since the last call. It
+// returns false, and leaves the session untouched, if no partition was added.
+func (s *fetchSession) build(request *FetchRequest) bool {
+	if len(s.next) == 0 {
+		s.next = nil
+		return false
+	}
+
+	if request.Version < 7 {
+		for topic, partitions := range s.next {
+			for partition, state := range partitions {
+				request.AddBlock(topic, partition, state.fetchOffset, state.maxBytes, state.leaderEpoch)
+			}
+		}
+		return true
+	}
+
+	request.SessionID = s.id
+	request.SessionEpoch = s.epoch
+
+	if s.id == 0 {
+		s.fullFetchRate.Mark(1)
+		for topic, partitions := range s.next {
+			for partition, state := range partitions {
+				request.AddBlock(topic, partition, state.fetchOffset, state.maxBytes, state.leaderEpoch)
+			}
+		}
+		s.partitionsPerRequest.Update(int64(countFetchBlocks(request)))
+		return true
+	}
+
+	s.incrementalFetchRate.Mark(1)
+	for topic, partitions := range s.next {
+		for partition, state := range partitions {
+			if cached, ok := s.partitions[topic][partition]; !ok || cached != state {
+				request.AddBlock(topic, partition, state.fetchOffset, state.maxBytes, state.leaderEpoch)
+			}
+		}
+	}
+	for topic, partitions := range s.partitions {
+		for partition := range partitions {
+			if _, ok := s.next[topic][partition]; !ok {
+				request.AddForgottenPartition(topic, partition)
+			}
+		}
+	}
+	s.partitionsPerRequest.Update(int64(countFetchBlocks(request)))
+	return true
+}
+
+// handleResponse advances the session after a successful fetch, or resets it
+// so that the next request is a full fetch if the broker reported an error.
+func (s *fetchSession) handleResponse(request *FetchRequest, response *FetchResponse) {
+	next := s.next
+	s.next = nil
+	if request.Version < 7 {
+		return
+	}
+
+	// FETCH_SESSION_ID_NOT_FOUND and INVALID_FETCH_SESSION_EPOCH are the
+	// expected errors here, but any top level error invalidates the session
+	if kerr := KError(response.ErrorCode); kerr != ErrNoError {
+		Logger.Printf("consumer/fetch-session/%d falling back to a full fetch because %s\n", s.id, kerr)
+		s.errorRate.Mark(1)
+		s.reset()
+		return
+	}
+
+	switch {
+	case s.id == 0 && response.SessionID == 0:
+		// the broker did not create a session, e.g. because its fetch
+		// session cache is full, keep sending full fetches
+		s.partitions = nil
+		return
+	case s.id == 0:
+		s.id = response.SessionID
+		s.epoch = fetchSessionInitialEpoch
+	}
+	s.partitions = next
+	s.epoch = nextFetchSessionEpoch(s.epoch)
+}
+
+// reset discards the session so that the next request is a full fetch.
+func (s *fetchSession) reset() {
+	s.id = 0
+	s.epoch = fetchSessionInitialEpoch
+	s.partitions = nil
+	s.next = nil
+}
+
+func nextFetchSessionEpoch(epoch int32) int32 {
+	if epoch == math.MaxInt32 {
+		// the epoch wraps around to 1 as 0 means a new session
+		return 1
+	}
+	return epoch + 1
+}
+
+func countFetchBlocks(request *FetchRequest) int {
+	n := 0
+	for _, partitions := range request.blocks {
+		n += len(partitions)
+	}
+	return n
+}
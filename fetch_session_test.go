@@ -0,0 +1,107 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFetchRequest() *FetchRequest {
+	return &FetchRequest{Version: 10}
+}
+
+func TestFetchSessionIncremental(t *testing.T) {
+	registry := metrics.NewRegistry()
+	session := newFetchSession(registry)
+
+	// the first request is a full fetch asking for a new session
+	session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+	session.add("my_topic", 1, fetchSessionPartition{fetchOffset: 20, maxBytes: 1024, leaderEpoch: 1})
+	request := newTestFetchRequest()
+	require.True(t, session.build(request))
+	require.Equal(t, int32(0), request.SessionID)
+	require.Equal(t, fetchSessionInitialEpoch, request.SessionEpoch)
+	require.Len(t, request.blocks["my_topic"], 2)
+	session.handleResponse(request, &FetchResponse{Version: 10, SessionID: 42})
+
+	// nothing changed, only the session is sent
+	session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+	session.add("my_topic", 1, fetchSessionPartition{fetchOffset: 20, maxBytes: 1024, leaderEpoch: 1})
+	request = newTestFetchRequest()
+	require.True(t, session.build(request))
+	require.Equal(t, int32(42), request.SessionID)
+	require.Equal(t, int32(1), request.SessionEpoch)
+	require.Empty(t, request.blocks)
+	require.Empty(t, request.forgotten)
+	session.handleResponse(request, &FetchResponse{Version: 10, SessionID: 42})
+
+	// partition 0 moved forward, partition 1 was dropped and 2 was added
+	session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 15, maxBytes: 1024, leaderEpoch: 1})
+	session.add("my_topic", 2, fetchSessionPartition{fetchOffset: 0, maxBytes: 1024, leaderEpoch: 1})
+	request = newTestFetchRequest()
+	require.True(t, session.build(request))
+	require.Equal(t, int32(2), request.SessionEpoch)
+	require.Len(t, request.blocks["my_topic"], 2)
+	require.Equal(t, int64(15), request.blocks["my_topic"][0].fetchOffset)
+	require.Equal(t, int64(0), request.blocks["my_topic"][2].fetchOffset)
+	require.Equal(t, map[string][]int32{"my_topic": {1}}, request.forgotten)
+	session.handleResponse(request, &FetchResponse{Version: 10, SessionID: 42})
+
+	require.Equal(t, int64(1), registry.Get("consumer-fetch-session-full-rate").(metrics.Meter).Count())
+	require.Equal(t, int64(2), registry.Get("consumer-fetch-session-incremental-rate").(metrics.Meter).Count())
+	require.Equal(t, int64(3), registry.Get("consumer-fetch-session-partitions").(metrics.Histogram).Count())
+}
+
+func TestFetchSessionErrorFallsBackToFullFetch(t *testing.T) {
+	for _, kerr := range []KError{ErrFetchSessionIDNotFound, ErrInvalidFetchSessionEpoch} {
+		t.Run(kerr.Error(), func(t *testing.T) {
+			registry := metrics.NewRegistry()
+			session := newFetchSession(registry)
+
+			session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+			request := newTestFetchRequest()
+			session.build(request)
+			session.handleResponse(request, &FetchResponse{Version: 10, SessionID: 42})
+
+			session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+			request = newTestFetchRequest()
+			session.build(request)
+			require.Equal(t, int32(42), request.SessionID)
+			session.handleResponse(request, &FetchResponse{Version: 10, ErrorCode: int16(kerr)})
+
+			session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+			request = newTestFetchRequest()
+			session.build(request)
+			require.Equal(t, int32(0), request.SessionID)
+			require.Equal(t, fetchSessionInitialEpoch, request.SessionEpoch)
+			require.Len(t, request.blocks["my_topic"], 1)
+
+			require.Equal(t, int64(1), registry.Get("consumer-fetch-session-error-rate").(metrics.Meter).Count())
+		})
+	}
+}
+
+func TestFetchSessionNotCreated(t *testing.T) {
+	session := newFetchSession(metrics.NewRegistry())
+
+	for range 2 {
+		session.add("my_topic", 0, fetchSessionPartition{fetchOffset: 10, maxBytes: 1024, leaderEpoch: 1})
+		request := newTestFetchRequest()
+		require.True(t, session.build(request))
+		require.Equal(t, int32(0), request.SessionID)
+		require.Equal(t, fetchSessionInitialEpoch, request.SessionEpoch)
+		require.Len(t, request.blocks["my_topic"], 1)
+		session.handleResponse(request, &FetchResponse{Version: 10})
+	}
+
+	require.False(t, session.build(newTestFetchRequest()))
+}
+
+func TestNextFetchSessionEpoch(t *testing.T) {
+	require.Equal(t, int32(2), nextFetchSessionEpoch(1))
+	require.Equal(t, int32(1), nextFetchSessionEpoch(math.MaxInt32))
+}
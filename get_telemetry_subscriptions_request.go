@@ -0,0 +1,63 @@
+package sarama
+
+// GetTelemetrySubscriptionsRequest asks a broker which client metrics, if any,
+// it wants the client to push and how often (KIP-714).
+type GetTelemetrySubscriptionsRequest struct {
+	Version int16
+
+	// ClientInstanceID is the unique identifier of the client instance, or the
+	// zero Uuid on the first request so that the broker assigns one
+	ClientInstanceID Uuid
+}
+
+func (r *GetTelemetrySubscriptionsRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *GetTelemetrySubscriptionsRequest) encode(pe packetEncoder) error {
+	if err := pe.putUuid(r.ClientInstanceID); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *GetTelemetrySubscriptionsRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ClientInstanceID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *GetTelemetrySubscriptionsRequest) key() int16 {
+	return apiKeyGetTelemetrySubscriptions
+}
+
+func (r *GetTelemetrySubscriptionsRequest) version() int16 {
+	return r.Version
+}
+
+func (r *GetTelemetrySubscriptionsRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *GetTelemetrySubscriptionsRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *GetTelemetrySubscriptionsRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *GetTelemetrySubscriptionsRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *GetTelemetrySubscriptionsRequest) requiredVersion() KafkaVersion {
+	return V3_7_0_0
+}
@@ -0,0 +1,161 @@
+package sarama
+
+import "time"
+
+type GetTelemetrySubscriptionsResponse struct {
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+
+	// ClientInstanceID is the assigned client instance id if the request
+	// carried the zero Uuid, otherwise the one that was sent
+	ClientInstanceID Uuid
+
+	// SubscriptionID is the unique identifier of the current subscription
+	SubscriptionID int32
+
+	// AcceptedCompressionTypes contains the compression types the broker
+	// accepts for PushTelemetry requests, in preference order. An empty array
+	// means only uncompressed payloads are accepted
+	AcceptedCompressionTypes []CompressionCodec
+
+	// PushInterval is the configured push interval
+	PushInterval time.Duration
+
+	// TelemetryMaxBytes is the maximum bytes of binary data the broker
+	// accepts in a PushTelemetry request
+	TelemetryMaxBytes int32
+
+	// DeltaTemporality is true if sums should be pushed as deltas since the
+	// previous push, false for cumulative values
+	DeltaTemporality bool
+
+	// RequestedMetrics contains the metric name prefixes the broker wants
+	// pushed. An empty array means no metrics are subscribed, and an array
+	// with a single empty string means all metrics are subscribed
+	RequestedMetrics []string
+}
+
+func (r *GetTelemetrySubscriptionsResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *GetTelemetrySubscriptionsResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.ErrorCode)
+
+	if err := pe.putUuid(r.ClientInstanceID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.SubscriptionID)
+
+	if err := pe.putArrayLength(len(r.AcceptedCompressionTypes)); err != nil {
+		return err
+	}
+	for _, codec := range r.AcceptedCompressionTypes {
+		pe.putInt8(int8(codec))
+	}
+
+	pe.putDurationMs(r.PushInterval)
+	pe.putInt32(r.TelemetryMaxBytes)
+	pe.putBool(r.DeltaTemporality)
+
+	if err := pe.putStringArray(r.RequestedMetrics); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *GetTelemetrySubscriptionsResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ClientInstanceID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if r.SubscriptionID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.AcceptedCompressionTypes = make([]CompressionCodec, n)
+	for i := range r.AcceptedCompressionTypes {
+		codec, err := pd.getInt8()
+		if err != nil {
+			return err
+		}
+		r.AcceptedCompressionTypes[i] = CompressionCodec(codec)
+	}
+
+	if r.PushInterval, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.TelemetryMaxBytes, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.DeltaTemporality, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	if r.RequestedMetrics, err = pd.getStringArray(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *GetTelemetrySubscriptionsResponse) key() int16 {
+	return apiKeyGetTelemetrySubscriptions
+}
+
+func (r *GetTelemetrySubscriptionsResponse) version() int16 {
+	return r.Version
+}
+
+func (r *GetTelemetrySubscriptionsResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *GetTelemetrySubscriptionsResponse) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *GetTelemetrySubscriptionsResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *GetTelemetrySubscriptionsResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *GetTelemetrySubscriptionsResponse) requiredVersion() KafkaVersion {
+	return V3_7_0_0
+}
+
+func (r *GetTelemetrySubscriptionsResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
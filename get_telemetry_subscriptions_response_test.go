@@ -0,0 +1,36 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var getTelemetrySubscriptionsResponseV0 = []byte{
+	0, 0, 0, 0, // ThrottleTime
+	0, 0, // ErrorCode
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // ClientInstanceID
+	0, 0, 0, 7, // SubscriptionID
+	3, 4, 1, // AcceptedCompressionTypes
+	0, 0, 0x75, 0x30, // PushInterval
+	0, 0x10, 0, 0, // TelemetryMaxBytes
+	1,                                    // DeltaTemporality
+	3,                                    // RequestedMetrics
+	8, 's', 'a', 'r', 'a', 'm', 'a', '.', // sarama.
+	5, 'o', 'r', 'g', '.', // org.
+	0, // empty tagged fields
+}
+
+func TestGetTelemetrySubscriptionsResponse(t *testing.T) {
+	response := &GetTelemetrySubscriptionsResponse{
+		ClientInstanceID:         Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SubscriptionID:           7,
+		AcceptedCompressionTypes: []CompressionCodec{CompressionZSTD, CompressionGZIP},
+		PushInterval:             30 * time.Second,
+		TelemetryMaxBytes:        1024 * 1024,
+		DeltaTemporality:         true,
+		RequestedMetrics:         []string{"sarama.", "org."},
+	}
+	testResponse(t, "v0", response, getTelemetrySubscriptionsResponseV0)
+}
@@ -0,0 +1,147 @@
+package sarama
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+)
+
+// keyOrderedQueueSize is the number of messages buffered for each worker of a
+// KeyOrderedClaim, so that a slow key does not hold back the other workers
+// straight away.
+const keyOrderedQueueSize = 256
+
+// KeyOrderedClaim wraps a ConsumerGroupClaim to process its messages on several
+// workers. Messages are assigned to the workers by a hash of their key, so the
+// messages sharing a key are processed one at a time and in order, while
+// messages with different keys are processed concurrently. Messages without a
+// key are spread over the workers.
+//
+// Offsets are marked with ConsumerGroupSession.MarkOffset once every message up
+// to them has been processed, so a message is never committed before an earlier
+// one handled by another worker. As with Consumer.Offsets.Ack, no more messages
+// are taken from the claim while Consumer.Offsets.Ack.MaxPending of them are
+// waiting for themselves or an earlier message to be processed.
+type KeyOrderedClaim struct {
+	session    ConsumerGroupSession
+	claim      ConsumerGroupClaim
+	workers    int
+	maxPending int
+}
+
+// NewKeyOrderedClaim wraps a claim, typically from ConsumerGroupHandler.ConsumeClaim,
+// to process its messages on the given number of workers.
+func NewKeyOrderedClaim(session ConsumerGroupSession, claim ConsumerGroupClaim, workers int) *KeyOrderedClaim {
+	maxPending := defaultAckMaxPending
+	if s, ok := session.(*consumerGroupSession); ok {
+		maxPending = s.parent.config.Consumer.Offsets.Ack.MaxPending
+	}
+	return &KeyOrderedClaim{
+		session:    session,
+		claim:      claim,
+		workers:    workers,
+		maxPending: maxPending,
+	}
+}
+
+// Process hands the messages of the claim over to fn on the workers, until the
+// Messages() channel of the claim is closed, the session context is done or fn
+// returns an error, which is then returned.
+//
+// It only returns once the workers have stopped and the offsets of the processed
+// messages are marked, so that the partition is drained by the time the
+// handler's Cleanup is called when a rebalance revokes it. When the session ends,
+// each worker finishes the message it is processing and drops the ones queued
+// behind it, which are left for the next owner of the partition.
+func (c *KeyOrderedClaim) Process(fn func(*ConsumerMessage) error) error {
+	if c.workers <= 0 {
+		return ConfigurationError("KeyOrderedClaim needs at least one worker")
+	}
+
+	ctx, cancel := context.WithCancel(c.session.Context())
+	defer cancel()
+
+	var (
+		failure  error
+		failOnce sync.Once
+	)
+
+	tracker := newAckTracker(c.maxPending)
+	// acked wakes the dispatch loop up when it waits for the tracker to have
+	// room again
+	acked := make(chan none, 1)
+	queues := make([]chan *ConsumerMessage, c.workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan *ConsumerMessage, keyOrderedQueueSize)
+		wg.Add(1)
+		go func(queue <-chan *ConsumerMessage) {
+			defer wg.Done()
+			for msg := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				if err := fn(msg); err != nil {
+					failOnce.Do(func() { failure = err })
+					cancel()
+					return
+				}
+				c.ack(tracker, msg)
+				select {
+				case acked <- none{}:
+				default:
+				}
+			}
+		}(queues[i])
+	}
+
+dispatch:
+	for {
+		var messages <-chan *ConsumerMessage
+		if !tracker.full() {
+			messages = c.claim.Messages()
+		}
+
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				break dispatch
+			}
+			tracker.deliver(msg)
+
+			select {
+			case queues[keyOrderedWorker(msg, c.workers)] <- msg:
+			case <-ctx.Done():
+				break dispatch
+			}
+		case <-acked:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	return failure
+}
+
+func (c *KeyOrderedClaim) ack(tracker *ackTracker, msg *ConsumerMessage) {
+	next, err := tracker.ack(msg.Offset)
+	if err != nil {
+		return
+	}
+	c.session.MarkOffset(msg.Topic, msg.Partition, next, "")
+}
+
+// keyOrderedWorker returns the worker that a message is handed to.
+func keyOrderedWorker(msg *ConsumerMessage, workers int) int {
+	if msg.Key == nil {
+		return int(msg.Offset % int64(workers))
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(msg.Key)
+	return int(hasher.Sum32() % uint32(workers))
+}
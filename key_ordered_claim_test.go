@@ -0,0 +1,168 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testGroupSession struct {
+	ctx context.Context
+
+	lock   sync.Mutex
+	marked int64
+}
+
+func (s *testGroupSession) Claims() map[string][]int32 { return nil }
+func (s *testGroupSession) MemberID() string           { return "" }
+func (s *testGroupSession) GenerationID() int32        { return 0 }
+func (s *testGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.marked = max(s.marked, offset)
+}
+func (s *testGroupSession) Commit() {}
+func (s *testGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (s *testGroupSession) MarkMessage(msg *ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+func (s *testGroupSession) AckMessage(msg *ConsumerMessage) error { return nil }
+func (s *testGroupSession) Context() context.Context              { return s.ctx }
+
+func (s *testGroupSession) markedOffset() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.marked
+}
+
+type testGroupClaim struct {
+	messages chan *ConsumerMessage
+}
+
+func (c *testGroupClaim) Topic() string                     { return "my-topic" }
+func (c *testGroupClaim) Partition() int32                  { return 0 }
+func (c *testGroupClaim) InitialOffset() int64              { return 0 }
+func (c *testGroupClaim) HighWaterMarkOffset() int64        { return 0 }
+func (c *testGroupClaim) Lag() ConsumerLag                  { return ConsumerLag{} }
+func (c *testGroupClaim) Messages() <-chan *ConsumerMessage { return c.messages }
+
+func TestKeyOrderedClaimKeepsKeyOrder(t *testing.T) {
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 100)}
+	for i := range 100 {
+		claim.messages <- &ConsumerMessage{
+			Topic:  "my-topic",
+			Offset: int64(i),
+			Key:    fmt.Appendf(nil, "key-%d", i%7),
+		}
+	}
+	close(claim.messages)
+
+	var lock sync.Mutex
+	processed := make(map[string][]int64)
+	err := NewKeyOrderedClaim(session, claim, 4).Process(func(msg *ConsumerMessage) error {
+		lock.Lock()
+		defer lock.Unlock()
+		processed[string(msg.Key)] = append(processed[string(msg.Key)], msg.Offset)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, processed, 7)
+	for key, offsets := range processed {
+		require.IsIncreasing(t, offsets, "messages of %s processed out of order", key)
+	}
+	require.Equal(t, int64(100), session.markedOffset())
+}
+
+func TestKeyOrderedClaimMarksFinishedOffsets(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	session := &testGroupSession{ctx: ctx}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage)}
+
+	release := make(chan none)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewKeyOrderedClaim(session, claim, 2).Process(func(msg *ConsumerMessage) error {
+			if string(msg.Key) == "slow" {
+				<-release
+			}
+			return nil
+		})
+	}()
+
+	// find a key that is not handled by the same worker as the slow one
+	worker := func(key string) int {
+		return keyOrderedWorker(&ConsumerMessage{Key: []byte(key)}, 2)
+	}
+	fast := "fast"
+	for i := 0; worker(fast) == worker("slow"); i++ {
+		fast = fmt.Sprintf("fast-%d", i)
+	}
+
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 0, Key: []byte("slow")}
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 1, Key: []byte(fast)}
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int64(0), session.markedOffset(), "offset 1 must wait for offset 0")
+
+	close(release)
+	require.Eventually(t, func() bool { return session.markedOffset() == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestKeyOrderedClaimBoundsPendingMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	session := &testGroupSession{ctx: ctx}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage)}
+
+	release := make(chan none)
+	done := make(chan error, 1)
+	keyOrdered := NewKeyOrderedClaim(session, claim, 2)
+	keyOrdered.maxPending = 2
+	go func() {
+		done <- keyOrdered.Process(func(msg *ConsumerMessage) error {
+			if msg.Offset == 0 {
+				<-release
+			}
+			return nil
+		})
+	}()
+
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 0, Key: []byte("slow")}
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 1, Key: []byte("slow")}
+	select {
+	case claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 2}:
+		t.Fatal("a message was taken while MaxPending messages were waiting to be marked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 2}
+	require.Eventually(t, func() bool { return session.markedOffset() == 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestKeyOrderedClaimReturnsProcessingError(t *testing.T) {
+	session := &testGroupSession{ctx: t.Context()}
+	claim := &testGroupClaim{messages: make(chan *ConsumerMessage, 1)}
+	claim.messages <- &ConsumerMessage{Topic: "my-topic", Offset: 0}
+
+	failed := errors.New("failed")
+	err := NewKeyOrderedClaim(session, claim, 2).Process(func(msg *ConsumerMessage) error {
+		return failed
+	})
+	require.ErrorIs(t, err, failed)
+	require.Equal(t, int64(0), session.markedOffset())
+}
@@ -20,6 +20,14 @@ type PartitionMetadata struct {
 	Isr []int32
 	// OfflineReplicas contains the set of offline replicas of this partition.
 	OfflineReplicas []int32
+	// EligibleLeaderReplicas contains the replicas, not in the ISR, that are
+	// eligible to become leader (KIP-966), or nil if ELR is disabled. It is only
+	// populated from a DescribeTopicPartitionsResponse.
+	EligibleLeaderReplicas []int32
+	// LastKnownELR contains the last known eligible leader replicas, or nil if
+	// ELR is disabled. It is only populated from a
+	// DescribeTopicPartitionsResponse.
+	LastKnownELR []int32
 }
 
 func (p *PartitionMetadata) decode(pd packetDecoder, version int16) (err error) {
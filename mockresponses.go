@@ -2,8 +2,10 @@ package sarama
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 // TestReporter has methods matching go's testing.T to avoid importing
@@ -138,15 +140,17 @@ type MockMetadataResponse struct {
 	errors       map[string]KError
 	leaders      map[string]map[int32]int32
 	brokers      map[string]int32
+	topicIDs     map[string]Uuid
 	t            TestReporter
 }
 
 func NewMockMetadataResponse(t TestReporter) *MockMetadataResponse {
 	return &MockMetadataResponse{
-		errors:  make(map[string]KError),
-		leaders: make(map[string]map[int32]int32),
-		brokers: make(map[string]int32),
-		t:       t,
+		errors:   make(map[string]KError),
+		leaders:  make(map[string]map[int32]int32),
+		brokers:  make(map[string]int32),
+		topicIDs: make(map[string]Uuid),
+		t:        t,
 	}
 }
 
@@ -175,6 +179,12 @@ func (mmr *MockMetadataResponse) SetController(brokerID int32) *MockMetadataResp
 	return mmr
 }
 
+// SetTopicID sets the topic id returned for the topic from MetadataResponse v10 onwards.
+func (mmr *MockMetadataResponse) SetTopicID(topic string, id Uuid) *MockMetadataResponse {
+	mmr.topicIDs[topic] = id
+	return mmr
+}
+
 func (mmr *MockMetadataResponse) For(reqBody versionedDecoder) encoderWithHeader {
 	metadataRequest := reqBody.(*MetadataRequest)
 	metadataResponse := &MetadataResponse{
@@ -201,6 +211,7 @@ func (mmr *MockMetadataResponse) For(reqBody versionedDecoder) encoderWithHeader
 		for topic, err := range mmr.errors {
 			metadataResponse.AddTopic(topic, err)
 		}
+		mmr.setTopicIDs(metadataResponse)
 		return metadataResponse
 	}
 	for _, topic := range metadataRequest.Topics {
@@ -217,9 +228,16 @@ func (mmr *MockMetadataResponse) For(reqBody versionedDecoder) encoderWithHeader
 			metadataResponse.AddTopicPartition(topic, partition, brokerID, replicas, replicas, offlineReplicas, ErrNoError)
 		}
 	}
+	mmr.setTopicIDs(metadataResponse)
 	return metadataResponse
 }
 
+func (mmr *MockMetadataResponse) setTopicIDs(metadataResponse *MetadataResponse) {
+	for _, topic := range metadataResponse.Topics {
+		topic.Uuid = mmr.topicIDs[topic.Name]
+	}
+}
+
 // MockOffsetResponse is an `OffsetResponse` builder.
 type MockOffsetResponse struct {
 	offsets map[string]map[int32]map[int64]int64
@@ -404,6 +422,66 @@ func (mfr *MockFetchResponse) getHighWaterMark(topic string, partition int32) in
 	return partitions[partition]
 }
 
+type mockEpochEndOffset struct {
+	leaderEpoch int32
+	endOffset   int64
+}
+
+// MockOffsetForLeaderEpochResponse is an `OffsetForLeaderEpochResponse`
+// builder. Partitions without a registered end offset are answered with
+// ErrUnknownTopicOrPartition.
+type MockOffsetForLeaderEpochResponse struct {
+	t          TestReporter
+	endOffsets map[string]map[int32]mockEpochEndOffset
+	errors     map[string]map[int32]KError
+}
+
+func NewMockOffsetForLeaderEpochResponse(t TestReporter) *MockOffsetForLeaderEpochResponse {
+	return &MockOffsetForLeaderEpochResponse{
+		t:          t,
+		endOffsets: make(map[string]map[int32]mockEpochEndOffset),
+		errors:     make(map[string]map[int32]KError),
+	}
+}
+
+// SetEndOffset sets the epoch and end offset returned for a topic/partition,
+// regardless of the epoch requested.
+func (m *MockOffsetForLeaderEpochResponse) SetEndOffset(topic string, partition int32, leaderEpoch int32, endOffset int64) *MockOffsetForLeaderEpochResponse {
+	if m.endOffsets[topic] == nil {
+		m.endOffsets[topic] = make(map[int32]mockEpochEndOffset)
+	}
+	m.endOffsets[topic][partition] = mockEpochEndOffset{leaderEpoch: leaderEpoch, endOffset: endOffset}
+	return m
+}
+
+func (m *MockOffsetForLeaderEpochResponse) SetError(topic string, partition int32, kerror KError) *MockOffsetForLeaderEpochResponse {
+	if m.errors[topic] == nil {
+		m.errors[topic] = make(map[int32]KError)
+	}
+	m.errors[topic][partition] = kerror
+	return m
+}
+
+func (m *MockOffsetForLeaderEpochResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*OffsetForLeaderEpochRequest)
+	res := &OffsetForLeaderEpochResponse{Version: req.version()}
+	for _, topic := range req.Topics {
+		for _, partition := range topic.Partitions {
+			if kerror, ok := m.errors[topic.Topic][partition.Partition]; ok {
+				res.AddPartition(topic.Topic, partition.Partition, kerror, -1, -1)
+				continue
+			}
+			epochEnd, ok := m.endOffsets[topic.Topic][partition.Partition]
+			if !ok {
+				res.AddPartition(topic.Topic, partition.Partition, ErrUnknownTopicOrPartition, -1, -1)
+				continue
+			}
+			res.AddPartition(topic.Topic, partition.Partition, ErrNoError, epochEnd.leaderEpoch, epochEnd.endOffset)
+		}
+	}
+	return res
+}
+
 // MockConsumerMetadataResponse is a `ConsumerMetadataResponse` builder.
 type MockConsumerMetadataResponse struct {
 	coordinators map[string]any
@@ -1462,6 +1540,24 @@ func (m *MockDescribeLogDirsResponse) SetLogDirs(logDirPath string, topicPartiti
 	return m
 }
 
+// AddFutureReplica adds a log dir holding the future replica of a partition
+// that is being moved to it by AlterReplicaLogDirs.
+func (m *MockDescribeLogDirsResponse) AddFutureReplica(logDirPath string, topic string, partition int32, offsetLag int64) *MockDescribeLogDirsResponse {
+	m.logDirs = append(m.logDirs, DescribeLogDirsResponseDirMetadata{
+		ErrorCode: ErrNoError,
+		Path:      logDirPath,
+		Topics: []DescribeLogDirsResponseTopic{{
+			Topic: topic,
+			Partitions: []DescribeLogDirsResponsePartition{{
+				PartitionID: partition,
+				IsTemporary: true,
+				OffsetLag:   offsetLag,
+			}},
+		}},
+	})
+	return m
+}
+
 func (m *MockDescribeLogDirsResponse) For(reqBody versionedDecoder) encoderWithHeader {
 	req := reqBody.(*DescribeLogDirsRequest)
 	resp := &DescribeLogDirsResponse{
@@ -1471,6 +1567,47 @@ func (m *MockDescribeLogDirsResponse) For(reqBody versionedDecoder) encoderWithH
 	return resp
 }
 
+// MockAlterReplicaLogDirsResponse is an `AlterReplicaLogDirsResponse` builder.
+// It echoes back the requested partitions, populating each with any error code
+// registered for it.
+type MockAlterReplicaLogDirsResponse struct {
+	t      TestReporter
+	errors map[string]map[int32]KError
+}
+
+func NewMockAlterReplicaLogDirsResponse(t TestReporter) *MockAlterReplicaLogDirsResponse {
+	return &MockAlterReplicaLogDirsResponse{
+		t:      t,
+		errors: make(map[string]map[int32]KError),
+	}
+}
+
+func (m *MockAlterReplicaLogDirsResponse) SetError(topic string, partition int32, kerror KError) *MockAlterReplicaLogDirsResponse {
+	if m.errors[topic] == nil {
+		m.errors[topic] = make(map[int32]KError)
+	}
+	m.errors[topic][partition] = kerror
+	return m
+}
+
+func (m *MockAlterReplicaLogDirsResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*AlterReplicaLogDirsRequest)
+	res := &AlterReplicaLogDirsResponse{Version: req.version()}
+	for _, dir := range req.Dirs {
+		for _, topic := range dir.Topics {
+			result := AlterReplicaLogDirsResponseTopic{TopicName: topic.Name}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, AlterReplicaLogDirsResponsePartition{
+					PartitionIndex: partition,
+					ErrorCode:      m.errors[topic.Name][partition],
+				})
+			}
+			res.Results = append(res.Results, result)
+		}
+	}
+	return res
+}
+
 type MockApiVersionsResponse struct {
 	t       TestReporter
 	apiKeys []ApiVersionsResponseKey
@@ -1710,3 +1847,319 @@ func (m *MockListTransactionsResponse) LastRequest() ListTransactionsRequest {
 	defer m.mu.Unlock()
 	return m.lastRequest
 }
+
+// MockWriteTxnMarkersResponse is a `WriteTxnMarkersResponse` builder. It echoes
+// back the requested markers, populating each partition with any error code
+// registered for it.
+type MockWriteTxnMarkersResponse struct {
+	t      TestReporter
+	errors map[string]map[int32]KError
+}
+
+func NewMockWriteTxnMarkersResponse(t TestReporter) *MockWriteTxnMarkersResponse {
+	return &MockWriteTxnMarkersResponse{
+		t:      t,
+		errors: make(map[string]map[int32]KError),
+	}
+}
+
+func (m *MockWriteTxnMarkersResponse) SetError(topic string, partition int32, kerror KError) *MockWriteTxnMarkersResponse {
+	if m.errors[topic] == nil {
+		m.errors[topic] = make(map[int32]KError)
+	}
+	m.errors[topic][partition] = kerror
+	return m
+}
+
+func (m *MockWriteTxnMarkersResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*WriteTxnMarkersRequest)
+	res := &WriteTxnMarkersResponse{Version: req.version()}
+	for _, marker := range req.Markers {
+		result := WritableTxnMarkerResult{ProducerID: marker.ProducerID}
+		for _, topic := range marker.Topics {
+			topicResult := WritableTxnMarkerTopicResult{Name: topic.Name}
+			for _, partition := range topic.PartitionIndexes {
+				topicResult.Partitions = append(topicResult.Partitions, WritableTxnMarkerPartitionResult{
+					PartitionIndex: partition,
+					ErrorCode:      m.errors[topic.Name][partition],
+				})
+			}
+			result.Topics = append(result.Topics, topicResult)
+		}
+		res.Markers = append(res.Markers, result)
+	}
+	return res
+}
+
+// MockDescribeQuorumResponse is a `DescribeQuorumResponse` builder describing
+// the metadata quorum.
+type MockDescribeQuorumResponse struct {
+	t             TestReporter
+	leaderID      int32
+	leaderEpoch   int32
+	highWatermark int64
+	voters        []QuorumReplicaState
+	observers     []QuorumReplicaState
+	errorCode     KError
+}
+
+func NewMockDescribeQuorumResponse(t TestReporter) *MockDescribeQuorumResponse {
+	return &MockDescribeQuorumResponse{t: t, leaderID: -1}
+}
+
+func (m *MockDescribeQuorumResponse) SetLeader(leaderID, leaderEpoch int32, highWatermark int64) *MockDescribeQuorumResponse {
+	m.leaderID = leaderID
+	m.leaderEpoch = leaderEpoch
+	m.highWatermark = highWatermark
+	return m
+}
+
+func (m *MockDescribeQuorumResponse) AddVoter(replicaID int32, logEndOffset int64) *MockDescribeQuorumResponse {
+	m.voters = append(m.voters, m.replicaState(replicaID, logEndOffset))
+	return m
+}
+
+func (m *MockDescribeQuorumResponse) AddObserver(replicaID int32, logEndOffset int64) *MockDescribeQuorumResponse {
+	m.observers = append(m.observers, m.replicaState(replicaID, logEndOffset))
+	return m
+}
+
+func (m *MockDescribeQuorumResponse) SetError(kerror KError) *MockDescribeQuorumResponse {
+	m.errorCode = kerror
+	return m
+}
+
+func (m *MockDescribeQuorumResponse) replicaState(replicaID int32, logEndOffset int64) QuorumReplicaState {
+	now := time.Now().UnixMilli()
+	return QuorumReplicaState{
+		ReplicaID:             replicaID,
+		LogEndOffset:          logEndOffset,
+		LastFetchTimestamp:    now,
+		LastCaughtUpTimestamp: now,
+	}
+}
+
+func (m *MockDescribeQuorumResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*DescribeQuorumRequest)
+	res := &DescribeQuorumResponse{Version: req.version()}
+	for _, topic := range req.Topics {
+		resTopic := DescribeQuorumResponseTopic{TopicName: topic.TopicName}
+		for _, partition := range topic.Partitions {
+			resTopic.Partitions = append(resTopic.Partitions, DescribeQuorumResponsePartition{
+				PartitionIndex: partition,
+				ErrorCode:      m.errorCode,
+				LeaderID:       m.leaderID,
+				LeaderEpoch:    m.leaderEpoch,
+				HighWatermark:  m.highWatermark,
+				CurrentVoters:  m.voters,
+				Observers:      m.observers,
+			})
+		}
+		res.Topics = append(res.Topics, resTopic)
+	}
+	return res
+}
+
+// MockGetTelemetrySubscriptionsResponse is a
+// `GetTelemetrySubscriptionsResponse` builder. Clients that do not yet have
+// an instance id are assigned the builder's.
+type MockGetTelemetrySubscriptionsResponse struct {
+	t                TestReporter
+	instanceID       Uuid
+	subscriptionID   int32
+	pushInterval     time.Duration
+	requestedMetrics []string
+	compressionTypes []CompressionCodec
+	deltaTemporality bool
+	errorCode        KError
+}
+
+func NewMockGetTelemetrySubscriptionsResponse(t TestReporter) *MockGetTelemetrySubscriptionsResponse {
+	return &MockGetTelemetrySubscriptionsResponse{
+		t:                t,
+		instanceID:       Uuid{1},
+		compressionTypes: []CompressionCodec{},
+		requestedMetrics: []string{},
+	}
+}
+
+func (m *MockGetTelemetrySubscriptionsResponse) SetSubscription(subscriptionID int32, pushInterval time.Duration, requestedMetrics ...string) *MockGetTelemetrySubscriptionsResponse {
+	m.subscriptionID = subscriptionID
+	m.pushInterval = pushInterval
+	m.requestedMetrics = requestedMetrics
+	return m
+}
+
+func (m *MockGetTelemetrySubscriptionsResponse) SetAcceptedCompressionTypes(codecs ...CompressionCodec) *MockGetTelemetrySubscriptionsResponse {
+	m.compressionTypes = codecs
+	return m
+}
+
+func (m *MockGetTelemetrySubscriptionsResponse) SetDeltaTemporality(delta bool) *MockGetTelemetrySubscriptionsResponse {
+	m.deltaTemporality = delta
+	return m
+}
+
+func (m *MockGetTelemetrySubscriptionsResponse) SetError(kerror KError) *MockGetTelemetrySubscriptionsResponse {
+	m.errorCode = kerror
+	return m
+}
+
+func (m *MockGetTelemetrySubscriptionsResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*GetTelemetrySubscriptionsRequest)
+	res := &GetTelemetrySubscriptionsResponse{
+		Version:                  req.version(),
+		ErrorCode:                m.errorCode,
+		ClientInstanceID:         req.ClientInstanceID,
+		SubscriptionID:           m.subscriptionID,
+		AcceptedCompressionTypes: m.compressionTypes,
+		PushInterval:             m.pushInterval,
+		TelemetryMaxBytes:        1024 * 1024,
+		DeltaTemporality:         m.deltaTemporality,
+		RequestedMetrics:         m.requestedMetrics,
+	}
+	if req.ClientInstanceID == (Uuid{}) {
+		res.ClientInstanceID = m.instanceID
+	}
+	return res
+}
+
+// MockPushTelemetryResponse is a `PushTelemetryResponse` builder.
+type MockPushTelemetryResponse struct {
+	t         TestReporter
+	errorCode KError
+}
+
+func NewMockPushTelemetryResponse(t TestReporter) *MockPushTelemetryResponse {
+	return &MockPushTelemetryResponse{t: t}
+}
+
+func (m *MockPushTelemetryResponse) SetError(kerror KError) *MockPushTelemetryResponse {
+	m.errorCode = kerror
+	return m
+}
+
+func (m *MockPushTelemetryResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*PushTelemetryRequest)
+	return &PushTelemetryResponse{Version: req.version(), ErrorCode: m.errorCode}
+}
+
+// MockDescribeTopicPartitionsResponse is a `DescribeTopicPartitionsResponse`
+// builder that pages through the registered partitions the way a broker does.
+type MockDescribeTopicPartitionsResponse struct {
+	t              TestReporter
+	partitions     map[string][]DescribeTopicPartitionsResponsePartition
+	partitionLimit int32
+}
+
+func NewMockDescribeTopicPartitionsResponse(t TestReporter) *MockDescribeTopicPartitionsResponse {
+	return &MockDescribeTopicPartitionsResponse{
+		t:          t,
+		partitions: make(map[string][]DescribeTopicPartitionsResponsePartition),
+	}
+}
+
+func (m *MockDescribeTopicPartitionsResponse) SetPartition(topic string, partition, leader int32, replicas, isr []int32) *MockDescribeTopicPartitionsResponse {
+	m.partitions[topic] = append(m.partitions[topic], DescribeTopicPartitionsResponsePartition{
+		PartitionIndex:  partition,
+		LeaderID:        leader,
+		ReplicaNodes:    replicas,
+		IsrNodes:        isr,
+		OfflineReplicas: []int32{},
+	})
+	slices.SortFunc(m.partitions[topic], func(a, b DescribeTopicPartitionsResponsePartition) int {
+		return int(a.PartitionIndex - b.PartitionIndex)
+	})
+	return m
+}
+
+func (m *MockDescribeTopicPartitionsResponse) SetEligibleLeaderReplicas(topic string, partition int32, elr, lastKnownELR []int32) *MockDescribeTopicPartitionsResponse {
+	for i := range m.partitions[topic] {
+		if m.partitions[topic][i].PartitionIndex == partition {
+			m.partitions[topic][i].EligibleLeaderReplicas = elr
+			m.partitions[topic][i].LastKnownELR = lastKnownELR
+		}
+	}
+	return m
+}
+
+// SetResponsePartitionLimit caps the number of partitions per response below
+// the limit requested by the client, like the broker's
+// max.request.partition.size.limit.
+func (m *MockDescribeTopicPartitionsResponse) SetResponsePartitionLimit(limit int32) *MockDescribeTopicPartitionsResponse {
+	m.partitionLimit = limit
+	return m
+}
+
+func (m *MockDescribeTopicPartitionsResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*DescribeTopicPartitionsRequest)
+	res := &DescribeTopicPartitionsResponse{Version: req.version()}
+
+	topics := slices.Clone(req.Topics)
+	if len(topics) == 0 {
+		for topic := range m.partitions {
+			topics = append(topics, topic)
+		}
+	}
+	slices.Sort(topics)
+
+	limit := req.ResponsePartitionLimit
+	if m.partitionLimit > 0 && (limit <= 0 || m.partitionLimit < limit) {
+		limit = m.partitionLimit
+	}
+
+	var count int32
+	for _, topic := range topics {
+		if req.Cursor != nil && topic < req.Cursor.TopicName {
+			continue
+		}
+		name := topic
+		partitions, ok := m.partitions[topic]
+		if !ok {
+			res.Topics = append(res.Topics, DescribeTopicPartitionsResponseTopic{
+				ErrorCode: ErrUnknownTopicOrPartition,
+				Name:      &name,
+			})
+			continue
+		}
+		resTopic := DescribeTopicPartitionsResponseTopic{Name: &name}
+		for _, partition := range partitions {
+			if req.Cursor != nil && topic == req.Cursor.TopicName && partition.PartitionIndex < req.Cursor.PartitionIndex {
+				continue
+			}
+			if limit > 0 && count == limit {
+				res.NextCursor = &DescribeTopicPartitionsCursor{TopicName: topic, PartitionIndex: partition.PartitionIndex}
+				break
+			}
+			resTopic.Partitions = append(resTopic.Partitions, partition)
+			count++
+		}
+		if len(resTopic.Partitions) > 0 {
+			res.Topics = append(res.Topics, resTopic)
+		}
+		if res.NextCursor != nil {
+			break
+		}
+	}
+	return res
+}
+
+// MockUnregisterBrokerResponse is an `UnregisterBrokerResponse` builder.
+type MockUnregisterBrokerResponse struct {
+	t         TestReporter
+	errorCode KError
+}
+
+func NewMockUnregisterBrokerResponse(t TestReporter) *MockUnregisterBrokerResponse {
+	return &MockUnregisterBrokerResponse{t: t}
+}
+
+func (m *MockUnregisterBrokerResponse) SetError(kerror KError) *MockUnregisterBrokerResponse {
+	m.errorCode = kerror
+	return m
+}
+
+func (m *MockUnregisterBrokerResponse) For(reqBody versionedDecoder) encoderWithHeader {
+	req := reqBody.(*UnregisterBrokerRequest)
+	return &UnregisterBrokerResponse{Version: req.version(), ErrorCode: m.errorCode}
+}
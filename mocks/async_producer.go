@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -25,6 +26,7 @@ type AsyncProducer struct {
 	txnLock         sync.Mutex
 	txnStatus       sarama.ProducerTxnStatusFlag
 	lastOffset      int64
+	flushes         map[*sarama.ProducerMessage]chan struct{}
 	*TopicConfig
 }
 
@@ -48,6 +50,7 @@ func NewAsyncProducer(t ErrorReporter, config *sarama.Config) *AsyncProducer {
 		errors:          make(chan *sarama.ProducerError, config.ChannelBufferSize),
 		isTransactional: config.Producer.Transaction.ID != "",
 		txnStatus:       sarama.ProducerTxnFlagReady,
+		flushes:         make(map[*sarama.ProducerMessage]chan struct{}),
 		TopicConfig:     NewTopicConfig(),
 	}
 
@@ -61,6 +64,15 @@ func NewAsyncProducer(t ErrorReporter, config *sarama.Config) *AsyncProducer {
 		partitioners := make(map[string]sarama.Partitioner, 1)
 
 		for msg := range mp.input {
+			mp.l.Lock()
+			flushed, isFlush := mp.flushes[msg]
+			delete(mp.flushes, msg)
+			mp.l.Unlock()
+			if isFlush {
+				close(flushed)
+				continue
+			}
+
 			mp.txnLock.Lock()
 			if mp.IsTransactional() && mp.txnStatus&sarama.ProducerTxnFlagInTransaction == 0 {
 				mp.t.Errorf("attempt to send message when transaction is not started or is in ending state.")
@@ -84,6 +96,9 @@ func NewAsyncProducer(t ErrorReporter, config *sarama.Config) *AsyncProducer {
 				partition, err := partitioner.Partition(msg, mp.partitions(msg.Topic))
 				if err != nil {
 					mp.t.Errorf("Partitioner returned an error: %s", err.Error())
+					if msg.OnDelivery != nil {
+						msg.OnDelivery(msg, err)
+					}
 					mp.errors <- &sarama.ProducerError{Err: err, Msg: msg}
 				} else {
 					msg.Partition = partition
@@ -96,12 +111,20 @@ func NewAsyncProducer(t ErrorReporter, config *sarama.Config) *AsyncProducer {
 					}
 					if errors.Is(expectation.Result, errProduceSuccess) {
 						mp.lastOffset++
+						msg.Offset = mp.lastOffset
+						if msg.OnDelivery != nil {
+							msg.OnDelivery(msg, nil)
+						}
 						if config.Producer.Return.Successes {
-							msg.Offset = mp.lastOffset
 							mp.successes <- msg
 						}
-					} else if config.Producer.Return.Errors {
-						mp.errors <- &sarama.ProducerError{Err: expectation.Result, Msg: msg}
+					} else {
+						if msg.OnDelivery != nil {
+							msg.OnDelivery(msg, expectation.Result)
+						}
+						if config.Producer.Return.Errors {
+							mp.errors <- &sarama.ProducerError{Err: expectation.Result, Msg: msg}
+						}
 					}
 				}
 			}
@@ -147,6 +170,48 @@ func (mp *AsyncProducer) Input() chan<- *sarama.ProducerMessage {
 	return mp.input
 }
 
+// SendContext corresponds with the SendContext method of sarama's Producer implementation.
+// It writes the message to the Input channel unless the context is done first.
+func (mp *AsyncProducer) SendContext(ctx context.Context, msg *sarama.ProducerMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case mp.input <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush corresponds with the Flush method of sarama's Producer implementation.
+// It waits until every message written to the Input channel before the call has
+// been handled according to its expectation, unless the context is done first.
+func (mp *AsyncProducer) Flush(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	marker := &sarama.ProducerMessage{}
+	flushed := make(chan struct{})
+	mp.l.Lock()
+	mp.flushes[marker] = flushed
+	mp.l.Unlock()
+	select {
+	case mp.input <- marker:
+	case <-ctx.Done():
+		mp.l.Lock()
+		delete(mp.flushes, marker)
+		mp.l.Unlock()
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Successes corresponds with the Successes method of sarama's Producer implementation.
 func (mp *AsyncProducer) Successes() <-chan *sarama.ProducerMessage {
 	return mp.successes
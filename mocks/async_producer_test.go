@@ -3,10 +3,12 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/IBM/sarama"
@@ -77,6 +79,62 @@ func TestProducerReturnsExpectationsToChannels(t *testing.T) {
 	}
 }
 
+func TestProducerCallsOnDelivery(t *testing.T) {
+	config := NewTestConfig()
+	config.Producer.Return.Errors = false
+	mp := NewAsyncProducer(t, config).
+		ExpectInputAndSucceed().
+		ExpectInputAndFail(sarama.ErrOutOfBrokers)
+
+	delivered := make(chan error, 2)
+	onDelivery := func(msg *sarama.ProducerMessage, err error) {
+		delivered <- err
+	}
+	if err := mp.SendContext(context.Background(), &sarama.ProducerMessage{Topic: "test 1", OnDelivery: onDelivery}); err != nil {
+		t.Error(err)
+	}
+	if err := mp.SendContext(context.Background(), &sarama.ProducerMessage{Topic: "test 2", OnDelivery: onDelivery}); err != nil {
+		t.Error(err)
+	}
+
+	if err := <-delivered; err != nil {
+		t.Error("Expected message 1 to be delivered, got", err)
+	}
+	if err := <-delivered; !errors.Is(err, sarama.ErrOutOfBrokers) {
+		t.Error("Expected message 2 to fail, got", err)
+	}
+
+	if err := mp.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProducerFlush(t *testing.T) {
+	config := NewTestConfig()
+	config.Producer.Return.Errors = false
+	mp := NewAsyncProducer(t, config).
+		ExpectInputAndSucceed().
+		ExpectInputAndSucceed()
+
+	var delivered atomic.Int32
+	onDelivery := func(msg *sarama.ProducerMessage, err error) {
+		delivered.Add(1)
+	}
+	mp.Input() <- &sarama.ProducerMessage{Topic: "test 1", OnDelivery: onDelivery}
+	mp.Input() <- &sarama.ProducerMessage{Topic: "test 2", OnDelivery: onDelivery}
+
+	if err := mp.Flush(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if n := delivered.Load(); n != 2 {
+		t.Error("Expected both messages to be delivered after flushing, got", n)
+	}
+
+	if err := mp.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestProducerWithTooFewExpectations(t *testing.T) {
 	trm := newTestReporterMock()
 	mp := NewAsyncProducer(trm, nil)
@@ -3,6 +3,7 @@ package mocks
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/IBM/sarama"
 )
@@ -342,6 +343,12 @@ func (pc *PartitionConsumer) HighWaterMarkOffset() int64 {
 	return pc.highWaterMarkOffset.Load()
 }
 
+// Lag implements the Lag method from the sarama.PartitionConsumer interface.
+// The mock reports the messages that were yielded but not yet consumed.
+func (pc *PartitionConsumer) Lag() sarama.ConsumerLag {
+	return sarama.ConsumerLag{Messages: int64(len(pc.messages))}
+}
+
 // Pause implements the Pause method from the sarama.PartitionConsumer interface.
 func (pc *PartitionConsumer) Pause() {
 	pc.l.Lock()
@@ -374,6 +381,29 @@ func (pc *PartitionConsumer) IsPaused() bool {
 	return pc.paused
 }
 
+// Seek implements the Seek method from the sarama.PartitionConsumer interface.
+// The mock has no log to reposition in, so it only discards the messages that
+// were yielded but not yet consumed.
+func (pc *PartitionConsumer) Seek(offset int64) error {
+	pc.l.Lock()
+	defer pc.l.Unlock()
+
+	for len(pc.messages) > 0 {
+		<-pc.messages
+	}
+	for len(pc.suppressedMessages) > 0 {
+		<-pc.suppressedMessages
+	}
+	return nil
+}
+
+// SeekToTimestamp implements the SeekToTimestamp method from the
+// sarama.PartitionConsumer interface. Like Seek, it only discards the
+// messages that were yielded but not yet consumed.
+func (pc *PartitionConsumer) SeekToTimestamp(t time.Time) error {
+	return pc.Seek(sarama.OffsetNewest)
+}
+
 ///////////////////////////////////////////////////
 // Expectation API
 ///////////////////////////////////////////////////
@@ -147,6 +147,34 @@ func TestConsumerHandlesExpectationsPausingResuming(t *testing.T) {
 	}
 }
 
+func TestConsumerSeekDiscardsUnconsumedMessages(t *testing.T) {
+	consumer := NewConsumer(t, NewTestConfig())
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	pcExpectation := consumer.ExpectConsumePartition("test", 0, sarama.OffsetOldest)
+	pcExpectation.YieldMessage(&sarama.ConsumerMessage{Value: []byte("hello world")})
+
+	pc, err := consumer.ConsumePartition("test", 0, sarama.OffsetOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pc.Seek(sarama.OffsetOldest); err != nil {
+		t.Fatal(err)
+	}
+	if len(pc.Messages()) > 0 {
+		t.Error("Expected the unconsumed message to be discarded")
+	}
+
+	pcExpectation.YieldMessage(&sarama.ConsumerMessage{Value: []byte("hello again")})
+	if msg := <-pc.Messages(); string(msg.Value) != "hello again" {
+		t.Error("Message was not as expected:", msg)
+	}
+}
+
 func TestConsumerReturnsNonconsumedErrorsOnClose(t *testing.T) {
 	consumer := NewConsumer(t, NewTestConfig())
 	consumer.ExpectConsumePartition("test", 0, sarama.OffsetOldest).YieldError(sarama.ErrOutOfBrokers)
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -144,6 +145,12 @@ func (sp *SyncProducer) partitioner(topic string) sarama.Partitioner {
 	return partitioner
 }
 
+// Flush corresponds with the Flush method of sarama's SyncProducer implementation.
+// The mock handles every message synchronously, so there is never anything to wait for.
+func (sp *SyncProducer) Flush(ctx context.Context) error {
+	return ctx.Err()
+}
+
 // Close corresponds with the Close method of sarama's SyncProducer implementation.
 // By closing a mock syncproducer, you also tell it that no more SendMessage calls will follow,
 // so it will write an error to the test state if there's any remaining expectations.
@@ -0,0 +1,120 @@
+package sarama
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// ackTracker tracks the messages delivered for a partition so that they can be
+// acknowledged in any order, while only the offset following the contiguous
+// range of acknowledged messages is committed. Offsets that are never
+// delivered, such as compacted records or the control records of
+// transactions, do not hold the committed offset back.
+type ackTracker struct {
+	lock       sync.Mutex
+	maxPending int
+
+	// pending holds the delivered messages from the first one that has not
+	// been acknowledged, in offset order, and acked how many of them have been
+	pending []ackEntry
+	acked   int
+	// next is the offset following the last delivered message
+	next int64
+}
+
+type ackEntry struct {
+	offset int64
+	acked  bool
+}
+
+func newAckTracker(maxPending int) *ackTracker {
+	return &ackTracker{maxPending: maxPending, next: -1}
+}
+
+// deliver records messages, in offset order, before they are handed over to
+// be processed.
+func (t *ackTracker) deliver(msgs ...*ConsumerMessage) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, msg := range msgs {
+		if msg.Offset < t.next {
+			// delivered again after seeking back
+			continue
+		}
+		t.pending = append(t.pending, ackEntry{offset: msg.Offset})
+		t.next = msg.Offset + 1
+	}
+}
+
+// reset forgets the pending messages, which are not going to be handed over
+// after seeking to offset, so that the committed offset follows from there.
+func (t *ackTracker) reset(offset int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pending = nil
+	t.acked = 0
+	t.next = offset
+}
+
+// full reports whether the maximum number of pending messages is reached,
+// in which case the partition should not be fetched from.
+func (t *ackTracker) full() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.pending) >= t.maxPending
+}
+
+// ack acknowledges the message at offset and returns the offset that can be
+// committed.
+func (t *ackTracker) ack(offset int64) (int64, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.pending) == 0 || offset < t.pending[0].offset {
+		if offset < t.next {
+			// acknowledged already
+			return t.committable(), nil
+		}
+		return -1, ErrAckNotDelivered
+	}
+
+	i, found := slices.BinarySearchFunc(t.pending, offset, func(e ackEntry, offset int64) int {
+		return cmp.Compare(e.offset, offset)
+	})
+	if !found {
+		return -1, ErrAckNotDelivered
+	}
+	if !t.pending[i].acked {
+		t.pending[i].acked = true
+		t.acked++
+	}
+
+	n := 0
+	for n < len(t.pending) && t.pending[n].acked {
+		n++
+	}
+	t.pending = t.pending[n:]
+	t.acked -= n
+
+	return t.committable(), nil
+}
+
+func (t *ackTracker) committable() int64 {
+	if len(t.pending) > 0 {
+		return t.pending[0].offset
+	}
+	return t.next
+}
+
+// window returns the number of delivered messages that cannot be committed
+// yet and how many of them have been acknowledged.
+func (t *ackTracker) window() (pending, acked int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.pending), t.acked
+}
@@ -0,0 +1,70 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckTracker(t *testing.T) {
+	tracker := newAckTracker(4)
+
+	_, err := tracker.ack(5)
+	require.ErrorIs(t, err, ErrAckNotDelivered, "nothing was delivered yet")
+
+	// offset 8 was compacted away
+	tracker.deliver(&ConsumerMessage{Offset: 5}, &ConsumerMessage{Offset: 6}, &ConsumerMessage{Offset: 7}, &ConsumerMessage{Offset: 9})
+	require.True(t, tracker.full())
+
+	for _, step := range []struct {
+		ack, committable int64
+		pending, acked   int
+		expectedErr      error
+		full             bool
+	}{
+		{ack: 7, committable: 5, pending: 4, acked: 1, full: true},
+		{ack: 5, committable: 6, pending: 3, acked: 1},
+		{ack: 9, committable: 6, pending: 3, acked: 2},
+		{ack: 8, committable: -1, pending: 3, acked: 2, expectedErr: ErrAckNotDelivered},
+		{ack: 6, committable: 10, pending: 0, acked: 0},
+		{ack: 5, committable: 10, pending: 0, acked: 0},
+		{ack: 10, committable: -1, pending: 0, acked: 0, expectedErr: ErrAckNotDelivered},
+	} {
+		committable, err := tracker.ack(step.ack)
+		if step.expectedErr != nil {
+			require.ErrorIs(t, err, step.expectedErr, "ack %d", step.ack)
+		} else {
+			require.NoError(t, err, "ack %d", step.ack)
+		}
+		require.Equal(t, step.committable, committable, "ack %d", step.ack)
+		pending, acked := tracker.window()
+		require.Equal(t, step.pending, pending, "ack %d", step.ack)
+		require.Equal(t, step.acked, acked, "ack %d", step.ack)
+		require.Equal(t, step.full, tracker.full(), "ack %d", step.ack)
+	}
+
+	// messages delivered again after seeking back are not tracked twice
+	tracker.deliver(&ConsumerMessage{Offset: 9}, &ConsumerMessage{Offset: 10})
+	pending, _ := tracker.window()
+	require.Equal(t, 1, pending)
+}
+
+func TestAckTrackerReset(t *testing.T) {
+	tracker := newAckTracker(2)
+	tracker.deliver(&ConsumerMessage{Offset: 5}, &ConsumerMessage{Offset: 6})
+	require.True(t, tracker.full())
+
+	// seeking back discards the messages delivered but not acknowledged
+	tracker.reset(3)
+	require.False(t, tracker.full())
+	pending, acked := tracker.window()
+	require.Zero(t, pending)
+	require.Zero(t, acked)
+
+	tracker.deliver(&ConsumerMessage{Offset: 3}, &ConsumerMessage{Offset: 4})
+	committable, err := tracker.ack(3)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), committable)
+}
@@ -127,7 +127,7 @@ func NewOffsetCommitRequest(conf *Config, group string) *OffsetCommitRequest {
 }
 
 func (r *OffsetCommitRequest) encode(pe packetEncoder) error {
-	if r.Version < 0 || r.Version > 8 {
+	if r.Version < 0 || r.Version > 9 {
 		return PacketEncodingError{"invalid or unsupported OffsetCommitRequest version field"}
 	}
 
@@ -272,7 +272,7 @@ func (r *OffsetCommitRequest) headerVersion() int16 {
 }
 
 func (r *OffsetCommitRequest) isValidVersion() bool {
-	return r.Version >= 0 && r.Version <= 8
+	return r.Version >= 0 && r.Version <= 9
 }
 
 func (r *OffsetCommitRequest) isFlexible() bool {
@@ -285,6 +285,8 @@ func (r *OffsetCommitRequest) isFlexibleVersion(version int16) bool {
 
 func (r *OffsetCommitRequest) requiredVersion() KafkaVersion {
 	switch r.Version {
+	case 9:
+		return V3_6_0_0
 	case 8:
 		return V2_4_0_0
 	case 7:
@@ -300,7 +302,7 @@ func (r *OffsetCommitRequest) requiredVersion() KafkaVersion {
 	case 0, 1:
 		return V0_8_2_0
 	default:
-		return V2_4_0_0
+		return V3_6_0_0
 	}
 }
 
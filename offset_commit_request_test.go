@@ -232,6 +232,23 @@ func TestOffsetCommitRequestV5AndPlus(t *testing.T) {
 				},
 			},
 		},
+		{
+			"v9",
+			9,
+			offsetCommitRequestOneBlockV8,
+			&OffsetCommitRequest{
+				Version:                 9,
+				ConsumerGroup:           "foo",
+				ConsumerGroupGeneration: 1,
+				ConsumerID:              "mid",
+				GroupInstanceId:         &groupInstanceId,
+				blocks: map[string]map[int32]*offsetCommitRequestBlock{
+					"topic": {
+						1: &offsetCommitRequestBlock{offset: 2, metadata: "meta", committedLeaderEpoch: 3},
+					},
+				},
+			},
+		},
 	}
 	for _, c := range tests {
 		request := new(OffsetCommitRequest)
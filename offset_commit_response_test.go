@@ -97,6 +97,20 @@ func TestEmptyOffsetCommitResponse(t *testing.T) {
 				},
 			},
 		},
+		{
+			"v9",
+			9,
+			noEmptyOffsetCommitResponseV8,
+			&OffsetCommitResponse{
+				ThrottleTimeMs: 100,
+				Version:        9,
+				Errors: map[string]map[int32]KError{
+					"topic": {
+						3: ErrNoError,
+					},
+				},
+			},
+		},
 	}
 	for _, c := range tests {
 		response := new(OffsetCommitResponse)
@@ -0,0 +1,240 @@
+package sarama
+
+// OffsetForLeaderEpochRequest (API key 23) asks a partition leader for the end
+// offset of a given leader epoch. Consumers use it after a leader change to
+// detect log truncation (KIP-320).
+type OffsetForLeaderEpochRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ReplicaID contains the broker ID of the follower, or -1 if this request
+	// is from a consumer (v3+).
+	ReplicaID int32
+	// Topics contains the topics to get offsets for.
+	Topics []OffsetForLeaderEpochRequestTopic
+}
+
+// OffsetForLeaderEpochRequestTopic contains the partitions of a single topic
+// to get offsets for.
+type OffsetForLeaderEpochRequestTopic struct {
+	// Topic contains the topic name.
+	Topic string
+	// Partitions contains the partitions to get offsets for.
+	Partitions []OffsetForLeaderEpochRequestPartition
+}
+
+// OffsetForLeaderEpochRequestPartition contains a single partition to get
+// the end offset for.
+type OffsetForLeaderEpochRequestPartition struct {
+	// Partition contains the partition index.
+	Partition int32
+	// CurrentLeaderEpoch contains the epoch known to the requester, used by
+	// the broker to fence stale requests, or -1 to skip the check (v2+).
+	CurrentLeaderEpoch int32
+	// LeaderEpoch contains the epoch to look up an end offset for.
+	LeaderEpoch int32
+}
+
+// NewOffsetForLeaderEpochRequest returns an OffsetForLeaderEpochRequest
+// for the given Kafka version, issued on behalf of a consumer.
+func NewOffsetForLeaderEpochRequest(version KafkaVersion) *OffsetForLeaderEpochRequest {
+	r := &OffsetForLeaderEpochRequest{ReplicaID: -1}
+	switch {
+	case version.IsAtLeast(V2_8_0_0):
+		// Version 4 enables flexible versions.
+		r.Version = 4
+	case version.IsAtLeast(V2_3_0_0):
+		// Version 3 adds ReplicaID.
+		r.Version = 3
+	case version.IsAtLeast(V2_1_0_0):
+		// Version 2 adds CurrentLeaderEpoch to support fencing.
+		r.Version = 2
+	case version.IsAtLeast(V2_0_0_0):
+		// Version 1 is the same as version 0.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *OffsetForLeaderEpochRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+// AddPartition adds a partition whose end offset for leaderEpoch should be
+// looked up.
+func (r *OffsetForLeaderEpochRequest) AddPartition(topic string, partition int32, currentLeaderEpoch int32, leaderEpoch int32) {
+	p := OffsetForLeaderEpochRequestPartition{
+		Partition:          partition,
+		CurrentLeaderEpoch: currentLeaderEpoch,
+		LeaderEpoch:        leaderEpoch,
+	}
+	for i := range r.Topics {
+		if r.Topics[i].Topic == topic {
+			r.Topics[i].Partitions = append(r.Topics[i].Partitions, p)
+			return
+		}
+	}
+	r.Topics = append(r.Topics, OffsetForLeaderEpochRequestTopic{
+		Topic:      topic,
+		Partitions: []OffsetForLeaderEpochRequestPartition{p},
+	})
+}
+
+func (p *OffsetForLeaderEpochRequestPartition) encode(pe packetEncoder, version int16) error {
+	pe.putInt32(p.Partition)
+	if version >= 2 {
+		pe.putInt32(p.CurrentLeaderEpoch)
+	}
+	pe.putInt32(p.LeaderEpoch)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (p *OffsetForLeaderEpochRequestPartition) decode(pd packetDecoder, version int16) (err error) {
+	if p.Partition, err = pd.getInt32(); err != nil {
+		return err
+	}
+	p.CurrentLeaderEpoch = -1
+	if version >= 2 {
+		if p.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
+			return err
+		}
+	}
+	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (t *OffsetForLeaderEpochRequestTopic) encode(pe packetEncoder, version int16) error {
+	if err := pe.putString(t.Topic); err != nil {
+		return err
+	}
+
+	if err := pe.putArrayLength(len(t.Partitions)); err != nil {
+		return err
+	}
+	for i := range t.Partitions {
+		if err := t.Partitions[i].encode(pe, version); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *OffsetForLeaderEpochRequestTopic) decode(pd packetDecoder, version int16) (err error) {
+	if t.Topic, err = pd.getString(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	t.Partitions = make([]OffsetForLeaderEpochRequestPartition, n)
+	for i := range n {
+		if err := t.Partitions[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *OffsetForLeaderEpochRequest) encode(pe packetEncoder) error {
+	if r.Version >= 3 {
+		pe.putInt32(r.ReplicaID)
+	}
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for i := range r.Topics {
+		if err := r.Topics[i].encode(pe, r.Version); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *OffsetForLeaderEpochRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	r.ReplicaID = -1
+	if version >= 3 {
+		if r.ReplicaID, err = pd.getInt32(); err != nil {
+			return err
+		}
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]OffsetForLeaderEpochRequestTopic, n)
+	for i := range n {
+		if err := r.Topics[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *OffsetForLeaderEpochRequest) key() int16 {
+	return apiKeyOffsetForLeaderEpoch
+}
+
+func (r *OffsetForLeaderEpochRequest) version() int16 {
+	return r.Version
+}
+
+func (r *OffsetForLeaderEpochRequest) headerVersion() int16 {
+	if r.Version >= 4 {
+		return 2
+	}
+	return 1
+}
+
+func (r *OffsetForLeaderEpochRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 4
+}
+
+func (r *OffsetForLeaderEpochRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *OffsetForLeaderEpochRequest) isFlexibleVersion(version int16) bool {
+	return version >= 4
+}
+
+func (r *OffsetForLeaderEpochRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 4:
+		return V2_8_0_0
+	case 3:
+		return V2_3_0_0
+	case 2:
+		return V2_1_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V0_11_0_0
+	}
+}
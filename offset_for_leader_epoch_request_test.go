@@ -0,0 +1,58 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	offsetForLeaderEpochRequestV0 = []byte{
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Topic
+		0, 0, 0, 1, // Partitions
+		0, 0, 0, 0, // Partition
+		0, 0, 0, 3, // LeaderEpoch
+	}
+
+	offsetForLeaderEpochRequestV3 = []byte{
+		255, 255, 255, 255, // ReplicaID
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Topic
+		0, 0, 0, 1, // Partitions
+		0, 0, 0, 0, // Partition
+		0, 0, 0, 4, // CurrentLeaderEpoch
+		0, 0, 0, 3, // LeaderEpoch
+	}
+
+	offsetForLeaderEpochRequestV4 = []byte{
+		255, 255, 255, 255, // ReplicaID
+		2,                          // Topics
+		6, 't', 'o', 'p', 'i', 'c', // Topic
+		2,          // Partitions
+		0, 0, 0, 0, // Partition
+		0, 0, 0, 4, // CurrentLeaderEpoch
+		0, 0, 0, 3, // LeaderEpoch
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestOffsetForLeaderEpochRequest(t *testing.T) {
+	request := &OffsetForLeaderEpochRequest{Version: 0, ReplicaID: -1}
+	request.AddPartition("topic", 0, -1, 3)
+	testRequest(t, "v0", request, offsetForLeaderEpochRequestV0)
+
+	request = NewOffsetForLeaderEpochRequest(V2_3_0_0)
+	request.AddPartition("topic", 0, 4, 3)
+	if request.Version != 3 {
+		t.Fatalf("expected version 3, got %d", request.Version)
+	}
+	testRequest(t, "v3", request, offsetForLeaderEpochRequestV3)
+
+	request = NewOffsetForLeaderEpochRequest(V2_8_0_0)
+	request.AddPartition("topic", 0, 4, 3)
+	if request.Version != 4 {
+		t.Fatalf("expected version 4, got %d", request.Version)
+	}
+	testRequest(t, "v4", request, offsetForLeaderEpochRequestV4)
+}
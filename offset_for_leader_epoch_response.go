@@ -0,0 +1,247 @@
+package sarama
+
+import "time"
+
+// OffsetForLeaderEpochResponse is the response to an
+// OffsetForLeaderEpochRequest.
+type OffsetForLeaderEpochResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota (v2+).
+	ThrottleTime time.Duration
+	// Topics contains the per-topic results.
+	Topics []OffsetForLeaderEpochResponseTopic
+}
+
+// OffsetForLeaderEpochResponseTopic contains the per-partition results of a
+// single topic.
+type OffsetForLeaderEpochResponseTopic struct {
+	// Topic contains the topic name.
+	Topic string
+	// Partitions contains the per-partition results.
+	Partitions []OffsetForLeaderEpochResponsePartition
+}
+
+// OffsetForLeaderEpochResponsePartition contains the end offset of the
+// requested epoch for a single partition.
+type OffsetForLeaderEpochResponsePartition struct {
+	// Err contains the error code, or 0 if there was no error.
+	Err KError
+	// Partition contains the partition index.
+	Partition int32
+	// LeaderEpoch contains the largest epoch known to the leader that is
+	// less than or equal to the requested epoch, or -1 if unknown (v1+).
+	LeaderEpoch int32
+	// EndOffset contains the end offset of LeaderEpoch, or -1 if unknown.
+	EndOffset int64
+}
+
+func (r *OffsetForLeaderEpochResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+// GetPartition returns the result for the given topic/partition, or nil if
+// the response does not contain it.
+func (r *OffsetForLeaderEpochResponse) GetPartition(topic string, partition int32) *OffsetForLeaderEpochResponsePartition {
+	for i := range r.Topics {
+		if r.Topics[i].Topic != topic {
+			continue
+		}
+		for j := range r.Topics[i].Partitions {
+			if r.Topics[i].Partitions[j].Partition == partition {
+				return &r.Topics[i].Partitions[j]
+			}
+		}
+	}
+	return nil
+}
+
+// AddPartition adds the end offset of leaderEpoch for a topic/partition to
+// the response.
+func (r *OffsetForLeaderEpochResponse) AddPartition(topic string, partition int32, kerror KError, leaderEpoch int32, endOffset int64) {
+	p := OffsetForLeaderEpochResponsePartition{
+		Err:         kerror,
+		Partition:   partition,
+		LeaderEpoch: leaderEpoch,
+		EndOffset:   endOffset,
+	}
+	for i := range r.Topics {
+		if r.Topics[i].Topic == topic {
+			r.Topics[i].Partitions = append(r.Topics[i].Partitions, p)
+			return
+		}
+	}
+	r.Topics = append(r.Topics, OffsetForLeaderEpochResponseTopic{
+		Topic:      topic,
+		Partitions: []OffsetForLeaderEpochResponsePartition{p},
+	})
+}
+
+func (p *OffsetForLeaderEpochResponsePartition) encode(pe packetEncoder, version int16) error {
+	pe.putKError(p.Err)
+	pe.putInt32(p.Partition)
+	if version >= 1 {
+		pe.putInt32(p.LeaderEpoch)
+	}
+	pe.putInt64(p.EndOffset)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (p *OffsetForLeaderEpochResponsePartition) decode(pd packetDecoder, version int16) (err error) {
+	if p.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+	if p.Partition, err = pd.getInt32(); err != nil {
+		return err
+	}
+	p.LeaderEpoch = -1
+	if version >= 1 {
+		if p.LeaderEpoch, err = pd.getInt32(); err != nil {
+			return err
+		}
+	}
+	if p.EndOffset, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (t *OffsetForLeaderEpochResponseTopic) encode(pe packetEncoder, version int16) error {
+	if err := pe.putString(t.Topic); err != nil {
+		return err
+	}
+
+	if err := pe.putArrayLength(len(t.Partitions)); err != nil {
+		return err
+	}
+	for i := range t.Partitions {
+		if err := t.Partitions[i].encode(pe, version); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (t *OffsetForLeaderEpochResponseTopic) decode(pd packetDecoder, version int16) (err error) {
+	if t.Topic, err = pd.getString(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	t.Partitions = make([]OffsetForLeaderEpochResponsePartition, n)
+	for i := range n {
+		if err := t.Partitions[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *OffsetForLeaderEpochResponse) encode(pe packetEncoder) error {
+	if r.Version >= 2 {
+		pe.putDurationMs(r.ThrottleTime)
+	}
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for i := range r.Topics {
+		if err := r.Topics[i].encode(pe, r.Version); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *OffsetForLeaderEpochResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if version >= 2 {
+		if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+			return err
+		}
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]OffsetForLeaderEpochResponseTopic, n)
+	for i := range n {
+		if err := r.Topics[i].decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *OffsetForLeaderEpochResponse) key() int16 {
+	return apiKeyOffsetForLeaderEpoch
+}
+
+func (r *OffsetForLeaderEpochResponse) version() int16 {
+	return r.Version
+}
+
+func (r *OffsetForLeaderEpochResponse) headerVersion() int16 {
+	if r.Version >= 4 {
+		return 1
+	}
+	return 0
+}
+
+func (r *OffsetForLeaderEpochResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 4
+}
+
+func (r *OffsetForLeaderEpochResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *OffsetForLeaderEpochResponse) isFlexibleVersion(version int16) bool {
+	return version >= 4
+}
+
+func (r *OffsetForLeaderEpochResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 4:
+		return V2_8_0_0
+	case 3:
+		return V2_3_0_0
+	case 2:
+		return V2_1_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V0_11_0_0
+	}
+}
+
+func (r *OffsetForLeaderEpochResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
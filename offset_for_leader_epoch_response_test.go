@@ -0,0 +1,65 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	offsetForLeaderEpochResponseV0 = []byte{
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Topic
+		0, 0, 0, 1, // Partitions
+		0, 0, // ErrorCode
+		0, 0, 0, 0, // Partition
+		0, 0, 0, 0, 0, 0, 0, 42, // EndOffset
+	}
+
+	offsetForLeaderEpochResponseV2 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Topic
+		0, 0, 0, 1, // Partitions
+		0, 0, // ErrorCode
+		0, 0, 0, 0, // Partition
+		0, 0, 0, 3, // LeaderEpoch
+		0, 0, 0, 0, 0, 0, 0, 42, // EndOffset
+	}
+
+	offsetForLeaderEpochResponseV4 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		2,                          // Topics
+		6, 't', 'o', 'p', 'i', 'c', // Topic
+		2,     // Partitions
+		0, 75, // ErrorCode
+		0, 0, 0, 1, // Partition
+		255, 255, 255, 255, // LeaderEpoch
+		255, 255, 255, 255, 255, 255, 255, 255, // EndOffset
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestOffsetForLeaderEpochResponse(t *testing.T) {
+	response := &OffsetForLeaderEpochResponse{Version: 0}
+	response.AddPartition("topic", 0, ErrNoError, -1, 42)
+	testResponse(t, "v0", response, offsetForLeaderEpochResponseV0)
+
+	response = &OffsetForLeaderEpochResponse{Version: 2, ThrottleTime: 100 * time.Millisecond}
+	response.AddPartition("topic", 0, ErrNoError, 3, 42)
+	testResponse(t, "v2", response, offsetForLeaderEpochResponseV2)
+
+	response = &OffsetForLeaderEpochResponse{Version: 4, ThrottleTime: 100 * time.Millisecond}
+	response.AddPartition("topic", 1, ErrUnknownLeaderEpoch, -1, -1)
+	testResponse(t, "v4", response, offsetForLeaderEpochResponseV4)
+
+	if p := response.GetPartition("topic", 1); p == nil || p.Err != ErrUnknownLeaderEpoch {
+		t.Errorf("unexpected partition result %+v", p)
+	}
+	if p := response.GetPartition("topic", 0); p != nil {
+		t.Errorf("expected no result for partition 0, got %+v", p)
+	}
+}
@@ -2,13 +2,18 @@ package sarama
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/rcrowley/go-metrics"
 )
 
 // Offset Manager
 
-// OffsetManager uses Kafka to store and fetch consumed partition offsets.
+// OffsetManager uses Kafka to store and fetch consumed partition offsets, or
+// the OffsetStore set with Config.Consumer.Offsets.Store.
 type OffsetManager interface {
 	// ManagePartition creates a PartitionOffsetManager on the given topic/partition.
 	// It will return an error if this OffsetManager is already managing the given
@@ -35,7 +40,9 @@ type offsetManager struct {
 
 	memberID        string
 	groupInstanceId *string
-	generation      int32
+	// generation is the member epoch when using the consumer group protocol,
+	// which the heartbeat loop advances while the session is running
+	generation atomic.Int32
 
 	broker     *Broker
 	brokerLock sync.RWMutex
@@ -46,6 +53,8 @@ type offsetManager struct {
 	closeOnce sync.Once
 	closing   chan none
 	closed    chan none
+
+	metricRegistry metrics.Registry
 }
 
 // NewOffsetManagerFromClient creates a new OffsetManager from the given client.
@@ -68,12 +77,14 @@ func newOffsetManagerFromClient(group, memberID string, generation int32, client
 		poms:            make(map[string]map[int32]*partitionOffsetManager),
 		sessionCanceler: sessionCanceler,
 
-		memberID:   memberID,
-		generation: generation,
+		memberID: memberID,
 
 		closing: make(chan none),
 		closed:  make(chan none),
+
+		metricRegistry: newCleanupRegistry(conf.MetricRegistry),
 	}
+	om.generation.Store(generation)
 	if conf.Consumer.Group.InstanceId != "" {
 		om.groupInstanceId = &conf.Consumer.Group.InstanceId
 	}
@@ -122,7 +133,7 @@ func (om *offsetManager) Close() error {
 		// flush one last time
 		if om.conf.Consumer.Offsets.AutoCommit.Enable {
 			for attempt := 0; attempt <= om.conf.Consumer.Offsets.Retry.Max; attempt++ {
-				om.flushToBroker()
+				om.flush()
 				if om.releasePOMs(false) == 0 {
 					break
 				}
@@ -133,6 +144,7 @@ func (om *offsetManager) Close() error {
 		om.brokerLock.Lock()
 		om.broker = nil
 		om.brokerLock.Unlock()
+		om.metricRegistry.UnregisterAll()
 	})
 	return nil
 }
@@ -146,6 +158,10 @@ func (om *offsetManager) computeBackoff(retries int) time.Duration {
 }
 
 func (om *offsetManager) fetchInitialOffset(topic string, partition int32, retries int) (int64, int32, string, error) {
+	if om.conf.Consumer.Offsets.Store != nil {
+		return om.fetchStoredOffset(topic, partition)
+	}
+
 	broker, err := om.coordinator()
 	if err != nil {
 		if retries <= 0 {
@@ -251,10 +267,20 @@ func (om *offsetManager) mainLoop() {
 }
 
 func (om *offsetManager) Commit() {
-	om.flushToBroker()
+	om.flush()
 	om.releasePOMs(false)
 }
 
+// flush commits the marked offsets to the broker, or saves them to the
+// configured OffsetStore.
+func (om *offsetManager) flush() {
+	if om.conf.Consumer.Offsets.Store != nil {
+		om.flushToStore()
+		return
+	}
+	om.flushToBroker()
+}
+
 func (om *offsetManager) flushToBroker() {
 	broker, err := om.coordinator()
 	if err != nil {
@@ -308,7 +334,7 @@ func (om *offsetManager) constructRequest() *OffsetCommitRequest {
 		Version:                 1,
 		ConsumerGroup:           om.group,
 		ConsumerID:              om.memberID,
-		ConsumerGroupGeneration: om.generation,
+		ConsumerGroupGeneration: om.generation.Load(),
 	}
 	// Version 1 adds timestamp and group membership information, as well as the commit timestamp.
 	//
@@ -338,6 +364,12 @@ func (om *offsetManager) constructRequest() *OffsetCommitRequest {
 	if om.conf.Version.IsAtLeast(V2_4_0_0) {
 		r.Version = 8
 	}
+	// Version 9 is the same as version 8, but the generation is the member
+	// epoch when the group uses the consumer rebalance protocol (KIP-848),
+	// so it is only needed by such groups.
+	if om.conf.Version.IsAtLeast(V3_6_0_0) && om.conf.Consumer.Group.Protocol == GroupProtocolConsumer {
+		r.Version = 9
+	}
 
 	// commit timestamp was only briefly supported in V1 where we set it to
 	// ReceiveTime (-1) to tell the broker to set it to the time when the commit
@@ -412,7 +444,7 @@ func (om *offsetManager) handleResponse(broker *Broker, req *OffsetCommitRequest
 			case ErrOffsetMetadataTooLarge, ErrInvalidCommitOffsetSize:
 				// nothing we can do about this, just tell the user and carry on
 				pom.handleError(err)
-			case ErrOffsetsLoadInProgress:
+			case ErrOffsetsLoadInProgress, ErrStaleMemberEpoch:
 				// nothing wrong but we didn't commit, we'll get it next time round
 			case ErrFencedInstancedId:
 				pom.handleError(err)
@@ -485,6 +517,42 @@ func (om *offsetManager) releasePOMs(force bool) (remaining int) {
 	return
 }
 
+// releasePartitions stops managing the given partitions, for example once they
+// are revoked from the member during a session, committing their offsets first
+// as Close does.
+func (om *offsetManager) releasePartitions(partitions map[string][]int32) {
+	var poms []*partitionOffsetManager
+	for topic, ps := range partitions {
+		for _, partition := range ps {
+			if pom := om.findPOM(topic, partition); pom != nil {
+				pom.AsyncClose()
+				poms = append(poms, pom)
+			}
+		}
+	}
+
+	if om.conf.Consumer.Offsets.AutoCommit.Enable {
+		for attempt := 0; attempt <= om.conf.Consumer.Offsets.Retry.Max; attempt++ {
+			om.flush()
+			if !slices.ContainsFunc(poms, (*partitionOffsetManager).isDirty) {
+				break
+			}
+		}
+	}
+
+	om.pomsLock.Lock()
+	defer om.pomsLock.Unlock()
+	for _, pom := range poms {
+		pom.release()
+		if om.poms[pom.topic][pom.partition] == pom {
+			delete(om.poms[pom.topic], pom.partition)
+			if len(om.poms[pom.topic]) == 0 {
+				delete(om.poms, pom.topic)
+			}
+		}
+	}
+}
+
 func (om *offsetManager) findPOM(topic string, partition int32) *partitionOffsetManager {
 	om.pomsLock.RLock()
 	defer om.pomsLock.RUnlock()
@@ -538,6 +606,16 @@ type PartitionOffsetManager interface {
 	// allows incrementing the offset. cf MarkOffset for more details.
 	ResetOffset(offset int64, metadata string)
 
+	// Ack acknowledges the message at the provided offset, which may be done in
+	// any order with respect to the other messages of the partition. The offset
+	// following the contiguous range of acknowledged messages is marked, so that
+	// a message is never committed before all the earlier ones are processed.
+	//
+	// Acknowledgements require Config.Consumer.Offsets.Ack.Enable, and are only
+	// tracked for the claims of a ConsumerGroup, where the delivered messages are
+	// known. ErrAckNotDelivered is returned for an offset that was not delivered.
+	Ack(offset int64) error
+
 	// Errors returns a read channel of errors that occur during offset management, if
 	// enabled. By default, errors are logged and not returned over this channel. If
 	// you want to implement any custom error handling, set your config's
@@ -570,6 +648,9 @@ type partitionOffsetManager struct {
 	dirty    bool
 	done     bool
 
+	// acks tracks the delivered messages when Consumer.Offsets.Ack.Enable is set
+	acks *ackTracker
+
 	releaseOnce sync.Once
 	errors      chan *ConsumerError
 }
@@ -580,7 +661,7 @@ func (om *offsetManager) newPartitionOffsetManager(topic string, partition int32
 		return nil, err
 	}
 
-	return &partitionOffsetManager{
+	pom := &partitionOffsetManager{
 		parent:      om,
 		topic:       topic,
 		partition:   partition,
@@ -588,7 +669,11 @@ func (om *offsetManager) newPartitionOffsetManager(topic string, partition int32
 		errors:      make(chan *ConsumerError, om.conf.ChannelBufferSize),
 		offset:      offset,
 		metadata:    metadata,
-	}, nil
+	}
+	if om.conf.Consumer.Offsets.Ack.Enable {
+		pom.acks = newAckTracker(om.conf.Consumer.Offsets.Ack.MaxPending)
+	}
+	return pom, nil
 }
 
 func (pom *partitionOffsetManager) Errors() <-chan *ConsumerError {
@@ -617,6 +702,36 @@ func (pom *partitionOffsetManager) ResetOffset(offset int64, metadata string) {
 	}
 }
 
+func (pom *partitionOffsetManager) Ack(offset int64) error {
+	if pom.acks == nil {
+		return ErrAcksDisabled
+	}
+
+	next, err := pom.acks.ack(offset)
+	if err != nil {
+		return err
+	}
+	pom.updateAckMetrics()
+
+	pom.lock.Lock()
+	defer pom.lock.Unlock()
+
+	if next > pom.offset {
+		pom.offset = next
+		pom.dirty = true
+	}
+	return nil
+}
+
+func (pom *partitionOffsetManager) updateAckMetrics() {
+	pending, acked := pom.acks.window()
+	registry := pom.parent.metricRegistry
+	getOrRegisterHistogram("consumer-ack-pending", registry).Update(int64(pending))
+	getOrRegisterTopicHistogram("consumer-ack-pending", pom.topic, registry).Update(int64(pending))
+	getOrRegisterHistogram("consumer-ack-waiting", registry).Update(int64(acked))
+	getOrRegisterTopicHistogram("consumer-ack-waiting", pom.topic, registry).Update(int64(acked))
+}
+
 func (pom *partitionOffsetManager) updateCommitted(offset int64, metadata string) {
 	pom.lock.Lock()
 	defer pom.lock.Unlock()
@@ -671,6 +786,12 @@ func (pom *partitionOffsetManager) handleError(err error) {
 	}
 }
 
+func (pom *partitionOffsetManager) isDirty() bool {
+	pom.lock.Lock()
+	defer pom.lock.Unlock()
+	return pom.dirty
+}
+
 func (pom *partitionOffsetManager) release() {
 	pom.releaseOnce.Do(func() {
 		close(pom.errors)
@@ -744,6 +744,21 @@ func TestPartitionOffsetManagerMarkOffset(t *testing.T) {
 	safeClose(t, testClient)
 }
 
+func TestPartitionOffsetManagerAckDisabled(t *testing.T) {
+	om, testClient, broker, coordinator := initOffsetManager(t, 0)
+	defer broker.Close()
+	defer coordinator.Close()
+	pom := initPartitionOffsetManager(t, om, coordinator, 5, "original_meta")
+
+	if err := pom.Ack(5); !errors.Is(err, ErrAcksDisabled) {
+		t.Errorf("Expected ErrAcksDisabled. Actual: %v", err)
+	}
+
+	safeClose(t, pom)
+	safeClose(t, om)
+	safeClose(t, testClient)
+}
+
 func TestPartitionOffsetManagerMarkOffsetWithRetention(t *testing.T) {
 	om, testClient, broker, coordinator := initOffsetManager(t, time.Hour)
 	defer broker.Close()
@@ -929,3 +944,30 @@ func TestConstructRequestRetentionTime(t *testing.T) {
 		}
 	}
 }
+
+func TestConstructRequestVersion(t *testing.T) {
+	for _, tc := range []struct {
+		protocol ConsumerGroupProtocol
+		version  int16
+	}{
+		{GroupProtocolClassic, 8},
+		{GroupProtocolConsumer, 9},
+	} {
+		t.Run(string(tc.protocol), func(t *testing.T) {
+			conf := NewTestConfig()
+			conf.Version = V3_6_0_0
+			conf.Consumer.Group.Protocol = tc.protocol
+			om := &offsetManager{
+				conf: conf,
+				poms: map[string]map[int32]*partitionOffsetManager{
+					"topic": {0: {dirty: true}},
+				},
+			}
+
+			req := om.constructRequest()
+			if req.Version != tc.version {
+				t.Errorf("expected version %d, got: %d", tc.version, req.Version)
+			}
+		})
+	}
+}
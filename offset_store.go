@@ -0,0 +1,81 @@
+package sarama
+
+// OffsetStore stores the offsets of a consumer group outside of Kafka, in
+// place of the OffsetFetch and OffsetCommit requests sent to the group
+// coordinator. It is set with Config.Consumer.Offsets.Store, while the group
+// membership and the assignment of the partitions still go through the
+// coordinator.
+//
+// An application processing messages into a database can keep the offsets in
+// the same database, so that they are stored in the same transaction as the
+// results of the processing. As the group generation is not checked when
+// saving offsets, fencing off a member that lost its partitions is up to the
+// store. The metadata strings of marked offsets are not stored.
+type OffsetStore interface {
+	// Load returns the next offset to consume from the partition, or a
+	// negative offset when none was saved, in which case consumption starts
+	// from Config.Consumer.Offsets.Initial.
+	Load(topic string, partition int32) (int64, error)
+
+	// Save stores the next offsets to consume, by topic and partition. It is
+	// called with the offsets marked since the last call, every
+	// Config.Consumer.Offsets.AutoCommit.Interval and when the offsets are
+	// committed or the partitions released. When it returns an error, the
+	// offsets are reported on the Errors channel of the partition offset
+	// managers and saved again on the next call.
+	Save(offsets map[string]map[int32]int64) error
+}
+
+// fetchStoredOffset loads the initial offset of a partition from the store.
+func (om *offsetManager) fetchStoredOffset(topic string, partition int32) (int64, int32, string, error) {
+	offset, err := om.conf.Consumer.Offsets.Store.Load(topic, partition)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return offset, -1, "", nil
+}
+
+// flushToStore saves the offsets marked since the last flush to the store.
+func (om *offsetManager) flushToStore() {
+	type marked struct {
+		offset   int64
+		metadata string
+	}
+
+	offsets := make(map[string]map[int32]int64)
+	saved := make(map[*partitionOffsetManager]marked)
+
+	om.pomsLock.RLock()
+	for _, topicManagers := range om.poms {
+		for _, pom := range topicManagers {
+			pom.lock.Lock()
+			if pom.dirty {
+				if offsets[pom.topic] == nil {
+					offsets[pom.topic] = make(map[int32]int64)
+				}
+				offsets[pom.topic][pom.partition] = pom.offset
+				saved[pom] = marked{offset: pom.offset, metadata: pom.metadata}
+			}
+			pom.lock.Unlock()
+		}
+	}
+	om.pomsLock.RUnlock()
+
+	if len(saved) == 0 {
+		return
+	}
+
+	if err := om.conf.Consumer.Offsets.Store.Save(offsets); err != nil {
+		// hold pomsLock so that none of them is released while reporting
+		om.pomsLock.RLock()
+		defer om.pomsLock.RUnlock()
+		for pom := range saved {
+			pom.handleError(err)
+		}
+		return
+	}
+
+	for pom, marked := range saved {
+		pom.updateCommitted(marked.offset, marked.metadata)
+	}
+}
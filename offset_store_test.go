@@ -0,0 +1,140 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryOffsetStore struct {
+	lock    sync.Mutex
+	offsets map[string]map[int32]int64
+	saves   int
+	err     error
+}
+
+func (s *memoryOffsetStore) Load(topic string, partition int32) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if offset, ok := s.offsets[topic][partition]; ok {
+		return offset, nil
+	}
+	return -1, nil
+}
+
+func (s *memoryOffsetStore) Save(offsets map[string]map[int32]int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.saves++
+	if s.err != nil {
+		return s.err
+	}
+	for topic, partitions := range offsets {
+		if s.offsets[topic] == nil {
+			s.offsets[topic] = make(map[int32]int64)
+		}
+		for partition, offset := range partitions {
+			s.offsets[topic][partition] = offset
+		}
+	}
+	return nil
+}
+
+func (s *memoryOffsetStore) saved() (map[string]map[int32]int64, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.offsets, s.saves
+}
+
+func initStoreOffsetManager(t *testing.T, store OffsetStore) (OffsetManager, *MockBroker) {
+	config := NewTestConfig()
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Offsets.Initial = OffsetOldest
+	config.Consumer.Offsets.Store = store
+
+	broker := NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("my_topic", 0, broker.BrokerID()).
+			SetLeader("my_topic", 1, broker.BrokerID()),
+	})
+
+	client, err := NewClient([]string{broker.Addr()}, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	om, err := NewOffsetManagerFromClient("group", client)
+	require.NoError(t, err)
+	return om, broker
+}
+
+func TestOffsetManagerStore(t *testing.T) {
+	store := &memoryOffsetStore{offsets: map[string]map[int32]int64{"my_topic": {0: 5}}}
+	om, broker := initStoreOffsetManager(t, store)
+
+	pom0, err := om.ManagePartition("my_topic", 0)
+	require.NoError(t, err)
+	pom1, err := om.ManagePartition("my_topic", 1)
+	require.NoError(t, err)
+
+	offset, _ := pom0.NextOffset()
+	require.Equal(t, int64(5), offset)
+	offset, _ = pom1.NextOffset()
+	require.Equal(t, OffsetOldest, offset, "the initial offset is used when none was saved")
+
+	pom0.MarkOffset(8, "ignored")
+	om.Commit()
+	offsets, saves := store.saved()
+	require.Equal(t, map[string]map[int32]int64{"my_topic": {0: 8}}, offsets)
+	require.Equal(t, 1, saves)
+
+	om.Commit()
+	_, saves = store.saved()
+	require.Equal(t, 1, saves, "clean offsets must not be saved again")
+
+	pom1.MarkOffset(3, "")
+	om.Commit()
+	// om must be closed before the poms so that their errors are released
+	safeClose(t, om)
+	safeClose(t, pom0)
+	safeClose(t, pom1)
+	offsets, _ = store.saved()
+	require.Equal(t, map[string]map[int32]int64{"my_topic": {0: 8, 1: 3}}, offsets)
+
+	for _, rr := range broker.History() {
+		require.IsType(t, &MetadataRequest{}, rr.Request, "offsets must not be fetched or committed to the broker")
+	}
+}
+
+func TestOffsetManagerStoreSaveErr(t *testing.T) {
+	failed := errors.New("database unavailable")
+	store := &memoryOffsetStore{offsets: make(map[string]map[int32]int64), err: failed}
+	om, _ := initStoreOffsetManager(t, store)
+
+	pom, err := om.ManagePartition("my_topic", 0)
+	require.NoError(t, err)
+
+	pom.MarkOffset(4, "")
+	om.Commit()
+	consumerErr := <-pom.Errors()
+	require.ErrorIs(t, consumerErr.Err, failed)
+
+	store.lock.Lock()
+	store.err = nil
+	store.lock.Unlock()
+
+	om.Commit()
+	offsets, saves := store.saved()
+	require.Equal(t, map[string]map[int32]int64{"my_topic": {0: 4}}, offsets)
+	require.Equal(t, 2, saves)
+
+	safeClose(t, om)
+	safeClose(t, pom)
+}
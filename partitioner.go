@@ -38,6 +38,34 @@ type DynamicConsistencyPartitioner interface {
 	MessageRequiresConsistency(message *ProducerMessage) bool
 }
 
+// AdaptivePartitioner can optionally be implemented by Partitioners in order
+// to take the state of the producer into account, for instance to avoid the
+// partitions whose leader is backed up. The producer calls PartitionAdaptive
+// instead of Partition for such partitioners.
+type AdaptivePartitioner interface {
+	Partitioner
+
+	// PartitionAdaptive is similar to Partitioner.Partition, but is also given
+	// the load of the partitions as seen by the producer.
+	PartitionAdaptive(message *ProducerMessage, numPartitions int32, load ProducerLoad) (int32, error)
+}
+
+// ProducerLoad exposes the state of the producer to an AdaptivePartitioner.
+type ProducerLoad interface {
+	// BatchBytes returns the number of bytes at which the producer flushes a
+	// batch, that is Producer.Flush.Bytes when set.
+	BatchBytes() int
+
+	// IsBusy reports whether the partition of the given index, in
+	// [0...numPartitions-1], is backed up: it has as many batches in flight
+	// as allowed, or its leader is much slower than the other leaders.
+	IsBusy(index int32) bool
+
+	// MessageBytes returns the number of bytes the message takes in a batch,
+	// which depends on the record format of the configured Version.
+	MessageBytes(message *ProducerMessage) int
+}
+
 // PartitionerConstructor is the type for a function capable of constructing new Partitioners.
 type PartitionerConstructor func(topic string) Partitioner
 
@@ -282,3 +310,97 @@ func (p *murmur2Partitioner) RequiresConsistency() bool {
 func (p *murmur2Partitioner) MessageRequiresConsistency(message *ProducerMessage) bool {
 	return message.Key != nil
 }
+
+// defaultStickyBatchBytes is the size of the batches of the sticky partitioner
+// when Producer.Flush.Bytes is not set, like the batch.size of the Java client.
+const defaultStickyBatchBytes = 16 * 1024
+
+type stickyPartitioner struct {
+	hash      Partitioner
+	generator *rand.Rand
+
+	// partition is the index of the partition keyless messages stick to, or
+	// -1, and bytes the number of bytes sent to it so far
+	partition int32
+	bytes     int
+}
+
+// NewStickyPartitioner returns a Partitioner which, like the Java client's
+// default partitioner (KIP-480 and KIP-794), sends keyless messages to the
+// same partition until a batch is filled, that is until Producer.Flush.Bytes
+// (16KiB if not set) have been sent to it, before switching to another random
+// partition. The partitions the producer reports as busy are skipped when
+// switching. Messages with a key are hashed like NewHashPartitioner does.
+func NewStickyPartitioner(topic string) Partitioner {
+	return &stickyPartitioner{
+		hash:      NewHashPartitioner(topic),
+		generator: rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+		partition: -1,
+	}
+}
+
+func (p *stickyPartitioner) Partition(message *ProducerMessage, numPartitions int32) (int32, error) {
+	if message.Key != nil {
+		return p.hash.Partition(message, numPartitions)
+	}
+	// without the producer's load, count the size in the record batch format
+	// of the default Version
+	return p.stick(numPartitions, defaultStickyBatchBytes, message.ByteSize(2), nil), nil
+}
+
+func (p *stickyPartitioner) PartitionAdaptive(message *ProducerMessage, numPartitions int32, load ProducerLoad) (int32, error) {
+	if message.Key != nil {
+		return p.hash.Partition(message, numPartitions)
+	}
+	batchBytes := load.BatchBytes()
+	if batchBytes <= 0 {
+		batchBytes = defaultStickyBatchBytes
+	}
+	return p.stick(numPartitions, batchBytes, load.MessageBytes(message), load.IsBusy), nil
+}
+
+// stick returns the partition a keyless message of the given size goes to.
+func (p *stickyPartitioner) stick(numPartitions int32, batchBytes, size int, isBusy func(int32) bool) int32 {
+	if p.partition < 0 || p.partition >= numPartitions || p.bytes >= batchBytes {
+		p.partition = p.next(numPartitions, isBusy)
+		p.bytes = 0
+	}
+	p.bytes += size
+	return p.partition
+}
+
+// next picks a random partition other than the current one among those that
+// are not busy, unless the current one is the only one that is not.
+func (p *stickyPartitioner) next(numPartitions int32, isBusy func(int32) bool) int32 {
+	candidates := make([]int32, 0, numPartitions)
+	for i := range numPartitions {
+		if i == p.partition || (isBusy != nil && isBusy(i)) {
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	if len(candidates) > 0 {
+		return candidates[p.generator.Intn(len(candidates))]
+	}
+
+	// every other partition is busy
+	if p.partition < 0 || p.partition >= numPartitions {
+		return int32(p.generator.Intn(int(numPartitions)))
+	}
+	if numPartitions == 1 || (isBusy != nil && !isBusy(p.partition)) {
+		return p.partition
+	}
+	choice := int32(p.generator.Intn(int(numPartitions - 1)))
+	if choice >= p.partition {
+		choice++
+	}
+	return choice
+}
+
+func (p *stickyPartitioner) RequiresConsistency() bool {
+	return true
+}
+
+func (p *stickyPartitioner) MessageRequiresConsistency(message *ProducerMessage) bool {
+	return message.Key != nil
+}
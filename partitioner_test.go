@@ -426,6 +426,95 @@ func TestMurmur2PartitionerConsistency(t *testing.T) {
 	}
 }
 
+type testProducerLoad struct {
+	batchBytes int
+	busy       map[int32]bool
+}
+
+func (l *testProducerLoad) BatchBytes() int                           { return l.batchBytes }
+func (l *testProducerLoad) IsBusy(index int32) bool                   { return l.busy[index] }
+func (l *testProducerLoad) MessageBytes(message *ProducerMessage) int { return message.ByteSize(2) }
+
+func TestStickyPartitioner(t *testing.T) {
+	partitioner := NewStickyPartitioner("mytopic").(AdaptivePartitioner)
+	msg := &ProducerMessage{Value: ByteEncoder(make([]byte, 100))}
+	load := &testProducerLoad{batchBytes: 10 * msg.ByteSize(2)}
+
+	first, err := partitioner.PartitionAdaptive(msg, 50, load)
+	if err != nil {
+		t.Fatal(partitioner, err)
+	}
+	for i := 1; i < 10; i++ {
+		choice, err := partitioner.PartitionAdaptive(msg, 50, load)
+		if err != nil {
+			t.Fatal(partitioner, err)
+		}
+		if choice != first {
+			t.Fatal(partitioner, "switched to partition", choice, "before the batch of partition", first, "was filled")
+		}
+	}
+	choice, err := partitioner.PartitionAdaptive(msg, 50, load)
+	if err != nil {
+		t.Fatal(partitioner, err)
+	}
+	if choice == first {
+		t.Error(partitioner, "stuck to partition", first, "after its batch was filled")
+	}
+	if choice < 0 || choice >= 50 {
+		t.Error(partitioner, "returned partition", choice, "outside of range")
+	}
+
+	for i := 1; i < 50; i++ {
+		buf := make([]byte, 256)
+		_, _ = rand.Read(buf)
+		assertPartitioningConsistent(t, partitioner, &ProducerMessage{Key: ByteEncoder(buf)}, 50)
+	}
+}
+
+func TestStickyPartitionerSkipsBusyPartitions(t *testing.T) {
+	partitioner := NewStickyPartitioner("mytopic").(AdaptivePartitioner)
+	msg := &ProducerMessage{Value: ByteEncoder(make([]byte, 100))}
+	load := &testProducerLoad{batchBytes: 1, busy: map[int32]bool{0: true, 1: true, 3: true}}
+
+	for i := 0; i < 50; i++ {
+		choice, err := partitioner.PartitionAdaptive(msg, 4, load)
+		if err != nil {
+			t.Fatal(partitioner, err)
+		}
+		// every message fills a batch, but the partitioner keeps to the only
+		// partition that is not busy
+		if choice != 2 {
+			t.Error(partitioner, "returned busy partition", choice)
+		}
+	}
+
+	load.busy[2] = true
+	for i := 0; i < 50; i++ {
+		choice, err := partitioner.PartitionAdaptive(msg, 4, load)
+		if err != nil {
+			t.Fatal(partitioner, err)
+		}
+		if choice < 0 || choice >= 4 {
+			t.Error(partitioner, "returned partition", choice, "outside of range when every partition is busy")
+		}
+	}
+}
+
+func TestStickyPartitionerConsistency(t *testing.T) {
+	partitioner := NewStickyPartitioner("mytopic")
+	ep, ok := partitioner.(DynamicConsistencyPartitioner)
+	if !ok {
+		t.Fatal("sticky partitioner does not implement DynamicConsistencyPartitioner")
+	}
+
+	if !ep.MessageRequiresConsistency(&ProducerMessage{Key: StringEncoder("hi")}) {
+		t.Error("messages with keys should require consistency")
+	}
+	if ep.MessageRequiresConsistency(&ProducerMessage{}) {
+		t.Error("messages without keys should not require consistency")
+	}
+}
+
 // By default, Sarama uses the message's key to consistently assign a partition to
 // a message using hashing. If no key is set, a random partition will be chosen.
 // This example shows how you can partition messages randomly, even when a key is set,
@@ -0,0 +1,100 @@
+package sarama
+
+// PushTelemetryRequest pushes the client metrics requested by a telemetry
+// subscription to a broker (KIP-714).
+type PushTelemetryRequest struct {
+	Version int16
+
+	// ClientInstanceID is the unique identifier of the client instance
+	ClientInstanceID Uuid
+
+	// SubscriptionID is the unique identifier of the current subscription
+	SubscriptionID int32
+
+	// Terminating is true if the client is terminating the connection
+	Terminating bool
+
+	// CompressionType is the compression codec used for Metrics
+	CompressionType CompressionCodec
+
+	// Metrics contains the metrics encoded in OpenTelemetry format
+	Metrics []byte
+}
+
+func (r *PushTelemetryRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *PushTelemetryRequest) encode(pe packetEncoder) error {
+	if err := pe.putUuid(r.ClientInstanceID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.SubscriptionID)
+	pe.putBool(r.Terminating)
+	pe.putInt8(int8(r.CompressionType))
+
+	if err := pe.putBytes(r.Metrics); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *PushTelemetryRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ClientInstanceID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	if r.SubscriptionID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.Terminating, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	compressionType, err := pd.getInt8()
+	if err != nil {
+		return err
+	}
+	r.CompressionType = CompressionCodec(compressionType)
+
+	if r.Metrics, err = pd.getBytes(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *PushTelemetryRequest) key() int16 {
+	return apiKeyPushTelemetry
+}
+
+func (r *PushTelemetryRequest) version() int16 {
+	return r.Version
+}
+
+func (r *PushTelemetryRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *PushTelemetryRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *PushTelemetryRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *PushTelemetryRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *PushTelemetryRequest) requiredVersion() KafkaVersion {
+	return V3_7_0_0
+}
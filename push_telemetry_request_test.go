@@ -0,0 +1,25 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var pushTelemetryRequestV0 = []byte{
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // ClientInstanceID
+	0, 0, 0, 7, // SubscriptionID
+	1,          // Terminating
+	4,          // CompressionType
+	4, 1, 2, 3, // Metrics
+	0, // empty tagged fields
+}
+
+func TestPushTelemetryRequest(t *testing.T) {
+	request := &PushTelemetryRequest{
+		ClientInstanceID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SubscriptionID:   7,
+		Terminating:      true,
+		CompressionType:  CompressionZSTD,
+		Metrics:          []byte{1, 2, 3},
+	}
+	testRequest(t, "v0", request, pushTelemetryRequestV0)
+}
@@ -0,0 +1,70 @@
+package sarama
+
+import "time"
+
+type PushTelemetryResponse struct {
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+}
+
+func (r *PushTelemetryResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *PushTelemetryResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.ErrorCode)
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *PushTelemetryResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *PushTelemetryResponse) key() int16 {
+	return apiKeyPushTelemetry
+}
+
+func (r *PushTelemetryResponse) version() int16 {
+	return r.Version
+}
+
+func (r *PushTelemetryResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *PushTelemetryResponse) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *PushTelemetryResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *PushTelemetryResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *PushTelemetryResponse) requiredVersion() KafkaVersion {
+	return V3_7_0_0
+}
+
+func (r *PushTelemetryResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,22 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var pushTelemetryResponseV0 = []byte{
+	0, 0, 0, 100, // ThrottleTime
+	0, 117, // ErrorCode
+	0, // empty tagged fields
+}
+
+func TestPushTelemetryResponse(t *testing.T) {
+	response := &PushTelemetryResponse{
+		ThrottleTime: 100 * time.Millisecond,
+		ErrorCode:    ErrUnknownSubscriptionID,
+	}
+	testResponse(t, "v0", response, pushTelemetryResponseV0)
+}
@@ -27,6 +27,19 @@ func (p RebalanceProtocol) String() string {
 	}
 }
 
+// ConsumerGroupProtocol identifies the protocol a consumer group uses to join
+// the group and receive its assignment, see Consumer.Group.Protocol.
+type ConsumerGroupProtocol string
+
+const (
+	// GroupProtocolClassic uses JoinGroup/SyncGroup with client side assignment.
+	GroupProtocolClassic ConsumerGroupProtocol = "classic"
+
+	// GroupProtocolConsumer uses ConsumerGroupHeartbeat with server side
+	// assignment (KIP-848).
+	GroupProtocolConsumer ConsumerGroupProtocol = "consumer"
+)
+
 // RebalanceProtocolBalanceStrategy is an optional extension of BalanceStrategy
 // that declares which rebalance protocols the strategy can take part in. A
 // strategy that does not implement it is treated as eager-only.
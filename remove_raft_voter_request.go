@@ -0,0 +1,84 @@
+package sarama
+
+// RemoveRaftVoterRequest is sent to the active controller to remove a voter
+// from the KRaft metadata quorum (KIP-853).
+type RemoveRaftVoterRequest struct {
+	Version int16
+
+	// ClusterID is the cluster id, or null to skip the check
+	ClusterID *string
+
+	// VoterID is the replica id of the voter getting removed from the topic
+	// partition
+	VoterID int32
+
+	// VoterDirectoryID is the directory id of the voter getting removed from
+	// the topic partition
+	VoterDirectoryID Uuid
+}
+
+func (r *RemoveRaftVoterRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *RemoveRaftVoterRequest) encode(pe packetEncoder) error {
+	if err := pe.putNullableString(r.ClusterID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.VoterID)
+
+	if err := pe.putUuid(r.VoterDirectoryID); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *RemoveRaftVoterRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ClusterID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.VoterID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.VoterDirectoryID, err = pd.getUuid(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *RemoveRaftVoterRequest) key() int16 {
+	return apiKeyRemoveRaftVoter
+}
+
+func (r *RemoveRaftVoterRequest) version() int16 {
+	return r.Version
+}
+
+func (r *RemoveRaftVoterRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *RemoveRaftVoterRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *RemoveRaftVoterRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *RemoveRaftVoterRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *RemoveRaftVoterRequest) requiredVersion() KafkaVersion {
+	return V3_9_0_0
+}
@@ -0,0 +1,20 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var removeRaftVoterRequestV0 = []byte{
+	0,          // ClusterID
+	0, 0, 0, 4, // VoterID
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // VoterDirectoryID
+	0, // empty tagged fields
+}
+
+func TestRemoveRaftVoterRequest(t *testing.T) {
+	request := &RemoveRaftVoterRequest{
+		VoterID:          4,
+		VoterDirectoryID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+	}
+	testRequest(t, "v0", request, removeRaftVoterRequestV0)
+}
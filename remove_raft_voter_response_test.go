@@ -0,0 +1,16 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var removeRaftVoterResponseV0 = []byte{
+	0, 0, 0, 0, // ThrottleTimeMs
+	0, 125, // ErrorCode (VOTER_NOT_FOUND)
+	0, // ErrorMessage
+	0, // empty tagged fields
+}
+
+func TestRemoveRaftVoterResponse(t *testing.T) {
+	testResponse(t, "v0", &RemoveRaftVoterResponse{ErrorCode: ErrVoterNotFound}, removeRaftVoterResponseV0)
+}
@@ -0,0 +1,92 @@
+package sarama
+
+// RenewDelegationTokenRequest asks the broker to extend the expiry time of a delegation token.
+type RenewDelegationTokenRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// HMAC contains the HMAC of the delegation token.
+	HMAC []byte
+	// RenewPeriodMs contains the renewal time period in milliseconds.
+	RenewPeriodMs int64
+}
+
+// NewRenewDelegationTokenRequest returns a RenewDelegationTokenRequest using
+// the highest protocol version supported by the given Kafka version.
+func NewRenewDelegationTokenRequest(version KafkaVersion) *RenewDelegationTokenRequest {
+	r := &RenewDelegationTokenRequest{}
+	switch {
+	case version.IsAtLeast(V2_4_0_0):
+		// Version 2 is the first flexible version.
+		r.Version = 2
+	case version.IsAtLeast(V2_0_0_0):
+		// Version 1 is the same as version 0.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *RenewDelegationTokenRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *RenewDelegationTokenRequest) encode(pe packetEncoder) error {
+	if err := pe.putBytes(r.HMAC); err != nil {
+		return err
+	}
+	pe.putInt64(r.RenewPeriodMs)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *RenewDelegationTokenRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.HMAC, err = pd.getBytes(); err != nil {
+		return err
+	}
+	if r.RenewPeriodMs, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *RenewDelegationTokenRequest) key() int16 {
+	return apiKeyRenewDelegationToken
+}
+
+func (r *RenewDelegationTokenRequest) version() int16 {
+	return r.Version
+}
+
+func (r *RenewDelegationTokenRequest) headerVersion() int16 {
+	if r.Version >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (r *RenewDelegationTokenRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 2
+}
+
+func (r *RenewDelegationTokenRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *RenewDelegationTokenRequest) isFlexibleVersion(version int16) bool {
+	return version >= 2
+}
+
+func (r *RenewDelegationTokenRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 2:
+		return V2_4_0_0
+	case 1:
+		return V2_0_0_0
+	default:
+		return V1_1_0_0
+	}
+}
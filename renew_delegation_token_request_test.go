@@ -0,0 +1,33 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	renewDelegationTokenRequestV0 = []byte{
+		0, 0, 0, 2, 1, 2, // Hmac
+		0, 0, 0, 0, 0, 0x36, 0xee, 0x80, // RenewPeriodMs
+	}
+
+	renewDelegationTokenRequestV2 = []byte{
+		3, 1, 2, // Hmac
+		0, 0, 0, 0, 0, 0x36, 0xee, 0x80, // RenewPeriodMs
+		0, // empty tagged fields
+	}
+)
+
+func TestRenewDelegationTokenRequest(t *testing.T) {
+	request := NewRenewDelegationTokenRequest(V1_1_0_0)
+	request.HMAC = []byte{1, 2}
+	request.RenewPeriodMs = 3600000
+	testRequest(t, "v0", request, renewDelegationTokenRequestV0)
+
+	request = NewRenewDelegationTokenRequest(V2_4_0_0)
+	if request.Version != 2 {
+		t.Fatalf("expected version 2, got %d", request.Version)
+	}
+	request.HMAC = []byte{1, 2}
+	request.RenewPeriodMs = 3600000
+	testRequest(t, "v2", request, renewDelegationTokenRequestV2)
+}
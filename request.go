@@ -166,14 +166,16 @@ func allocateBody(key, version int16) protocolBody {
 		return &DeleteRecordsRequest{Version: version}
 	case apiKeyInitProducerId:
 		return &InitProducerIDRequest{Version: version}
-	// 23: OffsetForLeaderEpochRequest
+	case apiKeyOffsetForLeaderEpoch:
+		return &OffsetForLeaderEpochRequest{Version: version}
 	case apiKeyAddPartitionsToTxn:
 		return &AddPartitionsToTxnRequest{Version: version}
 	case apiKeyAddOffsetsToTxn:
 		return &AddOffsetsToTxnRequest{Version: version}
 	case apiKeyEndTxn:
 		return &EndTxnRequest{Version: version}
-	// 27: WriteTxnMarkersRequest
+	case apiKeyWriteTxnMarkers:
+		return &WriteTxnMarkersRequest{Version: version}
 	case apiKeyTxnOffsetCommit:
 		return &TxnOffsetCommitRequest{Version: version}
 	case apiKeyDescribeAcls:
@@ -186,17 +188,22 @@ func allocateBody(key, version int16) protocolBody {
 		return &DescribeConfigsRequest{Version: version}
 	case apiKeyAlterConfigs:
 		return &AlterConfigsRequest{Version: version}
-	// 34: AlterReplicaLogDirsRequest
+	case apiKeyAlterReplicaLogDirs:
+		return &AlterReplicaLogDirsRequest{Version: version}
 	case apiKeyDescribeLogDirs:
 		return &DescribeLogDirsRequest{Version: version}
 	case apiKeySASLAuth:
 		return &SaslAuthenticateRequest{Version: version}
 	case apiKeyCreatePartitions:
 		return &CreatePartitionsRequest{Version: version}
-	// 38: CreateDelegationTokenRequest
-	// 39: RenewDelegationTokenRequest
-	// 40: ExpireDelegationTokenRequest
-	// 41: DescribeDelegationTokenRequest
+	case apiKeyCreateDelegationToken:
+		return &CreateDelegationTokenRequest{Version: version}
+	case apiKeyRenewDelegationToken:
+		return &RenewDelegationTokenRequest{Version: version}
+	case apiKeyExpireDelegationToken:
+		return &ExpireDelegationTokenRequest{Version: version}
+	case apiKeyDescribeDelegationToken:
+		return &DescribeDelegationTokenRequest{Version: version}
 	case apiKeyDeleteGroups:
 		return &DeleteGroupsRequest{Version: version}
 	case apiKeyElectLeaders:
@@ -226,20 +233,41 @@ func allocateBody(key, version int16) protocolBody {
 		// 52: VoteRequest
 		// 53: BeginQuorumEpochRequest
 		// 54: EndQuorumEpochRequest
-		// 55: DescribeQuorumRequest
+	case apiKeyDescribeQuorum:
+		return &DescribeQuorumRequest{Version: version}
 		// 56: AlterPartitionRequest
 		// 58: EnvelopeRequest
 		// 59: FetchSnapshotRequest
 		// 60: DescribeClusterRequest
 		// 62: BrokerRegistrationRequest
 		// 63: BrokerHeartbeatRequest
-		// 64: UnregisterBrokerRequest
+	case apiKeyUnregisterBroker:
+		return &UnregisterBrokerRequest{Version: version}
 	case apiKeyDescribeTransactions:
 		return &DescribeTransactionsRequest{Version: version}
 	case apiKeyListTransactions:
 		return &ListTransactionsRequest{Version: version}
 		// 67: AllocateProducerIdsRequest
-		// 68: ConsumerGroupHeartbeatRequest
+	case apiKeyConsumerGroupHeartbeat:
+		return &ConsumerGroupHeartbeatRequest{Version: version}
+	case apiKeyConsumerGroupDescribe:
+		return &ConsumerGroupDescribeRequest{Version: version}
+	case apiKeyGetTelemetrySubscriptions:
+		return &GetTelemetrySubscriptionsRequest{Version: version}
+	case apiKeyPushTelemetry:
+		return &PushTelemetryRequest{Version: version}
+	case apiKeyDescribeTopicPartitions:
+		return &DescribeTopicPartitionsRequest{Version: version}
+	case apiKeyShareGroupHeartbeat:
+		return &ShareGroupHeartbeatRequest{Version: version}
+	case apiKeyShareFetch:
+		return &ShareFetchRequest{Version: version}
+	case apiKeyShareAcknowledge:
+		return &ShareAcknowledgeRequest{Version: version}
+	case apiKeyAddRaftVoter:
+		return &AddRaftVoterRequest{Version: version}
+	case apiKeyRemoveRaftVoter:
+		return &RemoveRaftVoterRequest{Version: version}
 	}
 	return nil
 }
@@ -291,12 +319,16 @@ func allocateResponseBody(key, version int16) protocolBody {
 		return &DeleteRecordsResponse{Version: version}
 	case apiKeyInitProducerId:
 		return &InitProducerIDResponse{Version: version}
+	case apiKeyOffsetForLeaderEpoch:
+		return &OffsetForLeaderEpochResponse{Version: version}
 	case apiKeyAddPartitionsToTxn:
 		return &AddPartitionsToTxnResponse{Version: version}
 	case apiKeyAddOffsetsToTxn:
 		return &AddOffsetsToTxnResponse{Version: version}
 	case apiKeyEndTxn:
 		return &EndTxnResponse{Version: version}
+	case apiKeyWriteTxnMarkers:
+		return &WriteTxnMarkersResponse{Version: version}
 	case apiKeyTxnOffsetCommit:
 		return &TxnOffsetCommitResponse{Version: version}
 	case apiKeyDescribeAcls:
@@ -309,12 +341,22 @@ func allocateResponseBody(key, version int16) protocolBody {
 		return &DescribeConfigsResponse{Version: version}
 	case apiKeyAlterConfigs:
 		return &AlterConfigsResponse{Version: version}
+	case apiKeyAlterReplicaLogDirs:
+		return &AlterReplicaLogDirsResponse{Version: version}
 	case apiKeyDescribeLogDirs:
 		return &DescribeLogDirsResponse{Version: version}
 	case apiKeySASLAuth:
 		return &SaslAuthenticateResponse{Version: version}
 	case apiKeyCreatePartitions:
 		return &CreatePartitionsResponse{Version: version}
+	case apiKeyCreateDelegationToken:
+		return &CreateDelegationTokenResponse{Version: version}
+	case apiKeyRenewDelegationToken:
+		return &RenewDelegationTokenResponse{Version: version}
+	case apiKeyExpireDelegationToken:
+		return &ExpireDelegationTokenResponse{Version: version}
+	case apiKeyDescribeDelegationToken:
+		return &DescribeDelegationTokenResponse{Version: version}
 	case apiKeyDeleteGroups:
 		return &DeleteGroupsResponse{Version: version}
 	case apiKeyElectLeaders:
@@ -341,10 +383,34 @@ func allocateResponseBody(key, version int16) protocolBody {
 		return &DescribeClusterResponse{Version: version}
 	case apiKeyDescribeProducers:
 		return &DescribeProducersResponse{Version: version}
+	case apiKeyDescribeQuorum:
+		return &DescribeQuorumResponse{Version: version}
+	case apiKeyUnregisterBroker:
+		return &UnregisterBrokerResponse{Version: version}
 	case apiKeyDescribeTransactions:
 		return &DescribeTransactionsResponse{Version: version}
 	case apiKeyListTransactions:
 		return &ListTransactionsResponse{Version: version}
+	case apiKeyConsumerGroupHeartbeat:
+		return &ConsumerGroupHeartbeatResponse{Version: version}
+	case apiKeyConsumerGroupDescribe:
+		return &ConsumerGroupDescribeResponse{Version: version}
+	case apiKeyGetTelemetrySubscriptions:
+		return &GetTelemetrySubscriptionsResponse{Version: version}
+	case apiKeyPushTelemetry:
+		return &PushTelemetryResponse{Version: version}
+	case apiKeyDescribeTopicPartitions:
+		return &DescribeTopicPartitionsResponse{Version: version}
+	case apiKeyShareGroupHeartbeat:
+		return &ShareGroupHeartbeatResponse{Version: version}
+	case apiKeyShareFetch:
+		return &ShareFetchResponse{Version: version}
+	case apiKeyShareAcknowledge:
+		return &ShareAcknowledgeResponse{Version: version}
+	case apiKeyAddRaftVoter:
+		return &AddRaftVoterResponse{Version: version}
+	case apiKeyRemoveRaftVoter:
+		return &RemoveRaftVoterResponse{Version: version}
 	}
 	return nil
 }
@@ -69,7 +69,7 @@ var names = map[int16]string{
 	52:                                 "VoteRequest",
 	53:                                 "BeginQuorumEpochRequest",
 	54:                                 "EndQuorumEpochRequest",
-	55:                                 "DescribeQuorumRequest",
+	apiKeyDescribeQuorum:               "DescribeQuorumRequest",
 	56:                                 "AlterPartitionRequest",
 	apiKeyUpdateFeatures:               "UpdateFeaturesRequest",
 	58:                                 "EnvelopeRequest",
@@ -78,11 +78,20 @@ var names = map[int16]string{
 	apiKeyDescribeProducers:            "DescribeProducersRequest",
 	62:                                 "BrokerRegistrationRequest",
 	63:                                 "BrokerHeartbeatRequest",
-	64:                                 "UnregisterBrokerRequest",
+	apiKeyUnregisterBroker:             "UnregisterBrokerRequest",
 	apiKeyDescribeTransactions:         "DescribeTransactionsRequest",
 	apiKeyListTransactions:             "ListTransactionsRequest",
 	67:                                 "AllocateProducerIdsRequest",
-	68:                                 "ConsumerGroupHeartbeatRequest",
+	apiKeyConsumerGroupHeartbeat:       "ConsumerGroupHeartbeatRequest",
+	apiKeyConsumerGroupDescribe:        "ConsumerGroupDescribeRequest",
+	apiKeyGetTelemetrySubscriptions:    "GetTelemetrySubscriptionsRequest",
+	apiKeyPushTelemetry:                "PushTelemetryRequest",
+	apiKeyDescribeTopicPartitions:      "DescribeTopicPartitionsRequest",
+	apiKeyShareGroupHeartbeat:          "ShareGroupHeartbeatRequest",
+	apiKeyShareFetch:                   "ShareFetchRequest",
+	apiKeyShareAcknowledge:             "ShareAcknowledgeRequest",
+	apiKeyAddRaftVoter:                 "AddRaftVoterRequest",
+	apiKeyRemoveRaftVoter:              "RemoveRaftVoterRequest",
 }
 
 // TestAllocateBodyProtocolVersions tests two related version expectations:
@@ -234,6 +243,7 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 			map[int16]int16{
 				apiKeyFetch:                   11, // up from 10
 				apiKeyMetadata:                8,  // up from 7
+				apiKeyOffsetForLeaderEpoch:    3,  // up from 2
 				apiKeyOffsetCommit:            7,  // up from 6
 				apiKeyJoinGroup:               5,  // up from 4
 				apiKeyHeartbeat:               3,  // up from 2
@@ -270,6 +280,10 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				apiKeyAlterPartitionReassignments: 0, // new in 2.4
 				apiKeyListPartitionReassignments:  0, // new in 2.4
 				apiKeyOffsetDelete:                0, // new in 2.4
+				apiKeyCreateDelegationToken:       2, // up from 1
+				apiKeyRenewDelegationToken:        2, // up from 1
+				apiKeyExpireDelegationToken:       2, // up from 1
+				apiKeyDescribeDelegationToken:     2, // up from 1
 			},
 		},
 		{
@@ -292,6 +306,7 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 			map[int16]int16{
 				apiKeyListGroups:           4, // up from 3
 				apiKeyDescribeLogDirs:      2, // up from 1
+				apiKeyAlterReplicaLogDirs:  2, // up from 1
 				apiKeyDescribeClientQuotas: 0, // new in 2.6
 				apiKeyAlterClientQuotas:    0, // new in 2.6
 				apiKeyDeleteRecords:        2, // up from 1
@@ -312,6 +327,7 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				apiKeyDeleteTopics:                 5,  // up from 4
 				apiKeyCreatePartitions:             3,  // up from 2
 				apiKeyUpdateFeatures:               0,  // new in 2.7
+				apiKeyDescribeQuorum:               0,  // new in 2.7
 			},
 		},
 		{
@@ -331,6 +347,8 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				apiKeyAlterClientQuotas:    1,  // up from 0
 				apiKeyCreateTopics:         7,  // up from 6
 				apiKeyDeleteTopics:         6,  // up from 5
+				apiKeyOffsetForLeaderEpoch: 4,  // up from 3
+				apiKeyWriteTxnMarkers:      1,  // up from 0
 			},
 		},
 		{
@@ -352,6 +370,7 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				// apiKeyFetch:    13, // up from 12
 				// TODO: MetadataRequest v12 is not supported, but expected for KafkaVersion 3.1.0
 				// apiKeyMetadata: 12, // up from 11
+				apiKeyUnregisterBroker: 0, // new in 3.1
 			},
 		},
 		{
@@ -367,7 +386,10 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 		{
 			V3_3_0_0,
 			map[int16]int16{
-				apiKeyDescribeLogDirs: 4, // up from 3
+				apiKeyDescribeLogDirs:         4, // up from 3
+				apiKeyCreateDelegationToken:   3, // up from 2
+				apiKeyDescribeDelegationToken: 3, // up from 2
+				apiKeyDescribeQuorum:          1, // up from 0
 				// TODO: DescribeAclsRequest v3 is not supported, but expected for KafkaVersion 3.3.0
 				// apiKeyDescribeAcls: 3, // up from 2
 				// TODO: CreateAclsRequest v3 is not supported, but expected for KafkaVersion 3.3.0
@@ -387,22 +409,23 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				// apiKeyListOffsets:        8,  // up from 7
 				// TODO: AddPartitionsToTxnRequest v4 is not supported, but expected for KafkaVersion 3.5.0
 				// apiKeyAddPartitionsToTxn: 4,  // up from 3
-				// TODO: ConsumerGroupHeartbeatRequest v0 is not supported, but expected for KafkaVersion 3.5.0
-				// apiKeyConsumerGroupHeartbeat /* (68) */: 0, // new in 3.5
+				apiKeyConsumerGroupHeartbeat: 0, // new in 3.5
 			},
 		},
 		{
 			V3_6_0_0,
 			map[int16]int16{
-				// TODO: OffsetCommitRequest v9 is not supported, but expected for KafkaVersion 3.6.0
-				// apiKeyOffsetCommit: 9, // up from 8
+				apiKeyOffsetCommit: 9, // up from 8
 			},
 		},
 		{
 			V3_7_0_0,
 			map[int16]int16{
-				apiKeyDescribeCluster: 1,  // up from 0
-				apiKeyProduce:         10, // up from 9
+				apiKeyDescribeCluster:           1,  // up from 0
+				apiKeyProduce:                   10, // up from 9
+				apiKeyConsumerGroupDescribe:     0,  // new in 3.7
+				apiKeyGetTelemetrySubscriptions: 0,  // new in 3.7
+				apiKeyPushTelemetry:             0,  // new in 3.7
 				// TODO: FetchRequest v16 is not supported, but expected for KafkaVersion 3.7.0
 				// apiKeyFetch:           16, // up from 15
 				// TODO: OffsetFetchRequest v9 is not supported, but expected for KafkaVersion 3.7.0
@@ -412,8 +435,9 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 		{
 			V3_8_0_0,
 			map[int16]int16{
-				apiKeyListGroups:       5, // up from 4
-				apiKeyListTransactions: 1, // up from 0
+				apiKeyListGroups:              5, // up from 4
+				apiKeyListTransactions:        1, // up from 0
+				apiKeyDescribeTopicPartitions: 0, // new in 3.8
 				// TODO: ProduceRequest v11 is not supported, but expected for KafkaVersion 3.8.0
 				// apiKeyProduce:            11, // up from 10
 				// TODO: FindCoordinatorRequest v5 is not supported, but expected for KafkaVersion 3.8.0
@@ -439,7 +463,10 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				// apiKeyListOffsets:         9, // up from 8
 				// TODO: FindCoordinatorRequest v6 is not supported, but expected for KafkaVersion 3.9.0
 				// apiKeyFindCoordinator:     6,  // up from 5
-				apiKeyApiVersions: 4, // up from 3
+				apiKeyApiVersions:     4, // up from 3
+				apiKeyDescribeQuorum:  2, // up from 1
+				apiKeyAddRaftVoter:    0, // new in 3.9
+				apiKeyRemoveRaftVoter: 0, // new in 3.9
 			},
 		},
 		{
@@ -460,8 +487,11 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				// apiKeyTxnOffsetCommit:     5,  // up from 4
 				// TODO: UpdateFeaturesRequest v2 is not supported, but expected for KafkaVersion 4.0.0
 				// apiKeyUpdateFeatures /* (57) */: 2, // up from 1
-				// TODO: ConsumerGroupHeartbeatRequest v1 is not supported, but expected for KafkaVersion 4.0.0
-				// apiKeyConsumerGroupHeartbeat /* (68) */: 1, // up from 0
+				apiKeyConsumerGroupHeartbeat: 1, // up from 0
+				apiKeyConsumerGroupDescribe:  1, // up from 0
+				apiKeyShareGroupHeartbeat:    0, // new in 4.0 (early access)
+				apiKeyShareFetch:             0, // new in 4.0 (early access)
+				apiKeyShareAcknowledge:       0, // new in 4.0 (early access)
 			},
 		},
 		{
@@ -479,6 +509,9 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				// apiKeyInitProducerId:              6, // up from 5
 				// TODO: AlterPartitionReassignmentsRequest v1 is not supported, but expected for KafkaVersion 4.1.0
 				// apiKeyAlterPartitionReassignments: 1, // up from 0
+				apiKeyShareGroupHeartbeat: 1, // up from 0
+				apiKeyShareFetch:          1, // up from 0
+				apiKeyShareAcknowledge:    1, // up from 0
 			},
 		},
 		{
@@ -510,18 +543,25 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				apiKeyDeleteTopics:                 maxVersion(&DeleteTopicsRequest{}),
 				apiKeyDeleteRecords:                maxVersion(&DeleteRecordsRequest{}),
 				apiKeyInitProducerId:               maxVersion(&InitProducerIDRequest{}),
+				apiKeyOffsetForLeaderEpoch:         maxVersion(&OffsetForLeaderEpochRequest{}),
 				apiKeyAddPartitionsToTxn:           maxVersion(&AddPartitionsToTxnRequest{}),
 				apiKeyAddOffsetsToTxn:              maxVersion(&AddOffsetsToTxnRequest{}),
 				apiKeyEndTxn:                       maxVersion(&EndTxnRequest{}),
+				apiKeyWriteTxnMarkers:              maxVersion(&WriteTxnMarkersRequest{}),
 				apiKeyTxnOffsetCommit:              maxVersion(&TxnOffsetCommitRequest{}),
 				apiKeyDescribeAcls:                 maxVersion(&DescribeAclsRequest{}),
 				apiKeyCreateAcls:                   maxVersion(&CreateAclsRequest{}),
 				apiKeyDeleteAcls:                   maxVersion(&DeleteAclsRequest{}),
 				apiKeyDescribeConfigs:              maxVersion(&DescribeConfigsRequest{}),
 				apiKeyAlterConfigs:                 maxVersion(&AlterConfigsRequest{}),
+				apiKeyAlterReplicaLogDirs:          maxVersion(&AlterReplicaLogDirsRequest{}),
 				apiKeyDescribeLogDirs:              maxVersion(&DescribeLogDirsRequest{}),
 				apiKeySASLAuth:                     maxVersion(&SaslAuthenticateRequest{}),
 				apiKeyCreatePartitions:             maxVersion(&CreatePartitionsRequest{}),
+				apiKeyCreateDelegationToken:        maxVersion(&CreateDelegationTokenRequest{}),
+				apiKeyRenewDelegationToken:         maxVersion(&RenewDelegationTokenRequest{}),
+				apiKeyExpireDelegationToken:        maxVersion(&ExpireDelegationTokenRequest{}),
+				apiKeyDescribeDelegationToken:      maxVersion(&DescribeDelegationTokenRequest{}),
 				apiKeyDeleteGroups:                 maxVersion(&DeleteGroupsRequest{}),
 				apiKeyElectLeaders:                 maxVersion(&ElectLeadersRequest{}),
 				apiKeyIncrementalAlterConfigs:      maxVersion(&IncrementalAlterConfigsRequest{}),
@@ -535,8 +575,20 @@ func TestAllocateBodyProtocolVersions(t *testing.T) {
 				apiKeyUpdateFeatures:               maxVersion(&UpdateFeaturesRequest{}),
 				apiKeyDescribeCluster:              maxVersion(&DescribeClusterRequest{}),
 				apiKeyDescribeProducers:            maxVersion(&DescribeProducersRequest{}),
+				apiKeyDescribeQuorum:               maxVersion(&DescribeQuorumRequest{}),
+				apiKeyUnregisterBroker:             maxVersion(&UnregisterBrokerRequest{}),
 				apiKeyDescribeTransactions:         maxVersion(&DescribeTransactionsRequest{}),
 				apiKeyListTransactions:             maxVersion(&ListTransactionsRequest{}),
+				apiKeyConsumerGroupHeartbeat:       maxVersion(&ConsumerGroupHeartbeatRequest{}),
+				apiKeyConsumerGroupDescribe:        maxVersion(&ConsumerGroupDescribeRequest{}),
+				apiKeyGetTelemetrySubscriptions:    maxVersion(&GetTelemetrySubscriptionsRequest{}),
+				apiKeyPushTelemetry:                maxVersion(&PushTelemetryRequest{}),
+				apiKeyDescribeTopicPartitions:      maxVersion(&DescribeTopicPartitionsRequest{}),
+				apiKeyShareGroupHeartbeat:          maxVersion(&ShareGroupHeartbeatRequest{}),
+				apiKeyShareFetch:                   maxVersion(&ShareFetchRequest{}),
+				apiKeyShareAcknowledge:             maxVersion(&ShareAcknowledgeRequest{}),
+				apiKeyAddRaftVoter:                 maxVersion(&AddRaftVoterRequest{}),
+				apiKeyRemoveRaftVoter:              maxVersion(&RemoveRaftVoterRequest{}),
 			},
 		},
 	}
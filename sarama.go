@@ -64,6 +64,7 @@ Producer related metrics:
 	| records-per-request-for-topic-<topic>     | histogram  | Distribution of the number of records sent per request for a given topic             |
 	| compression-ratio                         | histogram  | Distribution of the compression ratio times 100 of record batches for all topics     |
 	| compression-ratio-for-topic-<topic>       | histogram  | Distribution of the compression ratio times 100 of record batches for a given topic  |
+	| producer-buffered-bytes                   | gauge      | Bytes of messages neither acknowledged nor failed yet (Producer.MaxBufferedBytes)    |
 	+-------------------------------------------+------------+--------------------------------------------------------------------------------------+
 
 Consumer related metrics:
@@ -71,15 +72,29 @@ Consumer related metrics:
 	+-------------------------------------------+------------+--------------------------------------------------------------------------------------+
 	| Name                                      | Type       | Description                                                                          |
 	+-------------------------------------------+------------+--------------------------------------------------------------------------------------+
+	| consumer-ack-pending                      | histogram  | Distribution of the number of delivered messages that cannot be committed yet        |
+	|                                           |            | because they or an earlier message are not acknowledged                              |
+	| consumer-ack-pending-for-topic-<topic>    | histogram  | Same as consumer-ack-pending for the partitions of a given topic                     |
+	| consumer-ack-waiting                      | histogram  | Distribution of the number of acknowledged messages waiting for an earlier one       |
+	| consumer-ack-waiting-for-topic-<topic>    | histogram  | Same as consumer-ack-waiting for the partitions of a given topic                     |
 	| consumer-batch-size                       | histogram  | Distribution of the number of messages in a batch                                    |
+	| consumer-buffered-bytes                   | gauge      | Bytes of fetched messages not yet received by the user (Consumer.MaxBufferedBytes)   |
 	| consumer-fetch-rate                       | meter      | Fetch requests/second sent to all brokers                                            |
 	| consumer-fetch-rate-for-broker-<broker>   | meter      | Fetch requests/second sent to a given broker                                         |
 	| consumer-fetch-rate-for-topic-<topic>     | meter      | Fetch requests/second sent for a given topic                                         |
 	| consumer-fetch-response-size              | histogram  | Distribution of the fetch response size in bytes                                     |
+	| consumer-fetch-session-full-rate          | meter      | Full fetch requests/second sent to create a fetch session (KIP-227)                  |
+	| consumer-fetch-session-incremental-rate   | meter      | Incremental fetch requests/second sent within a fetch session                        |
+	| consumer-fetch-session-error-rate         | meter      | Fetch session errors/second that caused a fallback to a full fetch                   |
+	| consumer-fetch-session-partitions         | histogram  | Distribution of the number of partitions sent per fetch request                      |
 	| consumer-group-join-total-<GroupID>       | counter    | Total count of consumer group join attempts                                          |
 	| consumer-group-join-failed-<GroupID>      | counter    | Total count of consumer group join failures                                          |
 	| consumer-group-sync-total-<GroupID>       | counter    | Total count of consumer group sync attempts                                          |
 	| consumer-group-sync-failed-<GroupID>      | counter    | Total count of consumer group sync failures                                          |
+	| consumer-lag-for-partition-<tp>           | gauge      | Number of messages of a given partition not handed over yet, <tp> being              |
+	|                                           |            | <topic>-<partition>                                                                  |
+	| consumer-time-lag-for-partition-<tp>      | gauge      | Age in milliseconds of the last message of a given partition handed over,            |
+	|                                           |            | or 0 once there is no lag                                                            |
 	+-------------------------------------------+------------+--------------------------------------------------------------------------------------+
 */
 package sarama
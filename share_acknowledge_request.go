@@ -0,0 +1,308 @@
+package sarama
+
+// AcknowledgeType is the type of acknowledgement sent by a member of a share
+// group for a record it acquired (KIP-932).
+type AcknowledgeType int8
+
+const (
+	// AcknowledgeTypeGap acknowledges an offset that does not contain a
+	// record, such as a control record or a record removed by compaction.
+	AcknowledgeTypeGap AcknowledgeType = 0
+	// AcknowledgeTypeAccept marks the record as successfully processed.
+	AcknowledgeTypeAccept AcknowledgeType = 1
+	// AcknowledgeTypeRelease releases the record so that it can be delivered
+	// again, to this or another member.
+	AcknowledgeTypeRelease AcknowledgeType = 2
+	// AcknowledgeTypeReject marks the record as unprocessable, it is not
+	// delivered again.
+	AcknowledgeTypeReject AcknowledgeType = 3
+)
+
+func (t AcknowledgeType) String() string {
+	switch t {
+	case AcknowledgeTypeGap:
+		return "gap"
+	case AcknowledgeTypeAccept:
+		return "accept"
+	case AcknowledgeTypeRelease:
+		return "release"
+	case AcknowledgeTypeReject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// AcknowledgementBatch acknowledges a range of offsets of a partition.
+type AcknowledgementBatch struct {
+	// FirstOffset contains the first offset of the batch of records to
+	// acknowledge.
+	FirstOffset int64
+	// LastOffset contains the last offset (inclusive) of the batch of records
+	// to acknowledge.
+	LastOffset int64
+	// AcknowledgeTypes contains either a single acknowledge type applying to
+	// every offset of the batch, or one acknowledge type per offset.
+	AcknowledgeTypes []AcknowledgeType
+}
+
+func (b *AcknowledgementBatch) encode(pe packetEncoder) error {
+	pe.putInt64(b.FirstOffset)
+	pe.putInt64(b.LastOffset)
+
+	if err := pe.putArrayLength(len(b.AcknowledgeTypes)); err != nil {
+		return err
+	}
+	for _, t := range b.AcknowledgeTypes {
+		pe.putInt8(int8(t))
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (b *AcknowledgementBatch) decode(pd packetDecoder) (err error) {
+	if b.FirstOffset, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	if b.LastOffset, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	b.AcknowledgeTypes = make([]AcknowledgeType, n)
+	for i := range n {
+		t, err := pd.getInt8()
+		if err != nil {
+			return err
+		}
+		b.AcknowledgeTypes[i] = AcknowledgeType(t)
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func encodeAcknowledgementBatches(pe packetEncoder, batches []AcknowledgementBatch) error {
+	if err := pe.putArrayLength(len(batches)); err != nil {
+		return err
+	}
+	for i := range batches {
+		if err := batches[i].encode(pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeAcknowledgementBatches(pd packetDecoder) ([]AcknowledgementBatch, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errInvalidArrayLength
+	}
+
+	batches := make([]AcknowledgementBatch, n)
+	for i := range n {
+		if err := batches[i].decode(pd); err != nil {
+			return nil, err
+		}
+	}
+	return batches, nil
+}
+
+// ShareAcknowledgeRequest is sent by members of a share group to acknowledge
+// records they acquired with a ShareFetchRequest, without fetching more.
+type ShareAcknowledgeRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// GroupID contains the group identifier.
+	GroupID *string
+	// MemberID contains the member id.
+	MemberID *string
+	// ShareSessionEpoch contains the current share session epoch: 0 to open
+	// a share session; -1 to close it; otherwise increments for consecutive
+	// requests.
+	ShareSessionEpoch int32
+	// Topics contains the topics containing records to acknowledge.
+	Topics []ShareAcknowledgeTopic
+}
+
+// ShareAcknowledgeTopic contains the partitions of a topic with records to
+// acknowledge.
+type ShareAcknowledgeTopic struct {
+	// TopicID contains the unique topic ID.
+	TopicID Uuid
+	// Partitions contains the partitions containing records to acknowledge.
+	Partitions []ShareAcknowledgePartition
+}
+
+// ShareAcknowledgePartition contains the acknowledgements of a partition.
+type ShareAcknowledgePartition struct {
+	// PartitionIndex contains the partition index.
+	PartitionIndex int32
+	// AcknowledgementBatches contains the record batches being acknowledged.
+	AcknowledgementBatches []AcknowledgementBatch
+}
+
+// NewShareAcknowledgeRequest returns a ShareAcknowledgeRequest using the
+// highest protocol version supported by the given Kafka version.
+func NewShareAcknowledgeRequest(version KafkaVersion) *ShareAcknowledgeRequest {
+	r := &ShareAcknowledgeRequest{}
+	if version.IsAtLeast(V4_1_0_0) {
+		// Version 1 is the first version that is not early access.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ShareAcknowledgeRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareAcknowledgeRequest) encode(pe packetEncoder) error {
+	if err := pe.putNullableString(r.GroupID); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.ShareSessionEpoch)
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range r.Topics {
+		if err := pe.putUuid(topic.TopicID); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition.PartitionIndex)
+
+			if err := encodeAcknowledgementBatches(pe, partition.AcknowledgementBatches); err != nil {
+				return err
+			}
+
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareAcknowledgeRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.GroupID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.ShareSessionEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]ShareAcknowledgeTopic, n)
+	for i := range r.Topics {
+		topic := &r.Topics[i]
+		if topic.TopicID, err = pd.getUuid(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]ShareAcknowledgePartition, m)
+		for j := range topic.Partitions {
+			partition := &topic.Partitions[j]
+			if partition.PartitionIndex, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if partition.AcknowledgementBatches, err = decodeAcknowledgementBatches(pd); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareAcknowledgeRequest) key() int16 {
+	return apiKeyShareAcknowledge
+}
+
+func (r *ShareAcknowledgeRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ShareAcknowledgeRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *ShareAcknowledgeRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareAcknowledgeRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareAcknowledgeRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareAcknowledgeRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
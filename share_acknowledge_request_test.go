@@ -0,0 +1,50 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var shareAcknowledgeRequestV0 = []byte{
+	2, 'g', // GroupID
+	2, 'm', // MemberID
+	0xff, 0xff, 0xff, 0xff, // ShareSessionEpoch
+	2,                                                     // Topics
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+	2,          // Partitions
+	0, 0, 0, 3, // PartitionIndex
+	2,                      // AcknowledgementBatches
+	0, 0, 0, 0, 0, 0, 0, 0, // FirstOffset
+	0, 0, 0, 0, 0, 0, 0, 9, // LastOffset
+	2, 2, // AcknowledgeTypes
+	0, // empty tagged fields
+	0, // empty tagged fields
+	0, // empty tagged fields
+	0, // empty tagged fields
+}
+
+func TestShareAcknowledgeRequest(t *testing.T) {
+	group := "g"
+	member := "m"
+
+	request := &ShareAcknowledgeRequest{
+		Version:           0,
+		GroupID:           &group,
+		MemberID:          &member,
+		ShareSessionEpoch: -1,
+		Topics: []ShareAcknowledgeTopic{{
+			TopicID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			Partitions: []ShareAcknowledgePartition{{
+				PartitionIndex: 3,
+				AcknowledgementBatches: []AcknowledgementBatch{{
+					FirstOffset:      0,
+					LastOffset:       9,
+					AcknowledgeTypes: []AcknowledgeType{AcknowledgeTypeRelease},
+				}},
+			}},
+		}},
+	}
+	testRequest(t, "v0", request, shareAcknowledgeRequestV0)
+
+	request.Version = 1
+	testRequest(t, "v1", request, shareAcknowledgeRequestV0)
+}
@@ -0,0 +1,309 @@
+package sarama
+
+import "time"
+
+// NodeEndpoint contains the endpoint of a broker that was returned as the new
+// leader of a partition.
+type NodeEndpoint struct {
+	// NodeID contains the ID of the associated node.
+	NodeID int32
+	// Host contains the node's hostname.
+	Host string
+	// Port contains the node's port.
+	Port int32
+	// Rack contains the rack of the node, or null if it has not been
+	// assigned to a rack.
+	Rack *string
+}
+
+func (n *NodeEndpoint) encode(pe packetEncoder) error {
+	pe.putInt32(n.NodeID)
+
+	if err := pe.putString(n.Host); err != nil {
+		return err
+	}
+
+	pe.putInt32(n.Port)
+
+	if err := pe.putNullableString(n.Rack); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (n *NodeEndpoint) decode(pd packetDecoder) (err error) {
+	if n.NodeID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if n.Host, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if n.Port, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if n.Rack, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func encodeNodeEndpoints(pe packetEncoder, endpoints []NodeEndpoint) error {
+	if err := pe.putArrayLength(len(endpoints)); err != nil {
+		return err
+	}
+	for i := range endpoints {
+		if err := endpoints[i].encode(pe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeNodeEndpoints(pd packetDecoder) ([]NodeEndpoint, error) {
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errInvalidArrayLength
+	}
+
+	endpoints := make([]NodeEndpoint, n)
+	for i := range n {
+		if err := endpoints[i].decode(pd); err != nil {
+			return nil, err
+		}
+	}
+	return endpoints, nil
+}
+
+func encodeShareCurrentLeader(pe packetEncoder, leader FetchResponseCurrentLeader) {
+	pe.putInt32(leader.LeaderID)
+	pe.putInt32(leader.LeaderEpoch)
+	pe.putEmptyTaggedFieldArray()
+}
+
+func decodeShareCurrentLeader(pd packetDecoder) (leader FetchResponseCurrentLeader, err error) {
+	if leader.LeaderID, err = pd.getInt32(); err != nil {
+		return leader, err
+	}
+
+	if leader.LeaderEpoch, err = pd.getInt32(); err != nil {
+		return leader, err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return leader, err
+}
+
+// ShareAcknowledgeResponse is returned by the partition leaders in response
+// to a ShareAcknowledgeRequest.
+type ShareAcknowledgeResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+	// Err contains the top-level error code, or ErrNoError if there was no
+	// error.
+	Err KError
+	// ErrorMessage contains the top-level error message, or null if there was
+	// no error.
+	ErrorMessage *string
+	// Responses contains the response topics.
+	Responses []ShareAcknowledgeTopicResponse
+	// NodeEndpoints contains the endpoints of the brokers returned as new
+	// partition leaders.
+	NodeEndpoints []NodeEndpoint
+}
+
+// ShareAcknowledgeTopicResponse contains the acknowledgement results of the
+// partitions of a topic.
+type ShareAcknowledgeTopicResponse struct {
+	// TopicID contains the unique topic ID.
+	TopicID Uuid
+	// Partitions contains the topic partitions.
+	Partitions []ShareAcknowledgePartitionResponse
+}
+
+// ShareAcknowledgePartitionResponse contains the acknowledgement result of a
+// partition.
+type ShareAcknowledgePartitionResponse struct {
+	// PartitionIndex contains the partition index.
+	PartitionIndex int32
+	// Err contains the error code, or ErrNoError if there was no error.
+	Err KError
+	// ErrorMessage contains the error message, or null if there was no
+	// error.
+	ErrorMessage *string
+	// CurrentLeader contains the current leader of the partition.
+	CurrentLeader FetchResponseCurrentLeader
+}
+
+func (r *ShareAcknowledgeResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareAcknowledgeResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.Err)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	if err := pe.putArrayLength(len(r.Responses)); err != nil {
+		return err
+	}
+	for _, topic := range r.Responses {
+		if err := pe.putUuid(topic.TopicID); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition.PartitionIndex)
+			pe.putKError(partition.Err)
+
+			if err := pe.putNullableString(partition.ErrorMessage); err != nil {
+				return err
+			}
+
+			encodeShareCurrentLeader(pe, partition.CurrentLeader)
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	if err := encodeNodeEndpoints(pe, r.NodeEndpoints); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Responses = make([]ShareAcknowledgeTopicResponse, n)
+	for i := range r.Responses {
+		topic := &r.Responses[i]
+		if topic.TopicID, err = pd.getUuid(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]ShareAcknowledgePartitionResponse, m)
+		for j := range topic.Partitions {
+			partition := &topic.Partitions[j]
+			if partition.PartitionIndex, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if partition.Err, err = pd.getKError(); err != nil {
+				return err
+			}
+
+			if partition.ErrorMessage, err = pd.getNullableString(); err != nil {
+				return err
+			}
+
+			if partition.CurrentLeader, err = decodeShareCurrentLeader(pd); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if r.NodeEndpoints, err = decodeNodeEndpoints(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareAcknowledgeResponse) key() int16 {
+	return apiKeyShareAcknowledge
+}
+
+func (r *ShareAcknowledgeResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ShareAcknowledgeResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *ShareAcknowledgeResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareAcknowledgeResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareAcknowledgeResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareAcknowledgeResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
+
+func (r *ShareAcknowledgeResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
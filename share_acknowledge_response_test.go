@@ -0,0 +1,50 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var shareAcknowledgeResponseV0 = []byte{
+	0, 0, 0, 0, // ThrottleTimeMs
+	0, 0, // ErrorCode
+	0,                                                     // ErrorMessage (null)
+	2,                                                     // Responses
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+	2,          // Partitions
+	0, 0, 0, 3, // PartitionIndex
+	0, 121, // ErrorCode
+	0,          // ErrorMessage (null)
+	0, 0, 0, 1, // CurrentLeader.LeaderId
+	0, 0, 0, 2, // CurrentLeader.LeaderEpoch
+	0,          // empty tagged fields
+	0,          // empty tagged fields
+	0,          // empty tagged fields
+	2,          // NodeEndpoints
+	0, 0, 0, 1, // NodeId
+	2, 'h', // Host
+	0, 0, 0x23, 0x84, // Port
+	2, 'r', // Rack
+	0, // empty tagged fields
+	0, // empty tagged fields
+}
+
+func TestShareAcknowledgeResponse(t *testing.T) {
+	rack := "r"
+
+	response := &ShareAcknowledgeResponse{
+		Version: 0,
+		Responses: []ShareAcknowledgeTopicResponse{{
+			TopicID: Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			Partitions: []ShareAcknowledgePartitionResponse{{
+				PartitionIndex: 3,
+				Err:            ErrInvalidRecordState,
+				CurrentLeader:  FetchResponseCurrentLeader{LeaderID: 1, LeaderEpoch: 2},
+			}},
+		}},
+		NodeEndpoints: []NodeEndpoint{{NodeID: 1, Host: "h", Port: 9092, Rack: &rack}},
+	}
+	testResponse(t, "v0", response, shareAcknowledgeResponseV0)
+
+	response.Version = 1
+	testResponse(t, "v1", response, shareAcknowledgeResponseV0)
+}
@@ -0,0 +1,885 @@
+package sarama
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosedShareConsumer is the error returned when a method is called on a share consumer that has been closed.
+var ErrClosedShareConsumer = errors.New("kafka: tried to use a share consumer that was closed")
+
+// shareSessionEpochClose is the share session epoch used to close a share session
+const shareSessionEpochClose int32 = -1
+
+// ShareConsumer is a member of a share group (KIP-932). Unlike the members of
+// a consumer group, the members of a share group cooperatively consume the
+// same partitions: each record is acquired by a single member at a time and
+// locked for the duration of its acquisition lock. A record that is released,
+// or whose lock expires before it was acknowledged, is delivered again to this
+// or another member of the share group.
+//
+// Share groups require Kafka 4.0 or later. The share group protocol must be
+// enabled on the brokers.
+type ShareConsumer interface {
+	// Subscribe joins the share group for the given topics and starts
+	// acquiring records from the partitions assigned to this member by the
+	// group coordinator. Calling Subscribe again replaces the subscription.
+	Subscribe(topics []string) error
+
+	// Messages returns the read channel for the records acquired by this
+	// member. Each record must be acknowledged with Accept, Release or Reject
+	// before its acquisition lock expires, otherwise it is delivered again.
+	// The channel is closed by Close.
+	Messages() <-chan *ShareMessage
+
+	// Errors returns a read channel of errors that occurred during the consumer life-cycle.
+	// By default, errors are logged and not returned over this channel.
+	// If you want to implement any custom error handling, set your config's
+	// Consumer.Return.Errors setting to true, and read from this channel.
+	Errors() <-chan error
+
+	// Close sends the pending acknowledgements, releases the records that were
+	// not read from the Messages channel yet and leaves the share group. It is
+	// required to call this function before the object passes out of scope, as
+	// it will otherwise leak memory.
+	Close() error
+}
+
+// ShareMessage is a record acquired by a member of a share group.
+type ShareMessage struct {
+	ConsumerMessage
+
+	// DeliveryCount is the number of times the record was acquired by a
+	// member of the share group, including this delivery.
+	DeliveryCount int16
+	// LockDeadline is the time at which the acquisition lock of the record
+	// expires, or the zero time if the broker does not return the lock
+	// duration (Kafka < 4.1).
+	LockDeadline time.Time
+
+	consumer     *shareConsumer
+	acknowledged atomic.Bool
+}
+
+// Accept acknowledges the record as successfully processed.
+func (m *ShareMessage) Accept() {
+	m.acknowledge(AcknowledgeTypeAccept)
+}
+
+// Release releases the record so that it is delivered again, to this or
+// another member of the share group.
+func (m *ShareMessage) Release() {
+	m.acknowledge(AcknowledgeTypeRelease)
+}
+
+// Reject acknowledges the record as unprocessable, it is not delivered again.
+func (m *ShareMessage) Reject() {
+	m.acknowledge(AcknowledgeTypeReject)
+}
+
+// acknowledge records the acknowledgement of the message, which is sent with
+// the next request to the partition leader. Only the first acknowledgement of
+// a message is taken into account.
+func (m *ShareMessage) acknowledge(t AcknowledgeType) {
+	if m.consumer == nil || m.acknowledged.Swap(true) {
+		return
+	}
+	m.consumer.acknowledge(topicPartition{topic: m.Topic, partition: m.Partition}, m.Offset, t)
+}
+
+type shareConsumer struct {
+	client Client
+
+	config   *Config
+	groupID  string
+	memberID string
+	messages chan *ShareMessage
+	errors   chan error
+
+	lock         sync.Mutex
+	subscription []string
+	subscribed   []string
+	assignment   map[topicPartition]Uuid
+	unresolved   bool
+	fetchers     map[int32]*shareFetcher
+
+	ackLock sync.Mutex
+	acks    map[topicPartition]map[int64]AcknowledgeType
+
+	// membership state, only touched by the heartbeat loop and Close
+	memberEpoch       int32
+	heartbeatInterval time.Duration
+	topicIDs          *topicIDCache
+
+	errorsLock sync.RWMutex
+	closed     chan none
+	closeOnce  sync.Once
+	startOnce  sync.Once
+	wg         sync.WaitGroup
+}
+
+// NewShareConsumer creates a new member of a share group using the given broker addresses and configuration.
+func NewShareConsumer(addrs []string, groupID string, config *Config) (ShareConsumer, error) {
+	client, err := NewClient(addrs, config)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newShareConsumer(groupID, client)
+	if err != nil {
+		_ = client.Close()
+	}
+	return c, err
+}
+
+// NewShareConsumerFromClient creates a new member of a share group using the given client. It is still
+// necessary to call Close() on the underlying client when shutting down this consumer.
+// PLEASE NOTE: share consumers can only re-use but not share clients.
+func NewShareConsumerFromClient(groupID string, client Client) (ShareConsumer, error) {
+	if client == nil {
+		return nil, ConfigurationError("client must not be nil")
+	}
+	// For clients passed in by the client, ensure we don't
+	// call Close() on it.
+	cli := &nopCloserClient{client}
+	return newShareConsumer(groupID, cli)
+}
+
+func newShareConsumer(groupID string, client Client) (ShareConsumer, error) {
+	config := client.Config()
+	if !config.Version.IsAtLeast(V4_0_0_0) {
+		return nil, ConfigurationError("share groups require Version to be >= V4_0_0_0")
+	}
+
+	return &shareConsumer{
+		client:            client,
+		config:            config,
+		groupID:           groupID,
+		memberID:          newMemberID(),
+		messages:          make(chan *ShareMessage, config.ChannelBufferSize),
+		errors:            make(chan error, config.ChannelBufferSize),
+		fetchers:          make(map[int32]*shareFetcher),
+		acks:              make(map[topicPartition]map[int64]AcknowledgeType),
+		heartbeatInterval: config.Consumer.Group.Heartbeat.Interval,
+		topicIDs:          newTopicIDCache(),
+		closed:            make(chan none),
+	}, nil
+}
+
+// Subscribe implements ShareConsumer.
+func (c *shareConsumer) Subscribe(topics []string) error {
+	select {
+	case <-c.closed:
+		return ErrClosedShareConsumer
+	default:
+	}
+
+	if len(topics) == 0 {
+		return fmt.Errorf("no topics provided")
+	}
+
+	if err := c.client.RefreshMetadata(topics...); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.subscription = slices.Clone(topics)
+	c.lock.Unlock()
+
+	c.startOnce.Do(func() {
+		c.wg.Add(1)
+		go withRecover(c.heartbeatLoop)
+	})
+	return nil
+}
+
+// Messages implements ShareConsumer.
+func (c *shareConsumer) Messages() <-chan *ShareMessage { return c.messages }
+
+// Errors implements ShareConsumer.
+func (c *shareConsumer) Errors() <-chan error { return c.errors }
+
+// Close implements ShareConsumer.
+func (c *shareConsumer) Close() (err error) {
+	c.closeOnce.Do(func() {
+		// no fetcher is started once closed, see updateFetchers
+		c.lock.Lock()
+		close(c.closed)
+		c.lock.Unlock()
+		c.wg.Wait()
+
+		// release the records nobody is going to read
+		for done := false; !done; {
+			select {
+			case msg := <-c.messages:
+				msg.Release()
+			default:
+				done = true
+			}
+		}
+		close(c.messages)
+
+		for _, fetcher := range c.fetchers {
+			if e := fetcher.close(); e != nil {
+				err = e
+			}
+		}
+
+		if e := c.leave(); e != nil {
+			err = e
+		}
+
+		go func() {
+			c.errorsLock.Lock()
+			defer c.errorsLock.Unlock()
+			close(c.errors)
+		}()
+
+		// drain errors
+		for e := range c.errors {
+			err = e
+		}
+
+		if e := c.client.Close(); e != nil {
+			err = e
+		}
+	})
+	return
+}
+
+func (c *shareConsumer) heartbeatLoop() {
+	defer c.wg.Done()
+
+	pause := time.NewTimer(0)
+	defer pause.Stop()
+
+	for {
+		select {
+		case <-pause.C:
+		case <-c.closed:
+			return
+		}
+		pause.Reset(c.heartbeat())
+	}
+}
+
+// heartbeat sends the member state to the group coordinator, applies the
+// assignment it returns and reports how long to wait for the next heartbeat
+func (c *shareConsumer) heartbeat() time.Duration {
+	backoff := c.config.Consumer.Group.Rebalance.Retry.Backoff
+
+	coordinator, err := c.client.Coordinator(c.groupID)
+	if err != nil {
+		c.handleError(err, "", -1)
+		return backoff
+	}
+
+	c.lock.Lock()
+	subscription := c.subscription
+	changed := !slices.Equal(subscription, c.subscribed)
+	c.lock.Unlock()
+
+	if err := c.topicIDs.resolve(coordinator, c.config.Version, subscription); err != nil {
+		c.handleError(err, "", -1)
+		return backoff
+	}
+
+	req := NewShareGroupHeartbeatRequest(c.config.Version)
+	req.GroupID = c.groupID
+	req.MemberID = c.memberID
+	req.MemberEpoch = c.memberEpoch
+	if c.config.RackID != "" {
+		req.RackID = &c.config.RackID
+	}
+	if c.memberEpoch == 0 || changed {
+		req.SubscribedTopicNames = subscription
+	}
+
+	resp, err := coordinator.ShareGroupHeartbeat(req)
+	if err != nil {
+		_ = coordinator.Close()
+		c.handleError(err, "", -1)
+		return backoff
+	}
+
+	switch resp.Err {
+	case ErrNoError:
+		c.lock.Lock()
+		c.subscribed = subscription
+		unresolved := c.unresolved
+		c.lock.Unlock()
+
+		c.memberEpoch = resp.MemberEpoch
+		if resp.HeartbeatIntervalMs > 0 {
+			c.heartbeatInterval = time.Duration(resp.HeartbeatIntervalMs) * time.Millisecond
+		}
+		if resp.Assignment != nil {
+			claims, err := c.topicIDs.claims(coordinator, c.config.Version, subscription, resp.Assignment.TopicPartitions)
+			if err != nil {
+				c.handleError(err, "", -1)
+				return backoff
+			}
+			c.assign(claims)
+		} else if unresolved {
+			c.refreshLeaders()
+		}
+		return c.heartbeatInterval
+	case ErrUnknownMemberId, ErrFencedMemberEpoch:
+		// rejoin from scratch, the records acquired so far can still be
+		// acknowledged as acquisition locks belong to the member id
+		Logger.Printf("sharegroup/%s: rejoining the group due to %v\n", c.groupID, resp.Err)
+		c.memberEpoch = 0
+		c.lock.Lock()
+		c.subscribed = nil
+		c.lock.Unlock()
+		c.assign(nil)
+		return 0
+	case ErrNotCoordinatorForConsumer, ErrConsumerCoordinatorNotAvailable, ErrOffsetsLoadInProgress:
+		_ = c.client.RefreshCoordinator(c.groupID)
+		return backoff
+	default:
+		c.handleError(resp.Err, "", -1)
+		return backoff
+	}
+}
+
+// assign replaces the assignment of the member and hands the assigned
+// partitions over to the fetchers of their leaders
+func (c *shareConsumer) assign(claims map[string][]int32) {
+	assignment := make(map[topicPartition]Uuid)
+	for topic, partitions := range claims {
+		for _, partition := range partitions {
+			assignment[topicPartition{topic: topic, partition: partition}] = c.topicIDs.ids[topic]
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.assignment = assignment
+	c.updateFetchers()
+}
+
+// refreshLeaders refreshes the metadata of the assigned partitions and moves
+// them to the fetchers of their current leaders
+func (c *shareConsumer) refreshLeaders() {
+	c.lock.Lock()
+	var topics []string
+	for tp := range c.assignment {
+		if !slices.Contains(topics, tp.topic) {
+			topics = append(topics, tp.topic)
+		}
+	}
+	c.lock.Unlock()
+
+	if err := c.client.RefreshMetadata(topics...); err != nil {
+		Logger.Printf("sharegroup/%s: failed to refresh metadata: %v\n", c.groupID, err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.updateFetchers()
+}
+
+// updateFetchers distributes the assigned partitions over one fetcher per
+// partition leader, called with the lock held
+func (c *shareConsumer) updateFetchers() {
+	partitions := make(map[int32]map[topicPartition]Uuid)
+	c.unresolved = false
+	for tp, id := range c.assignment {
+		leader, err := c.client.Leader(tp.topic, tp.partition)
+		if err != nil {
+			Logger.Printf("sharegroup/%s: no leader for %s/%d: %v\n", c.groupID, tp.topic, tp.partition, err)
+			c.unresolved = true
+			continue
+		}
+		if partitions[leader.ID()] == nil {
+			partitions[leader.ID()] = make(map[topicPartition]Uuid)
+		}
+		partitions[leader.ID()][tp] = id
+	}
+
+	for id, fetcher := range c.fetchers {
+		fetcher.partitions = partitions[id]
+	}
+
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+	for id, assigned := range partitions {
+		if _, ok := c.fetchers[id]; ok {
+			continue
+		}
+		fetcher := &shareFetcher{parent: c, brokerID: id, partitions: assigned}
+		c.fetchers[id] = fetcher
+		c.wg.Add(1)
+		go withRecover(fetcher.run)
+	}
+}
+
+// acknowledge records the acknowledgement of an offset until it is sent to
+// the partition leader
+func (c *shareConsumer) acknowledge(tp topicPartition, offset int64, t AcknowledgeType) {
+	c.ackLock.Lock()
+	defer c.ackLock.Unlock()
+
+	if c.acks[tp] == nil {
+		c.acks[tp] = make(map[int64]AcknowledgeType)
+	}
+	c.acks[tp][offset] = t
+}
+
+// takeAcknowledgements removes and returns the pending acknowledgements of the
+// given partitions
+func (c *shareConsumer) takeAcknowledgements(partitions map[topicPartition]Uuid) map[topicPartition][]AcknowledgementBatch {
+	c.ackLock.Lock()
+	defer c.ackLock.Unlock()
+
+	acks := make(map[topicPartition][]AcknowledgementBatch)
+	for tp := range partitions {
+		if offsets := c.acks[tp]; len(offsets) > 0 {
+			acks[tp] = acknowledgementBatches(offsets)
+			delete(c.acks, tp)
+		}
+	}
+	return acks
+}
+
+// restoreAcknowledgements puts back acknowledgements that could not be sent
+func (c *shareConsumer) restoreAcknowledgements(acks map[topicPartition][]AcknowledgementBatch) {
+	for tp, batches := range acks {
+		for _, batch := range batches {
+			for offset := batch.FirstOffset; offset <= batch.LastOffset; offset++ {
+				t := batch.AcknowledgeTypes[0]
+				if len(batch.AcknowledgeTypes) > 1 {
+					t = batch.AcknowledgeTypes[offset-batch.FirstOffset]
+				}
+				c.acknowledge(tp, offset, t)
+			}
+		}
+	}
+}
+
+// acknowledgementBatches merges consecutive offsets with the same
+// acknowledgement type into batches
+func acknowledgementBatches(offsets map[int64]AcknowledgeType) []AcknowledgementBatch {
+	var batches []AcknowledgementBatch
+	for _, offset := range slices.Sorted(maps.Keys(offsets)) {
+		t := offsets[offset]
+		if n := len(batches); n > 0 && batches[n-1].LastOffset == offset-1 && batches[n-1].AcknowledgeTypes[0] == t {
+			batches[n-1].LastOffset = offset
+			continue
+		}
+		batches = append(batches, AcknowledgementBatch{
+			FirstOffset:      offset,
+			LastOffset:       offset,
+			AcknowledgeTypes: []AcknowledgeType{t},
+		})
+	}
+	return batches
+}
+
+// leave leaves the share group by heartbeating with a leave member epoch
+func (c *shareConsumer) leave() error {
+	if c.memberEpoch == 0 {
+		return nil
+	}
+
+	coordinator, err := c.client.Coordinator(c.groupID)
+	if err != nil {
+		return err
+	}
+
+	req := NewShareGroupHeartbeatRequest(c.config.Version)
+	req.GroupID = c.groupID
+	req.MemberID = c.memberID
+	req.MemberEpoch = memberEpochLeave
+
+	resp, err := coordinator.ShareGroupHeartbeat(req)
+	if err != nil {
+		_ = coordinator.Close()
+		return err
+	}
+	c.memberEpoch = 0
+
+	switch resp.Err {
+	case ErrNoError, ErrUnknownMemberId, ErrFencedMemberEpoch:
+		return nil
+	default:
+		return resp.Err
+	}
+}
+
+func (c *shareConsumer) handleError(err error, topic string, partition int32) {
+	var consumerError *ConsumerError
+	if ok := errors.As(err, &consumerError); !ok && topic != "" && partition > -1 {
+		err = &ConsumerError{
+			Topic:     topic,
+			Partition: partition,
+			Err:       err,
+		}
+	}
+
+	if !c.config.Consumer.Return.Errors {
+		Logger.Println(err)
+		return
+	}
+
+	c.errorsLock.RLock()
+	defer c.errorsLock.RUnlock()
+	select {
+	case <-c.closed:
+		// consumer is closed
+		return
+	default:
+	}
+
+	select {
+	case c.errors <- err:
+	default:
+		// no error listener
+	}
+}
+
+// shareFetcher acquires the records of the partitions led by a single broker
+// through a share session, piggybacking the acknowledgements of the records
+// it acquired on the following fetch requests
+type shareFetcher struct {
+	parent   *shareConsumer
+	brokerID int32
+
+	// partitions assigned to the fetcher, guarded by the parent lock
+	partitions map[topicPartition]Uuid
+
+	// share session state, only touched by the fetcher goroutine and Close
+	broker  *Broker
+	epoch   int32
+	session map[topicPartition]Uuid
+}
+
+func (f *shareFetcher) run() {
+	c := f.parent
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+
+		c.lock.Lock()
+		partitions := f.partitions
+		c.lock.Unlock()
+
+		if len(partitions) == 0 && len(f.session) == 0 {
+			// nothing to fetch until partitions are assigned to this broker
+			select {
+			case <-c.closed:
+				return
+			case <-time.After(c.config.Consumer.MaxWaitTime):
+			}
+			continue
+		}
+
+		if err := f.fetch(partitions); err != nil {
+			c.handleError(err, "", -1)
+			select {
+			case <-c.closed:
+				return
+			case <-time.After(c.config.Consumer.Retry.Backoff):
+			}
+		}
+	}
+}
+
+// resetSession forgets the share session so that the next request opens a
+// new one, keeping the acknowledgements that could not be sent
+func (f *shareFetcher) resetSession(acks map[topicPartition][]AcknowledgementBatch) {
+	f.epoch = 0
+	f.session = nil
+	f.parent.restoreAcknowledgements(acks)
+}
+
+func (f *shareFetcher) fetch(partitions map[topicPartition]Uuid) error {
+	c := f.parent
+
+	if f.epoch == 0 {
+		broker, err := c.client.Broker(f.brokerID)
+		if err != nil {
+			return err
+		}
+		f.broker = broker
+		f.session = make(map[topicPartition]Uuid)
+	}
+
+	req := NewShareFetchRequest(c.config.Version)
+	req.GroupID = &c.groupID
+	req.MemberID = &c.memberID
+	req.ShareSessionEpoch = f.epoch
+	req.MaxWaitMs = int32(c.config.Consumer.MaxWaitTime / time.Millisecond)
+	req.MinBytes = c.config.Consumer.Fetch.Min
+	req.MaxBytes = c.config.Consumer.Fetch.MaxBytes
+	req.MaxRecords = c.config.Consumer.Share.MaxRecords
+	req.BatchSize = c.config.Consumer.Share.MaxRecords
+
+	// acknowledgements cannot be sent while opening a share session
+	var acks map[topicPartition][]AcknowledgementBatch
+	if f.epoch != 0 {
+		acks = f.parent.takeAcknowledgements(f.session)
+	}
+
+	topics := make(map[Uuid]int)
+	addPartition := func(tp topicPartition, id Uuid) *ShareFetchPartition {
+		i, ok := topics[id]
+		if !ok {
+			i = len(req.Topics)
+			topics[id] = i
+			req.Topics = append(req.Topics, ShareFetchTopic{TopicID: id})
+		}
+		req.Topics[i].Partitions = append(req.Topics[i].Partitions, ShareFetchPartition{
+			PartitionIndex:    tp.partition,
+			PartitionMaxBytes: c.config.Consumer.Fetch.Default,
+		})
+		return &req.Topics[i].Partitions[len(req.Topics[i].Partitions)-1]
+	}
+
+	added := make(map[topicPartition]Uuid)
+	for tp, id := range partitions {
+		if _, ok := f.session[tp]; !ok {
+			addPartition(tp, id).AcknowledgementBatches = acks[tp]
+			added[tp] = id
+		}
+	}
+	for tp, batches := range acks {
+		if _, ok := added[tp]; !ok {
+			addPartition(tp, f.session[tp]).AcknowledgementBatches = batches
+		}
+	}
+
+	// partitions are forgotten once all their acknowledgements were sent
+	forgotten := make(map[topicPartition]Uuid)
+	forgottenTopics := make(map[Uuid]int)
+	for tp, id := range f.session {
+		if _, ok := partitions[tp]; ok {
+			continue
+		}
+		if _, ok := acks[tp]; ok {
+			continue
+		}
+		forgotten[tp] = id
+		i, ok := forgottenTopics[id]
+		if !ok {
+			i = len(req.ForgottenTopicsData)
+			forgottenTopics[id] = i
+			req.ForgottenTopicsData = append(req.ForgottenTopicsData, ShareFetchForgottenTopic{TopicID: id})
+		}
+		req.ForgottenTopicsData[i].Partitions = append(req.ForgottenTopicsData[i].Partitions, tp.partition)
+	}
+
+	resp, err := f.broker.ShareFetch(req)
+	if err != nil {
+		_ = f.broker.Close()
+		f.resetSession(acks)
+		go withRecover(c.refreshLeaders)
+		return err
+	}
+
+	switch resp.Err {
+	case ErrNoError:
+	case ErrShareSessionNotFound, ErrInvalidShareSessionEpoch:
+		Logger.Printf("sharegroup/%s: reopening share session with broker %d due to %v\n", c.groupID, f.brokerID, resp.Err)
+		f.resetSession(acks)
+		return nil
+	default:
+		f.resetSession(acks)
+		return resp.Err
+	}
+
+	if f.epoch == math.MaxInt32 {
+		f.epoch = 1
+	} else {
+		f.epoch++
+	}
+	for tp, id := range added {
+		f.session[tp] = id
+	}
+	for tp := range forgotten {
+		delete(f.session, tp)
+	}
+
+	var lockDeadline time.Time
+	if resp.AcquisitionLockTimeoutMs > 0 {
+		lockDeadline = time.Now().Add(time.Duration(resp.AcquisitionLockTimeoutMs) * time.Millisecond)
+	}
+
+	names := make(map[Uuid]string, len(f.session))
+	for tp, id := range f.session {
+		names[id] = tp.topic
+	}
+
+	leaderChanged := false
+	for _, topic := range resp.Responses {
+		name, ok := names[topic.TopicID]
+		if !ok {
+			continue
+		}
+		for i := range topic.Partitions {
+			partition := &topic.Partitions[i]
+			tp := topicPartition{topic: name, partition: partition.PartitionIndex}
+
+			if !errors.Is(partition.AcknowledgeErr, ErrNoError) {
+				c.handleError(partition.AcknowledgeErr, tp.topic, tp.partition)
+			}
+
+			switch partition.Err {
+			case ErrNoError:
+				if !f.deliver(tp, partition, lockDeadline) {
+					return nil
+				}
+			case ErrNotLeaderForPartition, ErrLeaderNotAvailable, ErrUnknownTopicOrPartition, ErrFencedLeaderEpoch:
+				leaderChanged = true
+			default:
+				c.handleError(partition.Err, tp.topic, tp.partition)
+			}
+		}
+	}
+
+	if leaderChanged {
+		c.refreshLeaders()
+	}
+	return nil
+}
+
+// deliver sends the acquired records of a partition to the Messages channel,
+// and acknowledges the acquired offsets without a record as gaps. It returns
+// false if the consumer was closed in the meantime.
+func (f *shareFetcher) deliver(tp topicPartition, partition *ShareFetchPartitionResponse, lockDeadline time.Time) bool {
+	c := f.parent
+	if len(partition.AcquiredRecords) == 0 {
+		return true
+	}
+
+	acquired := func(offset int64) *AcquiredRecords {
+		for i := range partition.AcquiredRecords {
+			if r := &partition.AcquiredRecords[i]; r.FirstOffset <= offset && offset <= r.LastOffset {
+				return r
+			}
+		}
+		return nil
+	}
+
+	var messages []*ShareMessage
+	delivered := make(map[int64]bool)
+	for _, records := range partition.RecordsSet {
+		batch := records.RecordBatch
+		if batch == nil || batch.Control {
+			continue
+		}
+		for _, rec := range batch.Records {
+			offset := batch.FirstOffset + rec.OffsetDelta
+			r := acquired(offset)
+			if r == nil || delivered[offset] {
+				continue
+			}
+			timestamp := batch.FirstTimestamp.Add(rec.TimestampDelta)
+			if batch.LogAppendTime {
+				timestamp = batch.MaxTimestamp
+			}
+			msg := &ShareMessage{
+				ConsumerMessage: ConsumerMessage{
+					Topic:     tp.topic,
+					Partition: tp.partition,
+					Key:       rec.Key,
+					Value:     rec.Value,
+					Offset:    offset,
+					Timestamp: timestamp,
+					Headers:   rec.Headers,
+				},
+				DeliveryCount: r.DeliveryCount,
+				LockDeadline:  lockDeadline,
+				consumer:      c,
+			}
+			for _, interceptor := range c.config.Consumer.Interceptors {
+				msg.ConsumerMessage.safelyApplyInterceptor(interceptor)
+			}
+			delivered[offset] = true
+			messages = append(messages, msg)
+		}
+	}
+
+	for _, r := range partition.AcquiredRecords {
+		for offset := r.FirstOffset; offset <= r.LastOffset; offset++ {
+			if !delivered[offset] {
+				c.acknowledge(tp, offset, AcknowledgeTypeGap)
+			}
+		}
+	}
+
+	for i, msg := range messages {
+		select {
+		case c.messages <- msg:
+		case <-c.closed:
+			for _, msg := range messages[i:] {
+				msg.Release()
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// close sends the pending acknowledgements of the share session and closes it
+func (f *shareFetcher) close() error {
+	if f.epoch == 0 {
+		return nil
+	}
+	c := f.parent
+
+	acks := c.takeAcknowledgements(f.session)
+
+	req := NewShareAcknowledgeRequest(c.config.Version)
+	req.GroupID = &c.groupID
+	req.MemberID = &c.memberID
+	req.ShareSessionEpoch = shareSessionEpochClose
+	topics := make(map[Uuid]int)
+	for tp, batches := range acks {
+		id := f.session[tp]
+		i, ok := topics[id]
+		if !ok {
+			i = len(req.Topics)
+			topics[id] = i
+			req.Topics = append(req.Topics, ShareAcknowledgeTopic{TopicID: id})
+		}
+		req.Topics[i].Partitions = append(req.Topics[i].Partitions, ShareAcknowledgePartition{
+			PartitionIndex:         tp.partition,
+			AcknowledgementBatches: batches,
+		})
+	}
+	f.epoch = 0
+
+	resp, err := f.broker.ShareAcknowledge(req)
+	if err != nil {
+		_ = f.broker.Close()
+		return err
+	}
+	if !errors.Is(resp.Err, ErrNoError) {
+		return resp.Err
+	}
+	for _, topic := range resp.Responses {
+		for _, partition := range topic.Partitions {
+			if !errors.Is(partition.Err, ErrNoError) {
+				return partition.Err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// mockShareGroup simulates the group coordinator and partition leader of a
+// share group: it assigns partition 0 of my-topic and hands out offsets 0 to 3
+// of which offset 3 is a control record.
+type mockShareGroup struct {
+	topicID Uuid
+
+	mu           sync.Mutex
+	heartbeats   []*ShareGroupHeartbeatRequest
+	fetches      []*ShareFetchRequest
+	acknowledges []*ShareAcknowledgeRequest
+}
+
+func (m *mockShareGroup) heartbeat(req *request) encoderWithHeader {
+	hb := req.body.(*ShareGroupHeartbeatRequest)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeats = append(m.heartbeats, hb)
+
+	res := &ShareGroupHeartbeatResponse{
+		Version:             hb.Version,
+		MemberID:            &hb.MemberID,
+		MemberEpoch:         hb.MemberEpoch,
+		HeartbeatIntervalMs: 10,
+	}
+	if hb.MemberEpoch == 0 {
+		res.MemberEpoch = 1
+		res.Assignment = &ShareGroupHeartbeatAssignment{
+			TopicPartitions: []ConsumerGroupHeartbeatTopicPartitions{
+				{TopicID: m.topicID, Partitions: []int32{0}},
+			},
+		}
+	}
+	return res
+}
+
+func (m *mockShareGroup) fetch(req *request) encoderWithHeader {
+	fetch := req.body.(*ShareFetchRequest)
+
+	m.mu.Lock()
+	m.fetches = append(m.fetches, fetch)
+	first := len(m.fetches) == 1
+	m.mu.Unlock()
+
+	res := &ShareFetchResponse{Version: fetch.Version, AcquisitionLockTimeoutMs: 30000}
+	if !first {
+		time.Sleep(10 * time.Millisecond)
+		return res
+	}
+
+	batch := &RecordBatch{Version: 2, FirstOffset: 0}
+	for i, value := range []string{"a", "b", "c"} {
+		batch.addRecord(&Record{OffsetDelta: int64(i), Value: []byte(value)})
+	}
+	records := newDefaultRecords(batch)
+	control := newDefaultRecords(&RecordBatch{Version: 2, FirstOffset: 3, Control: true})
+	res.Responses = []ShareFetchTopicResponse{{
+		TopicID: m.topicID,
+		Partitions: []ShareFetchPartitionResponse{{
+			PartitionIndex:  0,
+			RecordsSet:      []*Records{&records, &control},
+			AcquiredRecords: []AcquiredRecords{{FirstOffset: 0, LastOffset: 3, DeliveryCount: 1}},
+		}},
+	}}
+	return res
+}
+
+func (m *mockShareGroup) acknowledge(req *request) encoderWithHeader {
+	ack := req.body.(*ShareAcknowledgeRequest)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.acknowledges = append(m.acknowledges, ack)
+
+	return &ShareAcknowledgeResponse{Version: ack.Version}
+}
+
+// acknowledged returns the acknowledgement type sent for each offset
+func (m *mockShareGroup) acknowledged() map[int64]AcknowledgeType {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acks := make(map[int64]AcknowledgeType)
+	add := func(batches []AcknowledgementBatch) {
+		for _, batch := range batches {
+			for offset := batch.FirstOffset; offset <= batch.LastOffset; offset++ {
+				acks[offset] = batch.AcknowledgeTypes[0]
+			}
+		}
+	}
+	for _, fetch := range m.fetches {
+		for _, topic := range fetch.Topics {
+			for _, partition := range topic.Partitions {
+				add(partition.AcknowledgementBatches)
+			}
+		}
+	}
+	for _, ack := range m.acknowledges {
+		for _, topic := range ack.Topics {
+			for _, partition := range topic.Partitions {
+				add(partition.AcknowledgementBatches)
+			}
+		}
+	}
+	return acks
+}
+
+func TestShareConsumer(t *testing.T) {
+	config := NewTestConfig()
+	config.ClientID = t.Name()
+	config.Version = V4_1_0_0
+	config.Consumer.Return.Errors = true
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+
+	topicID := Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	group := &mockShareGroup{topicID: topicID}
+	broker0.SetHandlerFuncByMap(map[string]requestHandlerFunc{
+		"MetadataRequest": func(req *request) encoderWithHeader {
+			return NewMockMetadataResponse(t).
+				SetBroker(broker0.Addr(), broker0.BrokerID()).
+				SetLeader("my-topic", 0, broker0.BrokerID()).
+				SetTopicID("my-topic", topicID).
+				For(req.body)
+		},
+		"FindCoordinatorRequest": func(req *request) encoderWithHeader {
+			return NewMockFindCoordinatorResponse(t).
+				SetCoordinator(CoordinatorGroup, "my-group", broker0).
+				For(req.body)
+		},
+		"ShareGroupHeartbeatRequest": group.heartbeat,
+		"ShareFetchRequest":          group.fetch,
+		"ShareAcknowledgeRequest":    group.acknowledge,
+	})
+
+	consumer, err := NewShareConsumer([]string{broker0.Addr()}, "my-group", config)
+	assert.NoError(t, err)
+	assert.NoError(t, consumer.Subscribe([]string{"my-topic"}))
+
+	var messages []*ShareMessage
+	for range 3 {
+		select {
+		case msg := <-consumer.Messages():
+			messages = append(messages, msg)
+		case err := <-consumer.Errors():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for messages")
+		}
+	}
+	for i, msg := range messages {
+		assert.Equal(t, "my-topic", msg.Topic)
+		assert.Equal(t, int64(i), msg.Offset)
+		assert.Equal(t, int16(1), msg.DeliveryCount)
+		assert.False(t, msg.LockDeadline.IsZero())
+	}
+	assert.Equal(t, []byte("a"), messages[0].Value)
+
+	messages[0].Accept()
+	messages[1].Release()
+	messages[2].Reject()
+	messages[2].Accept() // only the first acknowledgement counts
+
+	expected := map[int64]AcknowledgeType{
+		0: AcknowledgeTypeAccept,
+		1: AcknowledgeTypeRelease,
+		2: AcknowledgeTypeReject,
+		3: AcknowledgeTypeGap,
+	}
+	assert.Eventually(t, func() bool {
+		acks := group.acknowledged()
+		return len(acks) == len(expected)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, expected, group.acknowledged())
+
+	assert.NoError(t, consumer.Close())
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	first := group.heartbeats[0]
+	assert.Equal(t, "my-group", first.GroupID)
+	assert.NotEmpty(t, first.MemberID)
+	assert.Equal(t, []string{"my-topic"}, first.SubscribedTopicNames)
+	last := group.heartbeats[len(group.heartbeats)-1]
+	assert.Equal(t, memberEpochLeave, last.MemberEpoch, "Close should leave the group")
+
+	assert.Equal(t, int32(0), group.fetches[0].ShareSessionEpoch)
+	assert.Equal(t, first.MemberID, *group.fetches[0].MemberID)
+	assert.Equal(t, int32(1), group.fetches[1].ShareSessionEpoch)
+	assert.Len(t, group.acknowledges, 1)
+	assert.Equal(t, shareSessionEpochClose, group.acknowledges[0].ShareSessionEpoch, "Close should close the share session")
+}
+
+func TestShareConsumerRequiresVersion(t *testing.T) {
+	config := NewTestConfig()
+	config.Version = V3_9_0_0
+
+	broker0 := NewMockBroker(t, 0)
+	defer broker0.Close()
+	broker0.SetHandlerByMap(map[string]MockResponse{
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()),
+	})
+
+	_, err := NewShareConsumer([]string{broker0.Addr()}, "my-group", config)
+	var target ConfigurationError
+	assert.ErrorAs(t, err, &target)
+}
+
+func TestAcknowledgementBatches(t *testing.T) {
+	batches := acknowledgementBatches(map[int64]AcknowledgeType{
+		4: AcknowledgeTypeAccept,
+		5: AcknowledgeTypeAccept,
+		6: AcknowledgeTypeRelease,
+		8: AcknowledgeTypeRelease,
+	})
+	assert.Equal(t, []AcknowledgementBatch{
+		{FirstOffset: 4, LastOffset: 5, AcknowledgeTypes: []AcknowledgeType{AcknowledgeTypeAccept}},
+		{FirstOffset: 6, LastOffset: 6, AcknowledgeTypes: []AcknowledgeType{AcknowledgeTypeRelease}},
+		{FirstOffset: 8, LastOffset: 8, AcknowledgeTypes: []AcknowledgeType{AcknowledgeTypeRelease}},
+	}, batches)
+}
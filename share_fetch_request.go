@@ -0,0 +1,295 @@
+package sarama
+
+// ShareFetchRequest is sent by members of a share group (KIP-932) to the
+// partition leaders to acquire records, optionally acknowledging records
+// acquired by previous requests of the same share session.
+type ShareFetchRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// GroupID contains the group identifier.
+	GroupID *string
+	// MemberID contains the member id.
+	MemberID *string
+	// ShareSessionEpoch contains the current share session epoch: 0 to open
+	// a share session; -1 to close it; otherwise increments for consecutive
+	// requests.
+	ShareSessionEpoch int32
+	// MaxWaitMs contains the maximum time in milliseconds to wait for the
+	// response.
+	MaxWaitMs int32
+	// MinBytes contains the minimum bytes to accumulate in the response.
+	MinBytes int32
+	// MaxBytes contains the maximum bytes to fetch.
+	MaxBytes int32
+	// MaxRecords contains the maximum number of records to fetch (v1+). This
+	// limit can be exceeded for alignment of batch boundaries.
+	MaxRecords int32
+	// BatchSize contains the optimal number of records for batches of
+	// acquired records and acknowledgements (v1+).
+	BatchSize int32
+	// Topics contains the topics to fetch.
+	Topics []ShareFetchTopic
+	// ForgottenTopicsData contains the partitions to remove from this share
+	// session.
+	ForgottenTopicsData []ShareFetchForgottenTopic
+}
+
+// ShareFetchTopic contains the partitions of a topic to fetch.
+type ShareFetchTopic struct {
+	// TopicID contains the unique topic ID.
+	TopicID Uuid
+	// Partitions contains the partitions to fetch.
+	Partitions []ShareFetchPartition
+}
+
+// ShareFetchPartition contains a partition to fetch and the acknowledgements
+// of records previously acquired from it.
+type ShareFetchPartition struct {
+	// PartitionIndex contains the partition index.
+	PartitionIndex int32
+	// PartitionMaxBytes contains the maximum bytes to fetch from this
+	// partition (v0 only).
+	PartitionMaxBytes int32
+	// AcknowledgementBatches contains the record batches being acknowledged.
+	AcknowledgementBatches []AcknowledgementBatch
+}
+
+// ShareFetchForgottenTopic contains the partitions of a topic to remove from
+// the share session.
+type ShareFetchForgottenTopic struct {
+	// TopicID contains the unique topic ID.
+	TopicID Uuid
+	// Partitions contains the partitions indexes to forget.
+	Partitions []int32
+}
+
+// NewShareFetchRequest returns a ShareFetchRequest using the highest protocol
+// version supported by the given Kafka version.
+func NewShareFetchRequest(version KafkaVersion) *ShareFetchRequest {
+	r := &ShareFetchRequest{}
+	if version.IsAtLeast(V4_1_0_0) {
+		// Version 1 adds the record limits and removes the per partition
+		// byte limit.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ShareFetchRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareFetchRequest) encode(pe packetEncoder) error {
+	if err := pe.putNullableString(r.GroupID); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.ShareSessionEpoch)
+	pe.putInt32(r.MaxWaitMs)
+	pe.putInt32(r.MinBytes)
+	pe.putInt32(r.MaxBytes)
+
+	if r.Version >= 1 {
+		pe.putInt32(r.MaxRecords)
+		pe.putInt32(r.BatchSize)
+	}
+
+	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range r.Topics {
+		if err := pe.putUuid(topic.TopicID); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition.PartitionIndex)
+
+			if r.Version == 0 {
+				pe.putInt32(partition.PartitionMaxBytes)
+			}
+
+			if err := encodeAcknowledgementBatches(pe, partition.AcknowledgementBatches); err != nil {
+				return err
+			}
+
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	if err := pe.putArrayLength(len(r.ForgottenTopicsData)); err != nil {
+		return err
+	}
+	for _, topic := range r.ForgottenTopicsData {
+		if err := pe.putUuid(topic.TopicID); err != nil {
+			return err
+		}
+
+		if err := pe.putInt32Array(topic.Partitions); err != nil {
+			return err
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareFetchRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.GroupID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.ShareSessionEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.MaxWaitMs, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.MinBytes, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.MaxBytes, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		if r.MaxRecords, err = pd.getInt32(); err != nil {
+			return err
+		}
+
+		if r.BatchSize, err = pd.getInt32(); err != nil {
+			return err
+		}
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Topics = make([]ShareFetchTopic, n)
+	for i := range r.Topics {
+		topic := &r.Topics[i]
+		if topic.TopicID, err = pd.getUuid(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]ShareFetchPartition, m)
+		for j := range topic.Partitions {
+			partition := &topic.Partitions[j]
+			if partition.PartitionIndex, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if r.Version == 0 {
+				if partition.PartitionMaxBytes, err = pd.getInt32(); err != nil {
+					return err
+				}
+			}
+
+			if partition.AcknowledgementBatches, err = decodeAcknowledgementBatches(pd); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if n, err = pd.getArrayLength(); err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.ForgottenTopicsData = make([]ShareFetchForgottenTopic, n)
+	for i := range r.ForgottenTopicsData {
+		topic := &r.ForgottenTopicsData[i]
+		if topic.TopicID, err = pd.getUuid(); err != nil {
+			return err
+		}
+
+		if topic.Partitions, err = pd.getInt32Array(); err != nil {
+			return err
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareFetchRequest) key() int16 {
+	return apiKeyShareFetch
+}
+
+func (r *ShareFetchRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ShareFetchRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *ShareFetchRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareFetchRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareFetchRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareFetchRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
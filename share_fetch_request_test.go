@@ -0,0 +1,103 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	shareFetchRequestV0 = []byte{
+		2, 'g', // GroupID
+		2, 'm', // MemberID
+		0, 0, 0, 2, // ShareSessionEpoch
+		0, 0, 1, 0xf4, // MaxWaitMs
+		0, 0, 0, 1, // MinBytes
+		0, 0x10, 0, 0, // MaxBytes
+		2,                                                     // Topics
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2,          // Partitions
+		0, 0, 0, 1, // PartitionIndex
+		0, 0, 0x10, 0, // PartitionMaxBytes
+		2,                      // AcknowledgementBatches
+		0, 0, 0, 0, 0, 0, 0, 5, // FirstOffset
+		0, 0, 0, 0, 0, 0, 0, 7, // LastOffset
+		4, 1, 0, 3, // AcknowledgeTypes
+		0,                                                     // empty tagged fields
+		0,                                                     // empty tagged fields
+		0,                                                     // empty tagged fields
+		2,                                                     // ForgottenTopicsData
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2, 0, 0, 0, 0, // Partitions
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	shareFetchRequestV1 = []byte{
+		0,          // GroupID (null)
+		0,          // MemberID (null)
+		0, 0, 0, 0, // ShareSessionEpoch
+		0, 0, 1, 0xf4, // MaxWaitMs
+		0, 0, 0, 1, // MinBytes
+		0, 0x10, 0, 0, // MaxBytes
+		0, 0, 1, 0xf4, // MaxRecords
+		0, 0, 1, 0xf4, // BatchSize
+		2,                                                     // Topics
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2,          // Partitions
+		0, 0, 0, 1, // PartitionIndex
+		1, // AcknowledgementBatches
+		0, // empty tagged fields
+		0, // empty tagged fields
+		1, // ForgottenTopicsData
+		0, // empty tagged fields
+	}
+)
+
+func TestShareFetchRequest(t *testing.T) {
+	group := "g"
+	member := "m"
+	topicID := Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	request := &ShareFetchRequest{
+		Version:           0,
+		GroupID:           &group,
+		MemberID:          &member,
+		ShareSessionEpoch: 2,
+		MaxWaitMs:         500,
+		MinBytes:          1,
+		MaxBytes:          1 << 20,
+		Topics: []ShareFetchTopic{{
+			TopicID: topicID,
+			Partitions: []ShareFetchPartition{{
+				PartitionIndex:    1,
+				PartitionMaxBytes: 4096,
+				AcknowledgementBatches: []AcknowledgementBatch{{
+					FirstOffset:      5,
+					LastOffset:       7,
+					AcknowledgeTypes: []AcknowledgeType{AcknowledgeTypeAccept, AcknowledgeTypeGap, AcknowledgeTypeReject},
+				}},
+			}},
+		}},
+		ForgottenTopicsData: []ShareFetchForgottenTopic{
+			{TopicID: topicID, Partitions: []int32{0}},
+		},
+	}
+	testRequest(t, "v0", request, shareFetchRequestV0)
+
+	request = &ShareFetchRequest{
+		Version:    1,
+		MaxWaitMs:  500,
+		MinBytes:   1,
+		MaxBytes:   1 << 20,
+		MaxRecords: 500,
+		BatchSize:  500,
+		Topics: []ShareFetchTopic{{
+			TopicID: topicID,
+			Partitions: []ShareFetchPartition{{
+				PartitionIndex:         1,
+				AcknowledgementBatches: []AcknowledgementBatch{},
+			}},
+		}},
+		ForgottenTopicsData: []ShareFetchForgottenTopic{},
+	}
+	testRequest(t, "v1", request, shareFetchRequestV1)
+}
@@ -0,0 +1,337 @@
+package sarama
+
+import (
+	"errors"
+	"time"
+)
+
+// ShareFetchResponse is returned by the partition leaders in response to a
+// ShareFetchRequest.
+type ShareFetchResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+	// Err contains the top-level error code, or ErrNoError if there was no
+	// error.
+	Err KError
+	// ErrorMessage contains the top-level error message, or null if there was
+	// no error.
+	ErrorMessage *string
+	// AcquisitionLockTimeoutMs contains the time in milliseconds for which
+	// the acquired records are locked (v1+).
+	AcquisitionLockTimeoutMs int32
+	// Responses contains the response topics.
+	Responses []ShareFetchTopicResponse
+	// NodeEndpoints contains the endpoints of the brokers returned as new
+	// partition leaders.
+	NodeEndpoints []NodeEndpoint
+}
+
+// ShareFetchTopicResponse contains the fetched partitions of a topic.
+type ShareFetchTopicResponse struct {
+	// TopicID contains the unique topic ID.
+	TopicID Uuid
+	// Partitions contains the topic partitions.
+	Partitions []ShareFetchPartitionResponse
+}
+
+// ShareFetchPartitionResponse contains the records fetched from a partition
+// and the offsets acquired by the member.
+type ShareFetchPartitionResponse struct {
+	// PartitionIndex contains the partition index.
+	PartitionIndex int32
+	// Err contains the fetch error code, or ErrNoError if the fetch
+	// succeeded.
+	Err KError
+	// ErrorMessage contains the fetch error message, or null if there was no
+	// error.
+	ErrorMessage *string
+	// AcknowledgeErr contains the acknowledge error code, or ErrNoError if
+	// the acknowledge succeeded.
+	AcknowledgeErr KError
+	// AcknowledgeErrorMessage contains the acknowledge error message, or
+	// null if there was no error.
+	AcknowledgeErrorMessage *string
+	// CurrentLeader contains the current leader of the partition.
+	CurrentLeader FetchResponseCurrentLeader
+	// RecordsSet contains the record data.
+	RecordsSet []*Records
+	// AcquiredRecords contains the ranges of offsets acquired by the member.
+	AcquiredRecords []AcquiredRecords
+}
+
+// AcquiredRecords is a range of offsets acquired by a member of a share group.
+type AcquiredRecords struct {
+	// FirstOffset contains the earliest offset in this batch of acquired
+	// records.
+	FirstOffset int64
+	// LastOffset contains the last offset of this batch of acquired records.
+	LastOffset int64
+	// DeliveryCount contains the delivery count of this batch of acquired
+	// records.
+	DeliveryCount int16
+}
+
+func (b *ShareFetchPartitionResponse) encode(pe packetEncoder) error {
+	pe.putInt32(b.PartitionIndex)
+	pe.putKError(b.Err)
+
+	if err := pe.putNullableString(b.ErrorMessage); err != nil {
+		return err
+	}
+
+	pe.putKError(b.AcknowledgeErr)
+
+	if err := pe.putNullableString(b.AcknowledgeErrorMessage); err != nil {
+		return err
+	}
+
+	encodeShareCurrentLeader(pe, b.CurrentLeader)
+
+	recordsBytes, err := encode(fetchRecordsSet(b.RecordsSet), nil)
+	if err != nil {
+		return err
+	}
+	if err := pe.putBytes(recordsBytes); err != nil {
+		return err
+	}
+
+	if err := pe.putArrayLength(len(b.AcquiredRecords)); err != nil {
+		return err
+	}
+	for _, acquired := range b.AcquiredRecords {
+		pe.putInt64(acquired.FirstOffset)
+		pe.putInt64(acquired.LastOffset)
+		pe.putInt16(acquired.DeliveryCount)
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (b *ShareFetchPartitionResponse) decode(pd packetDecoder) (err error) {
+	if b.PartitionIndex, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if b.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if b.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if b.AcknowledgeErr, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if b.AcknowledgeErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if b.CurrentLeader, err = decodeShareCurrentLeader(pd); err != nil {
+		return err
+	}
+
+	recordsBytes, err := pd.getBytes()
+	if err != nil {
+		return err
+	}
+	// record batches are not flexible-encoded, so decode the payload with a
+	// plain realDecoder rather than the flexible parent
+	recordsDecoder := &realDecoder{raw: recordsBytes}
+	for recordsDecoder.remaining() > 0 {
+		records := &Records{}
+		if err := records.decode(recordsDecoder); err != nil {
+			// the last batch may be truncated by the fetch size limits
+			if errors.Is(err, ErrInsufficientData) {
+				break
+			}
+			return err
+		}
+		b.RecordsSet = append(b.RecordsSet, records)
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	b.AcquiredRecords = make([]AcquiredRecords, n)
+	for i := range b.AcquiredRecords {
+		acquired := &b.AcquiredRecords[i]
+		if acquired.FirstOffset, err = pd.getInt64(); err != nil {
+			return err
+		}
+
+		if acquired.LastOffset, err = pd.getInt64(); err != nil {
+			return err
+		}
+
+		if acquired.DeliveryCount, err = pd.getInt16(); err != nil {
+			return err
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareFetchResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareFetchResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.Err)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		pe.putInt32(r.AcquisitionLockTimeoutMs)
+	}
+
+	if err := pe.putArrayLength(len(r.Responses)); err != nil {
+		return err
+	}
+	for _, topic := range r.Responses {
+		if err := pe.putUuid(topic.TopicID); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for i := range topic.Partitions {
+			if err := topic.Partitions[i].encode(pe); err != nil {
+				return err
+			}
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	if err := encodeNodeEndpoints(pe, r.NodeEndpoints); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareFetchResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		if r.AcquisitionLockTimeoutMs, err = pd.getInt32(); err != nil {
+			return err
+		}
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Responses = make([]ShareFetchTopicResponse, n)
+	for i := range r.Responses {
+		topic := &r.Responses[i]
+		if topic.TopicID, err = pd.getUuid(); err != nil {
+			return err
+		}
+
+		m, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]ShareFetchPartitionResponse, m)
+		for j := range topic.Partitions {
+			if err := topic.Partitions[j].decode(pd); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if r.NodeEndpoints, err = decodeNodeEndpoints(pd); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareFetchResponse) key() int16 {
+	return apiKeyShareFetch
+}
+
+func (r *ShareFetchResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ShareFetchResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *ShareFetchResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareFetchResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareFetchResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareFetchResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
+
+func (r *ShareFetchResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
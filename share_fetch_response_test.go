@@ -0,0 +1,142 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var (
+	shareFetchResponseV0 = []byte{
+		0, 0, 0, 0, // ThrottleTimeMs
+		0, 0, // ErrorCode
+		0,                                                     // ErrorMessage (null)
+		2,                                                     // Responses
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2,          // Partitions
+		0, 0, 0, 1, // PartitionIndex
+		0, 6, // ErrorCode
+		0,    // ErrorMessage (null)
+		0, 0, // AcknowledgeErrorCode
+		0,          // AcknowledgeErrorMessage (null)
+		0, 0, 0, 2, // CurrentLeader.LeaderId
+		0, 0, 0, 4, // CurrentLeader.LeaderEpoch
+		0,          // empty tagged fields
+		1,          // Records
+		1,          // AcquiredRecords
+		0,          // empty tagged fields
+		0,          // empty tagged fields
+		2,          // NodeEndpoints
+		0, 0, 0, 2, // NodeId
+		2, 'h', // Host
+		0, 0, 0x23, 0x84, // Port
+		0, // Rack (null)
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+
+	shareFetchResponseV1 = []byte{
+		0, 0, 0, 100, // ThrottleTimeMs
+		0, 0, // ErrorCode
+		0,                // ErrorMessage (null)
+		0, 0, 0x75, 0x30, // AcquisitionLockTimeoutMs
+		2,                                                     // Responses
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, // TopicID
+		2,          // Partitions
+		0, 0, 0, 0, // PartitionIndex
+		0, 0, // ErrorCode
+		0,      // ErrorMessage (null)
+		0, 121, // AcknowledgeErrorCode
+		2, 'x', // AcknowledgeErrorMessage
+		0, 0, 0, 1, // CurrentLeader.LeaderId
+		0, 0, 0, 3, // CurrentLeader.LeaderEpoch
+		0,                       // empty tagged fields
+		1,                       // Records
+		2,                       // AcquiredRecords
+		0, 0, 0, 0, 0, 0, 0, 10, // FirstOffset
+		0, 0, 0, 0, 0, 0, 0, 12, // LastOffset
+		0, 2, // DeliveryCount
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+		1, // NodeEndpoints
+		0, // empty tagged fields
+	}
+)
+
+func TestShareFetchResponse(t *testing.T) {
+	message := "x"
+	topicID := Uuid{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	response := &ShareFetchResponse{
+		Version: 0,
+		Responses: []ShareFetchTopicResponse{{
+			TopicID: topicID,
+			Partitions: []ShareFetchPartitionResponse{{
+				PartitionIndex:  1,
+				Err:             ErrNotLeaderForPartition,
+				CurrentLeader:   FetchResponseCurrentLeader{LeaderID: 2, LeaderEpoch: 4},
+				AcquiredRecords: []AcquiredRecords{},
+			}},
+		}},
+		NodeEndpoints: []NodeEndpoint{{NodeID: 2, Host: "h", Port: 9092}},
+	}
+	testResponse(t, "v0", response, shareFetchResponseV0)
+
+	response = &ShareFetchResponse{
+		Version:                  1,
+		ThrottleTime:             100 * time.Millisecond,
+		AcquisitionLockTimeoutMs: 30000,
+		Responses: []ShareFetchTopicResponse{{
+			TopicID: topicID,
+			Partitions: []ShareFetchPartitionResponse{{
+				PartitionIndex:          0,
+				AcknowledgeErr:          ErrInvalidRecordState,
+				AcknowledgeErrorMessage: &message,
+				CurrentLeader:           FetchResponseCurrentLeader{LeaderID: 1, LeaderEpoch: 3},
+				AcquiredRecords: []AcquiredRecords{
+					{FirstOffset: 10, LastOffset: 12, DeliveryCount: 2},
+				},
+			}},
+		}},
+		NodeEndpoints: []NodeEndpoint{},
+	}
+	testResponse(t, "v1", response, shareFetchResponseV1)
+}
+
+func TestShareFetchResponseRecords(t *testing.T) {
+	records := newDefaultRecords(&RecordBatch{
+		Version:     2,
+		FirstOffset: 10,
+		Records: []*Record{
+			{OffsetDelta: 0, Value: []byte("a")},
+			{OffsetDelta: 1, Value: []byte("b")},
+		},
+	})
+	response := &ShareFetchResponse{
+		Version: 1,
+		Responses: []ShareFetchTopicResponse{{
+			Partitions: []ShareFetchPartitionResponse{{
+				RecordsSet:      []*Records{&records},
+				AcquiredRecords: []AcquiredRecords{{FirstOffset: 10, LastOffset: 11, DeliveryCount: 1}},
+			}},
+		}},
+	}
+
+	encoded, err := encode(response, nil)
+	assert.NoError(t, err)
+
+	decoded := new(ShareFetchResponse)
+	assert.NoError(t, versionedDecode(encoded, decoded, 1, nil))
+
+	partition := decoded.Responses[0].Partitions[0]
+	assert.Len(t, partition.RecordsSet, 1)
+	batch := partition.RecordsSet[0].RecordBatch
+	assert.Equal(t, int64(10), batch.FirstOffset)
+	assert.Len(t, batch.Records, 2)
+	assert.Equal(t, []byte("b"), batch.Records[1].Value)
+	assert.Equal(t, response.Responses[0].Partitions[0].AcquiredRecords, partition.AcquiredRecords)
+}
@@ -0,0 +1,126 @@
+package sarama
+
+// ShareGroupHeartbeatRequest is sent by members of a share group (KIP-932) to
+// join the group, keep their membership alive and receive their assigned
+// partitions.
+type ShareGroupHeartbeatRequest struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// GroupID contains the group identifier.
+	GroupID string
+	// MemberID contains the member id generated by the consumer. The member
+	// id must be kept during the entire lifetime of the member.
+	MemberID string
+	// MemberEpoch contains the current member epoch; 0 to join the group; -1
+	// to leave the group.
+	MemberEpoch int32
+	// RackID contains the rack id of the member if provided, null otherwise.
+	RackID *string
+	// SubscribedTopicNames contains the list of subscribed topic names, null
+	// if it did not change since the last heartbeat.
+	SubscribedTopicNames []string
+}
+
+// NewShareGroupHeartbeatRequest returns a ShareGroupHeartbeatRequest using
+// the highest protocol version supported by the given Kafka version.
+func NewShareGroupHeartbeatRequest(version KafkaVersion) *ShareGroupHeartbeatRequest {
+	r := &ShareGroupHeartbeatRequest{}
+	if version.IsAtLeast(V4_1_0_0) {
+		// Version 1 is the first version that is not early access.
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *ShareGroupHeartbeatRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareGroupHeartbeatRequest) encode(pe packetEncoder) error {
+	if err := pe.putString(r.GroupID); err != nil {
+		return err
+	}
+
+	if err := pe.putString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.MemberEpoch)
+
+	if err := pe.putNullableString(r.RackID); err != nil {
+		return err
+	}
+
+	if r.SubscribedTopicNames == nil {
+		if err := pe.putArrayLength(-1); err != nil {
+			return err
+		}
+	} else if err := pe.putStringArray(r.SubscribedTopicNames); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.GroupID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if r.MemberEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.RackID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareGroupHeartbeatRequest) key() int16 {
+	return apiKeyShareGroupHeartbeat
+}
+
+func (r *ShareGroupHeartbeatRequest) version() int16 {
+	return r.Version
+}
+
+func (r *ShareGroupHeartbeatRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *ShareGroupHeartbeatRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareGroupHeartbeatRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareGroupHeartbeatRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareGroupHeartbeatRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	shareGroupHeartbeatRequestV0 = []byte{
+		2, 'g', // GroupID
+		2, 'm', // MemberID
+		0, 0, 0, 0, // MemberEpoch
+		2, 'r', // RackID
+		2, 2, 't', // SubscribedTopicNames
+		0, // empty tagged fields
+	}
+
+	shareGroupHeartbeatRequestV1 = []byte{
+		2, 'g', // GroupID
+		2, 'm', // MemberID
+		0, 0, 0, 3, // MemberEpoch
+		0, // RackID (null)
+		0, // SubscribedTopicNames (null)
+		0, // empty tagged fields
+	}
+)
+
+func TestShareGroupHeartbeatRequest(t *testing.T) {
+	rack := "r"
+
+	request := &ShareGroupHeartbeatRequest{
+		Version:              0,
+		GroupID:              "g",
+		MemberID:             "m",
+		MemberEpoch:          0,
+		RackID:               &rack,
+		SubscribedTopicNames: []string{"t"},
+	}
+	testRequest(t, "v0", request, shareGroupHeartbeatRequestV0)
+
+	request = &ShareGroupHeartbeatRequest{
+		Version:     1,
+		GroupID:     "g",
+		MemberID:    "m",
+		MemberEpoch: 3,
+	}
+	testRequest(t, "v1", request, shareGroupHeartbeatRequestV1)
+}
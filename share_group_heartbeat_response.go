@@ -0,0 +1,167 @@
+package sarama
+
+import "time"
+
+// ShareGroupHeartbeatResponse is returned by the group coordinator in response
+// to a ShareGroupHeartbeatRequest.
+type ShareGroupHeartbeatResponse struct {
+	// Version defines the protocol version to use for encode and decode
+	Version int16
+	// ThrottleTime contains the duration for which the request was throttled
+	// due to a quota violation, or zero if the request did not violate any
+	// quota.
+	ThrottleTime time.Duration
+	// Err contains the top-level error code, or ErrNoError if there was no
+	// error.
+	Err KError
+	// ErrorMessage contains the top-level error message, or null if there was
+	// no error.
+	ErrorMessage *string
+	// MemberID contains the member id, or null if not provided.
+	MemberID *string
+	// MemberEpoch contains the member epoch.
+	MemberEpoch int32
+	// HeartbeatIntervalMs contains the heartbeat interval in milliseconds.
+	HeartbeatIntervalMs int32
+	// Assignment contains the new assignment of the member, or null if it
+	// did not change since the last heartbeat.
+	Assignment *ShareGroupHeartbeatAssignment
+}
+
+// ShareGroupHeartbeatAssignment is the assignment sent to a member of a share
+// group by the group coordinator.
+type ShareGroupHeartbeatAssignment struct {
+	// TopicPartitions contains the partitions assigned to the member.
+	TopicPartitions []ConsumerGroupHeartbeatTopicPartitions
+}
+
+func (a *ShareGroupHeartbeatAssignment) encode(pe packetEncoder) error {
+	if err := encodeConsumerGroupHeartbeatTopicPartitions(pe, a.TopicPartitions); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (a *ShareGroupHeartbeatAssignment) decode(pd packetDecoder, version int16) (err error) {
+	if a.TopicPartitions, err = decodeConsumerGroupHeartbeatTopicPartitions(pd, version); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareGroupHeartbeatResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *ShareGroupHeartbeatResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.Err)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	if err := pe.putNullableString(r.MemberID); err != nil {
+		return err
+	}
+
+	pe.putInt32(r.MemberEpoch)
+	pe.putInt32(r.HeartbeatIntervalMs)
+
+	if r.Assignment == nil {
+		pe.putInt8(-1)
+	} else {
+		pe.putInt8(1)
+		if err := r.Assignment.encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *ShareGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.Err, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberID, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.MemberEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.HeartbeatIntervalMs, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	present, err := pd.getInt8()
+	if err != nil {
+		return err
+	}
+	if present >= 0 {
+		r.Assignment = new(ShareGroupHeartbeatAssignment)
+		if err := r.Assignment.decode(pd, version); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *ShareGroupHeartbeatResponse) key() int16 {
+	return apiKeyShareGroupHeartbeat
+}
+
+func (r *ShareGroupHeartbeatResponse) version() int16 {
+	return r.Version
+}
+
+func (r *ShareGroupHeartbeatResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *ShareGroupHeartbeatResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *ShareGroupHeartbeatResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *ShareGroupHeartbeatResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *ShareGroupHeartbeatResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V4_1_0_0
+	case 0:
+		return V4_0_0_0
+	default:
+		return V4_1_0_0
+	}
+}
+
+func (r *ShareGroupHeartbeatResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -1,6 +1,9 @@
 package sarama
 
-import "sync"
+import (
+	"context"
+	"sync"
+)
 
 var expectationsPool = sync.Pool{
 	New: func() any {
@@ -31,6 +34,12 @@ type SyncProducer interface {
 	// SendMessages will return an error.
 	SendMessages(msgs []*ProducerMessage) error
 
+	// Flush waits until every message handed to SendMessage or SendMessages
+	// before the call has either been acknowledged or failed, sending the
+	// buffered ones without waiting for Producer.Flush thresholds. It returns
+	// the context error if the context is done first.
+	Flush(ctx context.Context) error
+
 	// Close shuts down the producer; you must call this function before a producer
 	// object passes out of scope, as it may otherwise leak memory.
 	// You must call this before calling Close on the underlying client.
@@ -184,6 +193,10 @@ func (sp *syncProducer) handleErrors() {
 	}
 }
 
+func (sp *syncProducer) Flush(ctx context.Context) error {
+	return sp.producer.Flush(ctx)
+}
+
 func (sp *syncProducer) Close() error {
 	sp.producer.AsyncClose()
 	sp.wg.Wait()
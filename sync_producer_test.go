@@ -3,10 +3,12 @@
 package sarama
 
 import (
+	"context"
 	"errors"
 	"log"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestSyncProducer(t *testing.T) {
@@ -59,6 +61,56 @@ func TestSyncProducer(t *testing.T) {
 	seedBroker.Close()
 }
 
+func TestSyncProducerFlush(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	leader := NewMockBroker(t, 2)
+
+	metadataResponse := new(MetadataResponse)
+	metadataResponse.AddBroker(leader.Addr(), leader.BrokerID())
+	metadataResponse.AddTopicPartition("my_topic", 0, leader.BrokerID(), nil, nil, nil, ErrNoError)
+	seedBroker.Returns(metadataResponse)
+
+	prodSuccess := new(ProduceResponse)
+	prodSuccess.AddTopicPartition("my_topic", 0, ErrNoError)
+	leader.Returns(prodSuccess)
+
+	config := NewTestConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Flush.Messages = 10
+	config.Producer.Flush.Frequency = time.Hour
+	producer, err := NewSyncProducer([]string{seedBroker.Addr()}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make(chan error)
+	go func() {
+		_, _, err := producer.SendMessage(&ProducerMessage{Topic: "my_topic", Value: StringEncoder(TestMessage)})
+		sent <- err
+	}()
+
+	// the message would otherwise wait for the flush frequency
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for done := false; !done; {
+		if err := producer.Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-sent:
+			if err != nil {
+				t.Error(err)
+			}
+			done = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	safeClose(t, producer)
+	leader.Close()
+	seedBroker.Close()
+}
+
 func TestSyncProducerTransactional(t *testing.T) {
 	seedBroker := NewMockBroker(t, 1)
 	defer seedBroker.Close()
@@ -0,0 +1,375 @@
+package sarama
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	// telemetryMetricPrefix is prepended to the names of the metrics in
+	// Config.MetricRegistry to form the names of the pushed OTLP metrics,
+	// which is what the prefixes requested by a subscription are matched
+	// against.
+	telemetryMetricPrefix = "sarama."
+
+	// defaultTelemetryPushInterval is used when a subscription does not
+	// specify a push interval, matching the broker's default.
+	defaultTelemetryPushInterval = 5 * time.Minute
+
+	// telemetryRetryBackoff is how long to wait before asking for a new
+	// subscription after a telemetry request failed.
+	telemetryRetryBackoff = 30 * time.Second
+
+	// telemetryTerminateTimeout bounds how long closing the client waits for
+	// the terminating push.
+	telemetryTerminateTimeout = time.Second
+)
+
+// telemetryQuantiles are the quantiles of each histogram that are pushed.
+var telemetryQuantiles = []float64{0.5, 0.75, 0.95, 0.99}
+
+// telemetryReporter pushes the meters and histograms of the client's metric
+// registry to the cluster as requested by a telemetry subscription (KIP-714).
+type telemetryReporter struct {
+	client *client
+	conf   *Config
+
+	instanceID   Uuid
+	subscription *GetTelemetrySubscriptionsResponse
+
+	startTime time.Time
+	lastPush  time.Time
+	// lastCounts holds the meter counts of the last push, used to compute
+	// deltas when the subscription asks for delta temporality
+	lastCounts map[string]int64
+}
+
+func newTelemetryReporter(client *client) *telemetryReporter {
+	return &telemetryReporter{
+		client:    client,
+		conf:      client.conf,
+		startTime: time.Now(),
+	}
+}
+
+// run requests subscriptions and pushes metrics until closer is closed, at
+// which point a final terminating push is sent. It returns early if the
+// cluster does not support client telemetry.
+func (r *telemetryReporter) run(closer <-chan none) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-closer:
+			r.terminate()
+			return
+		}
+
+		wait, err := r.step()
+		if errors.Is(err, ErrUnsupportedVersion) {
+			DebugLogger.Println("Client telemetry is not supported by the cluster, disabling it")
+			return
+		}
+		if err != nil {
+			Logger.Println("Client telemetry:", err)
+		}
+		timer.Reset(wait)
+	}
+}
+
+// step fetches a subscription if there is none, otherwise pushes the
+// subscribed metrics, and returns how long to wait before the next step.
+func (r *telemetryReporter) step() (time.Duration, error) {
+	broker := r.broker()
+	if broker == nil {
+		return telemetryRetryBackoff, ErrOutOfBrokers
+	}
+	if !broker.supportsAPIKey(apiKeyGetTelemetrySubscriptions) || !broker.supportsAPIKey(apiKeyPushTelemetry) {
+		return 0, ErrUnsupportedVersion
+	}
+
+	if r.subscription == nil {
+		return r.subscribe(broker)
+	}
+	return r.push(broker, false)
+}
+
+func (r *telemetryReporter) broker() *Broker {
+	broker := r.client.LeastLoadedBroker()
+	if broker != nil {
+		_ = broker.Open(r.conf)
+	}
+	return broker
+}
+
+func (r *telemetryReporter) subscribe(broker *Broker) (time.Duration, error) {
+	response, err := broker.GetTelemetrySubscriptions(&GetTelemetrySubscriptionsRequest{
+		ClientInstanceID: r.instanceID,
+	})
+	if err != nil {
+		return telemetryRetryBackoff, err
+	}
+	if !errors.Is(response.ErrorCode, ErrNoError) {
+		return telemetryRetryBackoff, response.ErrorCode
+	}
+
+	r.instanceID = response.ClientInstanceID
+	r.lastPush = time.Now()
+	r.lastCounts = make(map[string]int64)
+
+	interval := response.PushInterval
+	if interval <= 0 {
+		interval = defaultTelemetryPushInterval
+	}
+	if len(response.RequestedMetrics) == 0 {
+		// nothing is subscribed, check again for a new subscription later
+		return interval, nil
+	}
+	r.subscription = response
+	r.subscription.PushInterval = interval
+
+	// jitter the first push so that clients started together spread out
+	return interval/2 + time.Duration(rand.Int63n(int64(interval))), nil
+}
+
+func (r *telemetryReporter) push(broker *Broker, terminating bool) (time.Duration, error) {
+	subscription := r.subscription
+	request := &PushTelemetryRequest{
+		ClientInstanceID: r.instanceID,
+		SubscriptionID:   subscription.SubscriptionID,
+		Terminating:      terminating,
+		CompressionType:  r.compressionType(),
+	}
+
+	now := time.Now()
+	data, err := compress(request.CompressionType, CompressionLevelDefault, r.collect(now))
+	if err != nil {
+		return subscription.PushInterval, err
+	}
+	r.lastPush = now
+	if subscription.TelemetryMaxBytes > 0 && len(data) > int(subscription.TelemetryMaxBytes) {
+		return subscription.PushInterval, ErrTelemetryTooLarge
+	}
+	request.Metrics = data
+
+	response, err := broker.PushTelemetry(request)
+	if err != nil {
+		return subscription.PushInterval, err
+	}
+
+	switch {
+	case errors.Is(response.ErrorCode, ErrNoError):
+		return subscription.PushInterval, nil
+	case errors.Is(response.ErrorCode, ErrUnknownSubscriptionID),
+		errors.Is(response.ErrorCode, ErrUnsupportedCompressionType):
+		// the subscription changed, fetch the new one straight away
+		r.subscription = nil
+		return 0, nil
+	default:
+		r.subscription = nil
+		return telemetryRetryBackoff, response.ErrorCode
+	}
+}
+
+// terminate sends a final push, letting the broker know that the client is
+// going away. It is best effort: the push is skipped when no broker is
+// connected, and abandoned after telemetryTerminateTimeout so that it cannot
+// hold up closing the client.
+func (r *telemetryReporter) terminate() {
+	if r.subscription == nil {
+		return
+	}
+	broker := r.client.LeastLoadedBroker()
+	if broker == nil {
+		return
+	}
+	if connected, _ := broker.Connected(); !connected {
+		return
+	}
+
+	done := make(chan error, 1)
+	go withRecover(func() {
+		_, err := r.push(broker, true)
+		done <- err
+	})
+
+	timer := time.NewTimer(telemetryTerminateTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			DebugLogger.Println("Client telemetry: terminating push failed:", err)
+		}
+	case <-timer.C:
+		DebugLogger.Println("Client telemetry: terminating push timed out")
+	}
+}
+
+// compressionType returns the first compression type accepted by the
+// subscription that the client supports, or CompressionNone.
+func (r *telemetryReporter) compressionType() CompressionCodec {
+	for _, codec := range r.subscription.AcceptedCompressionTypes {
+		switch codec {
+		case CompressionNone, CompressionGZIP, CompressionSnappy, CompressionLZ4, CompressionZSTD:
+			return codec
+		}
+	}
+	return CompressionNone
+}
+
+// requested reports whether the subscription asks for the metric name.
+func (r *telemetryReporter) requested(name string) bool {
+	for _, prefix := range r.subscription.RequestedMetrics {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// collect samples the subscribed metrics of the registry and encodes them as
+// an OTLP MetricsData message. Meters are pushed as monotonic sums of their
+// counts and histograms as summaries of their samples.
+func (r *telemetryReporter) collect(now time.Time) []byte {
+	temporality := uint64(otlpTemporalityCumulative)
+	start := r.startTime
+	if r.subscription.DeltaTemporality {
+		temporality = otlpTemporalityDelta
+		start = r.lastPush
+	}
+
+	encoded := make(map[string][]byte)
+	r.conf.MetricRegistry.Each(func(name string, metric any) {
+		otlpName := telemetryMetricPrefix + name
+		if !r.requested(otlpName) {
+			return
+		}
+		switch m := metric.(type) {
+		case metrics.Meter:
+			count := m.Snapshot().Count()
+			value := count
+			if r.subscription.DeltaTemporality {
+				value -= r.lastCounts[name]
+			}
+			r.lastCounts[name] = count
+			encoded[otlpName] = encodeOTLPSum(otlpName, start, now, value, temporality)
+		case metrics.Histogram:
+			encoded[otlpName] = encodeOTLPSummary(otlpName, r.startTime, now, m.Snapshot())
+		}
+	})
+
+	names := make([]string, 0, len(encoded))
+	for name := range encoded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var scope []byte
+	scope = appendProtoString(scope, 1, defaultClientSoftwareName)
+	scope = appendProtoString(scope, 2, version())
+
+	var scopeMetrics []byte
+	scopeMetrics = appendProtoBytes(scopeMetrics, 1, scope)
+	for _, name := range names {
+		scopeMetrics = appendProtoBytes(scopeMetrics, 2, encoded[name])
+	}
+
+	var resource []byte
+	resource = appendProtoBytes(resource, 1, encodeOTLPStringAttribute("client_id", r.conf.ClientID))
+
+	var resourceMetrics []byte
+	resourceMetrics = appendProtoBytes(resourceMetrics, 1, resource)
+	resourceMetrics = appendProtoBytes(resourceMetrics, 2, scopeMetrics)
+
+	return appendProtoBytes(nil, 1, resourceMetrics)
+}
+
+// AggregationTemporality values of the OTLP metrics data model.
+const (
+	otlpTemporalityDelta      = 1
+	otlpTemporalityCumulative = 2
+)
+
+func encodeOTLPStringAttribute(key, value string) []byte {
+	var keyValue []byte
+	keyValue = appendProtoString(keyValue, 1, key)
+	keyValue = appendProtoBytes(keyValue, 2, appendProtoString(nil, 1, value))
+	return keyValue
+}
+
+func encodeOTLPSum(name string, start, now time.Time, value int64, temporality uint64) []byte {
+	var point []byte
+	point = appendProtoFixed64(point, 2, uint64(start.UnixNano()))
+	point = appendProtoFixed64(point, 3, uint64(now.UnixNano()))
+	point = appendProtoFixed64(point, 6, uint64(value))
+
+	var sum []byte
+	sum = appendProtoBytes(sum, 1, point)
+	sum = appendProtoVarint(sum, 2, temporality)
+	sum = appendProtoVarint(sum, 3, 1) // is_monotonic
+
+	var metric []byte
+	metric = appendProtoString(metric, 1, name)
+	return appendProtoBytes(metric, 7, sum)
+}
+
+func encodeOTLPSummary(name string, start, now time.Time, histogram metrics.Histogram) []byte {
+	var point []byte
+	point = appendProtoFixed64(point, 2, uint64(start.UnixNano()))
+	point = appendProtoFixed64(point, 3, uint64(now.UnixNano()))
+	point = appendProtoFixed64(point, 4, uint64(histogram.Count()))
+	point = appendProtoFixed64(point, 5, math.Float64bits(float64(histogram.Sum())))
+	for i, value := range histogram.Percentiles(telemetryQuantiles) {
+		var quantile []byte
+		quantile = appendProtoFixed64(quantile, 1, math.Float64bits(telemetryQuantiles[i]))
+		quantile = appendProtoFixed64(quantile, 2, math.Float64bits(value))
+		point = appendProtoBytes(point, 6, quantile)
+	}
+
+	var metric []byte
+	metric = appendProtoString(metric, 1, name)
+	return appendProtoBytes(metric, 11, appendProtoBytes(nil, 1, point))
+}
+
+// Minimal protobuf wire format encoding, enough to build OTLP messages
+// without depending on a protobuf runtime.
+const (
+	protoWireVarint = 0
+	protoWireI64    = 1
+	protoWireLen    = 2
+)
+
+func appendProtoTag(b []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, protoWireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendProtoFixed64(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, protoWireI64)
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+func appendProtoBytes(b []byte, field int, v []byte) []byte {
+	b = appendProtoTag(b, field, protoWireLen)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendProtoString(b []byte, field int, v string) []byte {
+	b = appendProtoTag(b, field, protoWireLen)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
@@ -0,0 +1,207 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type protoField struct {
+	num    int
+	varint uint64
+	data   []byte
+}
+
+// parseProto splits a protobuf message into its fields, fixed64 values are
+// returned as their 8 raw bytes.
+func parseProto(t *testing.T, b []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		require.Positive(t, n)
+		b = b[n:]
+		field := protoField{num: int(tag >> 3)}
+		switch tag & 7 {
+		case protoWireVarint:
+			field.varint, n = binary.Uvarint(b)
+			require.Positive(t, n)
+			b = b[n:]
+		case protoWireI64:
+			field.data, b = b[:8], b[8:]
+		case protoWireLen:
+			length, n := binary.Uvarint(b)
+			require.Positive(t, n)
+			field.data, b = b[n:n+int(length)], b[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func protoSubMessage(t *testing.T, b []byte, num int) []byte {
+	t.Helper()
+	for _, field := range parseProto(t, b) {
+		if field.num == num {
+			return field.data
+		}
+	}
+	t.Fatalf("field %d not found", num)
+	return nil
+}
+
+// otlpMetrics returns the encoded metrics of an OTLP MetricsData by name.
+func otlpMetrics(t *testing.T, payload []byte) map[string][]byte {
+	t.Helper()
+	resourceMetrics := protoSubMessage(t, payload, 1)
+	scopeMetrics := protoSubMessage(t, resourceMetrics, 2)
+	result := make(map[string][]byte)
+	for _, field := range parseProto(t, scopeMetrics) {
+		if field.num == 2 {
+			result[string(protoSubMessage(t, field.data, 1))] = field.data
+		}
+	}
+	return result
+}
+
+func otlpSumValue(t *testing.T, metric []byte) int64 {
+	t.Helper()
+	point := protoSubMessage(t, protoSubMessage(t, metric, 7), 1)
+	return int64(binary.LittleEndian.Uint64(protoSubMessage(t, point, 6)))
+}
+
+func TestTelemetryReporterCollect(t *testing.T) {
+	conf := NewTestConfig()
+	meter := metrics.GetOrRegisterMeter("incoming-byte-rate", conf.MetricRegistry)
+	histogram := getOrRegisterHistogram("request-latency-in-ms", conf.MetricRegistry)
+	metrics.GetOrRegisterMeter("outgoing-byte-rate", conf.MetricRegistry).Mark(1)
+
+	reporter := newTelemetryReporter(&client{conf: conf})
+	reporter.subscription = &GetTelemetrySubscriptionsResponse{
+		RequestedMetrics: []string{"sarama.incoming", "sarama.request-latency"},
+		DeltaTemporality: true,
+	}
+	reporter.lastCounts = make(map[string]int64)
+
+	meter.Mark(10)
+	histogram.Update(4)
+	histogram.Update(6)
+	collected := otlpMetrics(t, reporter.collect(time.Now()))
+	require.Len(t, collected, 2, "only the requested metrics should be pushed")
+	require.Equal(t, int64(10), otlpSumValue(t, collected["sarama.incoming-byte-rate"]))
+
+	summary := protoSubMessage(t, protoSubMessage(t, collected["sarama.request-latency-in-ms"], 11), 1)
+	require.Equal(t, uint64(2), binary.LittleEndian.Uint64(protoSubMessage(t, summary, 4)))
+
+	meter.Mark(5)
+	collected = otlpMetrics(t, reporter.collect(time.Now()))
+	require.Equal(t, int64(5), otlpSumValue(t, collected["sarama.incoming-byte-rate"]), "delta since the previous push")
+
+	reporter.subscription.DeltaTemporality = false
+	collected = otlpMetrics(t, reporter.collect(time.Now()))
+	require.Equal(t, int64(15), otlpSumValue(t, collected["sarama.incoming-byte-rate"]), "cumulative count")
+}
+
+func TestClientTelemetryPush(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"ApiVersionsRequest": NewMockApiVersionsResponse(t).SetApiKeys([]ApiVersionsResponseKey{
+			{ApiKey: apiKeyMetadata, MinVersion: 0, MaxVersion: 12},
+			{ApiKey: apiKeyGetTelemetrySubscriptions, MinVersion: 0, MaxVersion: 0},
+			{ApiKey: apiKeyPushTelemetry, MinVersion: 0, MaxVersion: 0},
+		}),
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+		"GetTelemetrySubscriptionsRequest": NewMockGetTelemetrySubscriptionsResponse(t).
+			SetSubscription(7, 20*time.Millisecond, "").
+			SetAcceptedCompressionTypes(CompressionGZIP),
+		"PushTelemetryRequest": NewMockPushTelemetryResponse(t),
+	})
+
+	config := NewTestConfig()
+	config.ApiVersionsRequest = true
+	config.Version = V3_7_0_0
+	client, err := NewClient([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+
+	pushes := func() []*PushTelemetryRequest {
+		var requests []*PushTelemetryRequest
+		for _, rr := range seedBroker.History() {
+			if request, ok := rr.Request.(*PushTelemetryRequest); ok {
+				requests = append(requests, request)
+			}
+		}
+		return requests
+	}
+	require.Eventually(t, func() bool { return len(pushes()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, client.Close())
+
+	requests := pushes()
+	first, last := requests[0], requests[len(requests)-1]
+	require.Equal(t, Uuid{1}, first.ClientInstanceID)
+	require.Equal(t, int32(7), first.SubscriptionID)
+	require.Equal(t, CompressionGZIP, first.CompressionType)
+	require.False(t, first.Terminating)
+	require.True(t, last.Terminating, "closing the client should send a terminating push")
+}
+
+func TestClientTelemetryUnsupported(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"ApiVersionsRequest": NewMockApiVersionsResponse(t).SetApiKeys([]ApiVersionsResponseKey{
+			{ApiKey: apiKeyMetadata, MinVersion: 0, MaxVersion: 12},
+		}),
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+	})
+
+	config := NewTestConfig()
+	config.ApiVersionsRequest = true
+	config.Version = V3_7_0_0
+	client, err := NewClient([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	for _, rr := range seedBroker.History() {
+		require.NotEqual(t, apiKeyGetTelemetrySubscriptions, rr.Request.key())
+	}
+}
+
+func TestClientTelemetryOptOut(t *testing.T) {
+	seedBroker := NewMockBroker(t, 1)
+	defer seedBroker.Close()
+
+	seedBroker.SetHandlerByMap(map[string]MockResponse{
+		"ApiVersionsRequest": NewMockApiVersionsResponse(t).SetApiKeys([]ApiVersionsResponseKey{
+			{ApiKey: apiKeyMetadata, MinVersion: 0, MaxVersion: 12},
+			{ApiKey: apiKeyGetTelemetrySubscriptions, MinVersion: 0, MaxVersion: 0},
+			{ApiKey: apiKeyPushTelemetry, MinVersion: 0, MaxVersion: 0},
+		}),
+		"MetadataRequest": NewMockMetadataResponse(t).
+			SetBroker(seedBroker.Addr(), seedBroker.BrokerID()),
+	})
+
+	config := NewTestConfig()
+	config.ApiVersionsRequest = true
+	config.Version = V3_7_0_0
+	require.True(t, config.Telemetry.Enable, "telemetry is enabled by default")
+	config.Telemetry.Enable = false
+	client, err := NewClient([]string{seedBroker.Addr()}, config)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	for _, rr := range seedBroker.History() {
+		require.NotEqual(t, apiKeyGetTelemetrySubscriptions, rr.Request.key())
+	}
+}
@@ -103,7 +103,7 @@ var (
 	partitioner = flag.String(
 		"partitioner",
 		"roundrobin",
-		"The partitioning scheme to use (hash, manual, random, roundrobin).",
+		"The partitioning scheme to use (hash, manual, random, roundrobin, sticky).",
 	)
 	compression = flag.String(
 		"compression",
@@ -186,6 +186,8 @@ func parsePartitioner(scheme string, partition int) sarama.PartitionerConstructo
 		return sarama.NewRandomPartitioner
 	case "roundrobin":
 		return sarama.NewRoundRobinPartitioner
+	case "sticky":
+		return sarama.NewStickyPartitioner
 	default:
 		printUsageErrorAndExit(fmt.Sprintf("Unknown -partitioning: %s", scheme))
 	}
@@ -105,6 +105,20 @@ type transactionManager struct {
 	// Consumer group metadata per group whose offsets are added to the
 	// transaction, keyed by group ID.
 	groupMetadataInCurrentTxn map[string]*ConsumerGroupMetadata
+
+	// Batches sent to each partition whose outcome is not known yet, keyed
+	// like sequenceNumbers and in the order of their sequence numbers.
+	// inFlightCond, bound to mutex, is signalled when one of them is sent
+	// again or completes.
+	inFlightBatches map[string][]*inFlightBatch
+	inFlightCond    *sync.Cond
+}
+
+// inFlightBatch is a batch of an idempotent producer sent to a partition.
+type inFlightBatch struct {
+	pSet *partitionSet
+	// retrying is set while the batch waits to be sent again
+	retrying bool
 }
 
 const (
@@ -250,6 +264,146 @@ func (t *transactionManager) bumpEpoch() {
 	}
 }
 
+// batchSent records a batch handed over to a broker. A batch sent for the
+// first time goes after the batches in flight for its partition, while a
+// retried one keeps its place. It reports whether the batch was retried.
+func (t *transactionManager) batchSent(topic string, partition int32, pSet *partitionSet) bool {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, batch := range t.inFlightBatches[key] {
+		if batch.pSet == pSet {
+			retrying := batch.retrying
+			batch.retrying = false
+			t.inFlightCond.Broadcast()
+			return retrying
+		}
+	}
+	t.inFlightBatches[key] = append(t.inFlightBatches[key], &inFlightBatch{pSet: pSet})
+	return false
+}
+
+// batchRetrying marks an in-flight batch as waiting to be sent again.
+func (t *transactionManager) batchRetrying(topic string, partition int32, pSet *partitionSet) {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, batch := range t.inFlightBatches[key] {
+		if batch.pSet == pSet {
+			batch.retrying = true
+			return
+		}
+	}
+}
+
+// batchCompleted stops tracking a batch that succeeded or failed for good.
+func (t *transactionManager) batchCompleted(topic string, partition int32, pSet *partitionSet) {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	batches := t.inFlightBatches[key]
+	for i, batch := range batches {
+		if batch.pSet == pSet {
+			batches = append(batches[:i], batches[i+1:]...)
+			break
+		}
+	}
+	if len(batches) == 0 {
+		delete(t.inFlightBatches, key)
+	} else {
+		t.inFlightBatches[key] = batches
+	}
+	t.inFlightCond.Broadcast()
+}
+
+// isOldestInFlight reports whether no batch was sent to the partition before
+// the given one and is still in flight.
+func (t *transactionManager) isOldestInFlight(topic string, partition int32, pSet *partitionSet) bool {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	batches := t.inFlightBatches[key]
+	return len(batches) == 0 || batches[0].pSet == pSet
+}
+
+// hasRetryingBatch reports whether a batch of the partition waits to be sent
+// again, in which case no newer batch may be sent before it.
+func (t *transactionManager) hasRetryingBatch(topic string, partition int32) bool {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, batch := range t.inFlightBatches[key] {
+		if batch.retrying {
+			return true
+		}
+	}
+	return false
+}
+
+// awaitRetryTurn blocks until the batches sent to the partition before the
+// given one have been sent again, so that retries reach the broker in the
+// order of their sequence numbers.
+func (t *transactionManager) awaitRetryTurn(topic string, partition int32, pSet *partitionSet) {
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for {
+		turn := true
+		for _, batch := range t.inFlightBatches[key] {
+			if batch.pSet == pSet {
+				break
+			}
+			if batch.retrying {
+				turn = false
+				break
+			}
+		}
+		if turn {
+			return
+		}
+		t.inFlightCond.Wait()
+	}
+}
+
+// resequenceBatch gives the next sequence numbers of the current epoch to a
+// batch produced with a previous one, whose sequence numbers the broker will
+// no longer accept once the epoch was bumped, and moves it after the batches
+// in flight for its partition. It reports whether the batch was re-sequenced.
+func (t *transactionManager) resequenceBatch(topic string, partition int32, pSet *partitionSet) bool {
+	rb := pSet.recordsToSend.RecordBatch
+	if rb == nil {
+		return false
+	}
+
+	key := fmt.Sprintf("%s-%d", topic, partition)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if rb.ProducerID == t.producerID && rb.ProducerEpoch == t.producerEpoch {
+		return false
+	}
+
+	sequence := t.sequenceNumbers[key]
+	t.sequenceNumbers[key] = sequence + int32(len(pSet.msgs))
+	rb.ProducerID = t.producerID
+	rb.ProducerEpoch = t.producerEpoch
+	rb.FirstSequence = sequence
+	for i, msg := range pSet.msgs {
+		msg.sequenceNumber = sequence + int32(i)
+		msg.producerEpoch = t.producerEpoch
+	}
+
+	batches := t.inFlightBatches[key]
+	for i, batch := range batches {
+		if batch.pSet == pSet {
+			batches = append(append(batches[:i], batches[i+1:]...), batch)
+			break
+		}
+	}
+	t.inFlightBatches[key] = batches
+	t.inFlightCond.Broadcast()
+	return true
+}
+
 func (t *transactionManager) getProducerID() (int64, int16) {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -938,6 +1092,8 @@ func newTransactionManager(conf *Config, client Client) (*transactionManager, er
 		txnmgr.transactionTimeout = conf.Producer.Transaction.Timeout
 		txnmgr.sequenceNumbers = make(map[string]int32)
 		txnmgr.mutex = sync.Mutex{}
+		txnmgr.inFlightBatches = make(map[string][]*inFlightBatch)
+		txnmgr.inFlightCond = sync.NewCond(&txnmgr.mutex)
 
 		var err error
 		txnmgr.producerID, txnmgr.producerEpoch, err = txnmgr.initProducerId()
@@ -4,7 +4,9 @@ package sarama
 
 import (
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -973,3 +975,71 @@ func TestPublishPartitionToTxn(t *testing.T) {
 		}()
 	}
 }
+
+func TestTxnmgrInFlightBatches(t *testing.T) {
+	txnmgr := &transactionManager{
+		producerID:      1000,
+		producerEpoch:   1,
+		sequenceNumbers: map[string]int32{"test-topic-0": 6},
+		inFlightBatches: make(map[string][]*inFlightBatch),
+	}
+	txnmgr.inFlightCond = sync.NewCond(&txnmgr.mutex)
+
+	newBatch := func(epoch int16, firstSequence int32, count int) *partitionSet {
+		pSet := &partitionSet{recordsToSend: newDefaultRecords(&RecordBatch{
+			ProducerID:    1000,
+			ProducerEpoch: epoch,
+			FirstSequence: firstSequence,
+		})}
+		for i := range count {
+			pSet.msgs = append(pSet.msgs, &ProducerMessage{sequenceNumber: firstSequence + int32(i), producerEpoch: epoch})
+		}
+		return pSet
+	}
+	first, second, third := newBatch(1, 0, 2), newBatch(1, 2, 2), newBatch(1, 4, 2)
+
+	for _, pSet := range []*partitionSet{first, second, third} {
+		require.False(t, txnmgr.batchSent("test-topic", 0, pSet))
+	}
+	require.True(t, txnmgr.isOldestInFlight("test-topic", 0, first))
+	require.False(t, txnmgr.isOldestInFlight("test-topic", 0, second))
+	require.False(t, txnmgr.hasRetryingBatch("test-topic", 0))
+
+	txnmgr.batchRetrying("test-topic", 0, first)
+	txnmgr.batchRetrying("test-topic", 0, second)
+	require.True(t, txnmgr.hasRetryingBatch("test-topic", 0))
+
+	turn := make(chan struct{})
+	go func() {
+		txnmgr.awaitRetryTurn("test-topic", 0, second)
+		close(turn)
+	}()
+	txnmgr.awaitRetryTurn("test-topic", 0, first)
+	select {
+	case <-turn:
+		t.Fatal("the second batch must not be retried before the first one is sent")
+	case <-time.After(10 * time.Millisecond):
+	}
+	require.True(t, txnmgr.batchSent("test-topic", 0, first))
+	<-turn
+	require.True(t, txnmgr.batchSent("test-topic", 0, second))
+	require.False(t, txnmgr.hasRetryingBatch("test-topic", 0))
+
+	txnmgr.batchCompleted("test-topic", 0, first)
+	require.True(t, txnmgr.isOldestInFlight("test-topic", 0, second))
+
+	require.False(t, txnmgr.resequenceBatch("test-topic", 0, second), "the batch has the current epoch")
+	txnmgr.bumpEpoch()
+	require.True(t, txnmgr.resequenceBatch("test-topic", 0, second))
+	rb := second.recordsToSend.RecordBatch
+	assert.Equal(t, int16(2), rb.ProducerEpoch)
+	assert.Equal(t, int32(0), rb.FirstSequence)
+	assert.Equal(t, int32(1), second.msgs[1].sequenceNumber)
+	assert.Equal(t, int16(2), second.msgs[1].producerEpoch)
+	assert.Equal(t, int32(2), txnmgr.sequenceNumbers["test-topic-0"])
+	require.True(t, txnmgr.isOldestInFlight("test-topic", 0, third), "a re-sequenced batch goes after the batches in flight")
+
+	txnmgr.batchCompleted("test-topic", 0, second)
+	txnmgr.batchCompleted("test-topic", 0, third)
+	require.Empty(t, txnmgr.inFlightBatches)
+}
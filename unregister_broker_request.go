@@ -0,0 +1,59 @@
+package sarama
+
+// UnregisterBrokerRequest is sent to remove the registration of a broker
+// from a KRaft cluster.
+type UnregisterBrokerRequest struct {
+	Version int16
+
+	// BrokerID is the broker ID to unregister
+	BrokerID int32
+}
+
+func (r *UnregisterBrokerRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *UnregisterBrokerRequest) encode(pe packetEncoder) error {
+	pe.putInt32(r.BrokerID)
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *UnregisterBrokerRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.BrokerID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *UnregisterBrokerRequest) key() int16 {
+	return apiKeyUnregisterBroker
+}
+
+func (r *UnregisterBrokerRequest) version() int16 {
+	return r.Version
+}
+
+func (r *UnregisterBrokerRequest) headerVersion() int16 {
+	return 2
+}
+
+func (r *UnregisterBrokerRequest) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *UnregisterBrokerRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *UnregisterBrokerRequest) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *UnregisterBrokerRequest) requiredVersion() KafkaVersion {
+	return V3_1_0_0
+}
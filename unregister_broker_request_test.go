@@ -0,0 +1,14 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var unregisterBrokerRequestV0 = []byte{
+	0, 0, 0, 3, // BrokerID
+	0, // empty tagged fields
+}
+
+func TestUnregisterBrokerRequest(t *testing.T) {
+	testRequest(t, "v0", &UnregisterBrokerRequest{BrokerID: 3}, unregisterBrokerRequestV0)
+}
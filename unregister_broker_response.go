@@ -0,0 +1,83 @@
+package sarama
+
+import "time"
+
+type UnregisterBrokerResponse struct {
+	Version int16
+
+	ThrottleTime time.Duration
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+
+	// ErrorMessage is the top-level error message, or null if there was no
+	// error
+	ErrorMessage *string
+}
+
+func (r *UnregisterBrokerResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (r *UnregisterBrokerResponse) encode(pe packetEncoder) error {
+	pe.putDurationMs(r.ThrottleTime)
+	pe.putKError(r.ErrorCode)
+
+	if err := pe.putNullableString(r.ErrorMessage); err != nil {
+		return err
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *UnregisterBrokerResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	if r.ThrottleTime, err = pd.getDurationMs(); err != nil {
+		return err
+	}
+
+	if r.ErrorCode, err = pd.getKError(); err != nil {
+		return err
+	}
+
+	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *UnregisterBrokerResponse) key() int16 {
+	return apiKeyUnregisterBroker
+}
+
+func (r *UnregisterBrokerResponse) version() int16 {
+	return r.Version
+}
+
+func (r *UnregisterBrokerResponse) headerVersion() int16 {
+	return 1
+}
+
+func (r *UnregisterBrokerResponse) isValidVersion() bool {
+	return r.Version == 0
+}
+
+func (r *UnregisterBrokerResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *UnregisterBrokerResponse) isFlexibleVersion(version int16) bool {
+	return version >= 0
+}
+
+func (r *UnregisterBrokerResponse) requiredVersion() KafkaVersion {
+	return V3_1_0_0
+}
+
+func (r *UnregisterBrokerResponse) throttleTime() time.Duration {
+	return r.ThrottleTime
+}
@@ -0,0 +1,25 @@
+//go:build !functional
+
+package sarama
+
+import (
+	"testing"
+	"time"
+)
+
+var unregisterBrokerResponseV0 = []byte{
+	0, 0, 0, 100, // ThrottleTimeMs
+	0, 102, // ErrorCode (BROKER_ID_NOT_REGISTERED)
+	4, 'b', 'a', 'd', // ErrorMessage
+	0, // empty tagged fields
+}
+
+func TestUnregisterBrokerResponse(t *testing.T) {
+	message := "bad"
+	response := &UnregisterBrokerResponse{
+		ThrottleTime: 100 * time.Millisecond,
+		ErrorCode:    ErrBrokerIDNotRegistered,
+		ErrorMessage: &message,
+	}
+	testResponse(t, "v0", response, unregisterBrokerResponseV0)
+}
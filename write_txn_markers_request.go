@@ -0,0 +1,198 @@
+package sarama
+
+// WriteTxnMarkersRequest is sent by the transaction coordinator to the
+// partition leaders to write the COMMIT or ABORT markers of a transaction. An
+// admin client may send it directly to abort a hanging transaction.
+type WriteTxnMarkersRequest struct {
+	Version int16
+
+	// Markers contains the transaction markers to be written
+	Markers []WritableTxnMarker
+}
+
+// WritableTxnMarker is a transaction marker to write to a set of partitions.
+type WritableTxnMarker struct {
+	// ProducerID is the current producer ID
+	ProducerID int64
+
+	// ProducerEpoch is the current epoch associated with the producer ID
+	ProducerEpoch int16
+
+	// TransactionResult is the result of the transaction to write to the
+	// partitions (false = ABORT, true = COMMIT)
+	TransactionResult bool
+
+	// Topics contains each topic that we want to write transaction marker(s)
+	// for
+	Topics []WritableTxnMarkerTopic
+
+	// CoordinatorEpoch is the epoch associated with the transaction state
+	// partition hosting this transaction's metadata
+	CoordinatorEpoch int32
+}
+
+type WritableTxnMarkerTopic struct {
+	// Name is the topic name
+	Name string
+
+	// PartitionIndexes contains the indexes of the partitions to write
+	// transaction markers for
+	PartitionIndexes []int32
+}
+
+// NewWriteTxnMarkersRequest returns a WriteTxnMarkersRequest using the highest
+// protocol version supported by the given Kafka version.
+func NewWriteTxnMarkersRequest(version KafkaVersion) *WriteTxnMarkersRequest {
+	r := &WriteTxnMarkersRequest{}
+	if version.IsAtLeast(V2_8_0_0) {
+		r.Version = 1
+	}
+	return r
+}
+
+func (r *WriteTxnMarkersRequest) setVersion(v int16) {
+	r.Version = v
+}
+
+func (m *WritableTxnMarker) encode(pe packetEncoder) error {
+	pe.putInt64(m.ProducerID)
+	pe.putInt16(m.ProducerEpoch)
+	pe.putBool(m.TransactionResult)
+
+	if err := pe.putArrayLength(len(m.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range m.Topics {
+		if err := pe.putString(topic.Name); err != nil {
+			return err
+		}
+
+		if err := pe.putInt32Array(topic.PartitionIndexes); err != nil {
+			return err
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putInt32(m.CoordinatorEpoch)
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (m *WritableTxnMarker) decode(pd packetDecoder) (err error) {
+	if m.ProducerID, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	if m.ProducerEpoch, err = pd.getInt16(); err != nil {
+		return err
+	}
+
+	if m.TransactionResult, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	m.Topics = make([]WritableTxnMarkerTopic, n)
+	for i := range m.Topics {
+		topic := &m.Topics[i]
+		if topic.Name, err = pd.getString(); err != nil {
+			return err
+		}
+
+		if topic.PartitionIndexes, err = pd.getInt32Array(); err != nil {
+			return err
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if m.CoordinatorEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *WriteTxnMarkersRequest) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(r.Markers)); err != nil {
+		return err
+	}
+	for i := range r.Markers {
+		if err := r.Markers[i].encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *WriteTxnMarkersRequest) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Markers = make([]WritableTxnMarker, n)
+	for i := range r.Markers {
+		if err := r.Markers[i].decode(pd); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *WriteTxnMarkersRequest) key() int16 {
+	return apiKeyWriteTxnMarkers
+}
+
+func (r *WriteTxnMarkersRequest) version() int16 {
+	return r.Version
+}
+
+func (r *WriteTxnMarkersRequest) headerVersion() int16 {
+	if r.Version >= 1 {
+		return 2
+	}
+	return 1
+}
+
+func (r *WriteTxnMarkersRequest) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *WriteTxnMarkersRequest) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *WriteTxnMarkersRequest) isFlexibleVersion(version int16) bool {
+	return version >= 1
+}
+
+func (r *WriteTxnMarkersRequest) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V2_8_0_0
+	default:
+		return V0_11_0_0
+	}
+}
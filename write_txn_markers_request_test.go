@@ -0,0 +1,51 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	writeTxnMarkersRequestV0 = []byte{
+		0, 0, 0, 1, // Markers
+		0, 0, 0, 0, 0, 0, 0, 42, // ProducerID
+		0, 3, // ProducerEpoch
+		0,          // TransactionResult (abort)
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Name
+		0, 0, 0, 1, // PartitionIndexes
+		0, 0, 0, 2, // partition 2
+		0, 0, 0, 7, // CoordinatorEpoch
+	}
+
+	writeTxnMarkersRequestV1 = []byte{
+		2,                       // Markers
+		0, 0, 0, 0, 0, 0, 0, 42, // ProducerID
+		0, 3, // ProducerEpoch
+		1,                          // TransactionResult (commit)
+		2,                          // Topics
+		6, 't', 'o', 'p', 'i', 'c', // Name
+		2,          // PartitionIndexes
+		0, 0, 0, 2, // partition 2
+		0,          // empty tagged fields
+		0, 0, 0, 7, // CoordinatorEpoch
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestWriteTxnMarkersRequest(t *testing.T) {
+	request := &WriteTxnMarkersRequest{
+		Version: 0,
+		Markers: []WritableTxnMarker{{
+			ProducerID:       42,
+			ProducerEpoch:    3,
+			Topics:           []WritableTxnMarkerTopic{{Name: "topic", PartitionIndexes: []int32{2}}},
+			CoordinatorEpoch: 7,
+		}},
+	}
+	testRequest(t, "v0", request, writeTxnMarkersRequestV0)
+
+	request.Version = 1
+	request.Markers[0].TransactionResult = true
+	testRequest(t, "v1", request, writeTxnMarkersRequestV1)
+}
@@ -0,0 +1,190 @@
+package sarama
+
+type WriteTxnMarkersResponse struct {
+	Version int16
+
+	// Markers contains the results for writing markers
+	Markers []WritableTxnMarkerResult
+}
+
+// WritableTxnMarkerResult contains the per-partition results of writing the
+// markers of a producer.
+type WritableTxnMarkerResult struct {
+	// ProducerID is the current producer ID in use by the transactional ID
+	ProducerID int64
+
+	// Topics contains the results by topic
+	Topics []WritableTxnMarkerTopicResult
+}
+
+type WritableTxnMarkerTopicResult struct {
+	// Name is the topic name
+	Name string
+
+	// Partitions contains the results by partition
+	Partitions []WritableTxnMarkerPartitionResult
+}
+
+type WritableTxnMarkerPartitionResult struct {
+	// PartitionIndex is the partition index
+	PartitionIndex int32
+
+	// ErrorCode is the error code, or 0 if there was no error
+	ErrorCode KError
+}
+
+func (r *WriteTxnMarkersResponse) setVersion(v int16) {
+	r.Version = v
+}
+
+func (m *WritableTxnMarkerResult) encode(pe packetEncoder) error {
+	pe.putInt64(m.ProducerID)
+
+	if err := pe.putArrayLength(len(m.Topics)); err != nil {
+		return err
+	}
+	for _, topic := range m.Topics {
+		if err := pe.putString(topic.Name); err != nil {
+			return err
+		}
+
+		if err := pe.putArrayLength(len(topic.Partitions)); err != nil {
+			return err
+		}
+		for _, partition := range topic.Partitions {
+			pe.putInt32(partition.PartitionIndex)
+			pe.putKError(partition.ErrorCode)
+			pe.putEmptyTaggedFieldArray()
+		}
+
+		pe.putEmptyTaggedFieldArray()
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (m *WritableTxnMarkerResult) decode(pd packetDecoder) (err error) {
+	if m.ProducerID, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	m.Topics = make([]WritableTxnMarkerTopicResult, n)
+	for i := range m.Topics {
+		topic := &m.Topics[i]
+		if topic.Name, err = pd.getString(); err != nil {
+			return err
+		}
+
+		p, err := pd.getArrayLength()
+		if err != nil {
+			return err
+		}
+		if p < 0 {
+			return errInvalidArrayLength
+		}
+
+		topic.Partitions = make([]WritableTxnMarkerPartitionResult, p)
+		for j := range topic.Partitions {
+			partition := &topic.Partitions[j]
+			if partition.PartitionIndex, err = pd.getInt32(); err != nil {
+				return err
+			}
+
+			if partition.ErrorCode, err = pd.getKError(); err != nil {
+				return err
+			}
+
+			if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *WriteTxnMarkersResponse) encode(pe packetEncoder) error {
+	if err := pe.putArrayLength(len(r.Markers)); err != nil {
+		return err
+	}
+	for i := range r.Markers {
+		if err := r.Markers[i].encode(pe); err != nil {
+			return err
+		}
+	}
+
+	pe.putEmptyTaggedFieldArray()
+	return nil
+}
+
+func (r *WriteTxnMarkersResponse) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+
+	n, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errInvalidArrayLength
+	}
+
+	r.Markers = make([]WritableTxnMarkerResult, n)
+	for i := range r.Markers {
+		if err := r.Markers[i].decode(pd); err != nil {
+			return err
+		}
+	}
+
+	_, err = pd.getEmptyTaggedFieldArray()
+	return err
+}
+
+func (r *WriteTxnMarkersResponse) key() int16 {
+	return apiKeyWriteTxnMarkers
+}
+
+func (r *WriteTxnMarkersResponse) version() int16 {
+	return r.Version
+}
+
+func (r *WriteTxnMarkersResponse) headerVersion() int16 {
+	if r.Version >= 1 {
+		return 1
+	}
+	return 0
+}
+
+func (r *WriteTxnMarkersResponse) isValidVersion() bool {
+	return r.Version >= 0 && r.Version <= 1
+}
+
+func (r *WriteTxnMarkersResponse) isFlexible() bool {
+	return r.isFlexibleVersion(r.Version)
+}
+
+func (r *WriteTxnMarkersResponse) isFlexibleVersion(version int16) bool {
+	return version >= 1
+}
+
+func (r *WriteTxnMarkersResponse) requiredVersion() KafkaVersion {
+	switch r.Version {
+	case 1:
+		return V2_8_0_0
+	default:
+		return V0_11_0_0
+	}
+}
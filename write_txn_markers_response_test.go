@@ -0,0 +1,49 @@
+//go:build !functional
+
+package sarama
+
+import "testing"
+
+var (
+	writeTxnMarkersResponseV0 = []byte{
+		0, 0, 0, 1, // Markers
+		0, 0, 0, 0, 0, 0, 0, 42, // ProducerID
+		0, 0, 0, 1, // Topics
+		0, 5, 't', 'o', 'p', 'i', 'c', // Name
+		0, 0, 0, 1, // Partitions
+		0, 0, 0, 2, // PartitionIndex
+		0, 0, // ErrorCode
+	}
+
+	writeTxnMarkersResponseV1 = []byte{
+		2,                       // Markers
+		0, 0, 0, 0, 0, 0, 0, 42, // ProducerID
+		2,                          // Topics
+		6, 't', 'o', 'p', 'i', 'c', // Name
+		2,          // Partitions
+		0, 0, 0, 2, // PartitionIndex
+		0, 47, // ErrorCode (INVALID_PRODUCER_EPOCH)
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+		0, // empty tagged fields
+	}
+)
+
+func TestWriteTxnMarkersResponse(t *testing.T) {
+	response := &WriteTxnMarkersResponse{
+		Version: 0,
+		Markers: []WritableTxnMarkerResult{{
+			ProducerID: 42,
+			Topics: []WritableTxnMarkerTopicResult{{
+				Name:       "topic",
+				Partitions: []WritableTxnMarkerPartitionResult{{PartitionIndex: 2}},
+			}},
+		}},
+	}
+	testResponse(t, "v0", response, writeTxnMarkersResponseV0)
+
+	response.Version = 1
+	response.Markers[0].Topics[0].Partitions[0].ErrorCode = ErrInvalidProducerEpoch
+	testResponse(t, "v1", response, writeTxnMarkersResponseV1)
+}